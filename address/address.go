@@ -6,6 +6,7 @@ import (
 	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/bech32"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"sync"
 )
 
 type Address struct {
@@ -207,15 +208,32 @@ func (a Address) EncodeFullBech32m() (string, error) {
 	return bech32.EncodeWithBech32m(hrp, payload)
 }
 
+var (
+	hrpMutex sync.RWMutex
+	hrps     = make(map[types.Network]string)
+)
+
+// RegisterHrp sets the human-readable part of addresses on a custom network, e.g. ckt for a devnet. Addresses with
+// hrp ckb and ckt are always decoded as mainnet and testnet ones.
+func RegisterHrp(network types.Network, hrp string) {
+	hrpMutex.Lock()
+	defer hrpMutex.Unlock()
+	hrps[network] = hrp
+}
+
 func toHrp(network types.Network) (string, error) {
 	switch network {
 	case types.NetworkMain:
 		return "ckb", nil
 	case types.NetworkTest:
 		return "ckt", nil
-	default:
-		return "", errors.New("unknown network")
 	}
+	hrpMutex.RLock()
+	defer hrpMutex.RUnlock()
+	if hrp, ok := hrps[network]; ok {
+		return hrp, nil
+	}
+	return "", errors.New("unknown network")
 }
 
 func fromHrp(hrp string) (types.Network, error) {
@@ -224,7 +242,13 @@ func fromHrp(hrp string) (types.Network, error) {
 		return types.NetworkMain, nil
 	case "ckt":
 		return types.NetworkTest, nil
-	default:
-		return 0, errors.New("unknown hrp")
 	}
+	hrpMutex.RLock()
+	defer hrpMutex.RUnlock()
+	for network, h := range hrps {
+		if h == hrp {
+			return network, nil
+		}
+	}
+	return 0, errors.New("unknown hrp")
 }
@@ -120,6 +120,23 @@ func testFullBech32m(t *testing.T, script *types.Script, network types.Network,
 	assert.Equal(t, a, addr)
 }
 
+func TestRegisterHrp(t *testing.T) {
+	devnet := types.Network(100)
+	a := &Address{
+		Script:  generateScript("9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8", "b39bbc0b3673c7d36450bc14cfcdad2d559c6c64", types.HashTypeType),
+		Network: devnet,
+	}
+	_, err := a.Encode()
+	assert.Error(t, err)
+
+	RegisterHrp(devnet, "ckd")
+	encoded, err := a.Encode()
+	assert.NoError(t, err)
+	decoded, err := Decode(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, a, decoded)
+}
+
 func generateScript(codeHash string, args string, hashType types.ScriptHashType) *types.Script {
 	return &types.Script{
 		CodeHash: types.HexToHash(codeHash),
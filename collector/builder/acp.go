@@ -0,0 +1,199 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/address"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector/handler"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"math/big"
+	"reflect"
+)
+
+// AnyoneCanPayTransactionBuilder pays CKB or SUDT into anyone-can-pay cells. When SudtType is nil, it pays CKB and
+// iterator should return cells without type, otherwise it pays SUDT and iterator should return SUDT cells of SudtType.
+type AnyoneCanPayTransactionBuilder struct {
+	SimpleTransactionBuilder
+	FeeRate uint
+	// FeeEstimator estimates fee when it's set, otherwise fee is charged by FeeRate. It's nil by default, set it to e.g.
+	// NodeFeeEstimator or CycleFeeEstimator to opt in.
+	FeeEstimator FeeEstimator
+	SudtType     *types.Script
+
+	iterator          collector.CellIterator
+	acpCodeHash       types.Hash
+	acpCells          []*types.TransactionInput
+	changeOutputIndex int
+}
+
+func NewAnyoneCanPayTransactionBuilder(network types.Network, iterator collector.CellIterator, sudtType *types.Script) *AnyoneCanPayTransactionBuilder {
+	return &AnyoneCanPayTransactionBuilder{
+		SimpleTransactionBuilder: *NewSimpleTransactionBuilder(network),
+		FeeRate:                  1000,
+		SudtType:                 sudtType,
+
+		iterator:          iterator,
+		acpCodeHash:       systemscript.GetCodeHash(network, systemscript.AnyoneCanPay),
+		changeOutputIndex: -1,
+	}
+}
+
+// AddAnyoneCanPayPayment pays capacity and SUDT into an existing anyone-can-pay cell without its owner's signature.
+// The payment should reach the minimum amounts in lock args, if there are.
+func (r *AnyoneCanPayTransactionBuilder) AddAnyoneCanPayPayment(acpCell *types.TransactionInput, capacity uint64, sudtAmount *big.Int) error {
+	lock := acpCell.Output.Lock
+	if lock == nil || lock.CodeHash != r.acpCodeHash {
+		return errors.New("not an anyone-can-pay cell")
+	}
+	if sudtAmount == nil {
+		sudtAmount = big.NewInt(0)
+	}
+	if sudtAmount.Sign() < 0 {
+		return errors.New("sudt amount should not be negative")
+	}
+	data := acpCell.OutputData
+	if sudtAmount.Sign() > 0 {
+		if r.SudtType == nil || !reflect.DeepEqual(acpCell.Output.Type, r.SudtType) {
+			return errors.New("anyone-can-pay cell's type mismatches sudt type")
+		}
+		amount, err := systemscript.DecodeSudtAmount(data)
+		if err != nil {
+			return err
+		}
+		data = systemscript.EncodeSudtAmount(amount.Add(amount, sudtAmount))
+	}
+	if err := checkAnyoneCanPayMinimum(lock.Args, capacity, sudtAmount); err != nil {
+		return err
+	}
+	r.acpCells = append(r.acpCells, acpCell)
+	r.AddOutput(&types.CellOutput{
+		Capacity: acpCell.Output.Capacity + capacity,
+		Lock:     lock,
+		Type:     acpCell.Output.Type,
+	}, data)
+	return nil
+}
+
+// checkAnyoneCanPayMinimum checks payment against the optional minimum exponents following the 20-byte pubkey hash in
+// args. At least one of CKB and SUDT should be paid enough.
+func checkAnyoneCanPayMinimum(args []byte, capacity uint64, sudtAmount *big.Int) error {
+	if len(args) < 20 || len(args) > 22 {
+		return fmt.Errorf("invalid anyone-can-pay args length %d", len(args))
+	}
+	var ckbMinimum, sudtMinimum *big.Int
+	if len(args) > 20 {
+		ckbMinimum = exponent(args[20])
+	}
+	if len(args) > 21 {
+		sudtMinimum = exponent(args[21])
+	}
+	return checkPaymentMinimum(capacity, sudtAmount, ckbMinimum, sudtMinimum)
+}
+
+// checkPaymentMinimum checks that at least one of CKB and SUDT is paid no less than its minimum, where any positive
+// payment is enough if the minimum is nil
+func checkPaymentMinimum(capacity uint64, sudtAmount *big.Int, ckbMinimum *big.Int, sudtMinimum *big.Int) error {
+	ckbEnough := capacity > 0
+	if ckbMinimum != nil && ckbEnough {
+		ckbEnough = new(big.Int).SetUint64(capacity).Cmp(ckbMinimum) >= 0
+	}
+	sudtEnough := sudtAmount.Sign() > 0
+	if sudtMinimum != nil && sudtEnough {
+		sudtEnough = sudtAmount.Cmp(sudtMinimum) >= 0
+	}
+	if !ckbEnough && !sudtEnough {
+		return errors.New("payment is less than the minimum amount of anyone-can-pay cell")
+	}
+	return nil
+}
+
+// AddAnyoneCanPayOutputByAddress creates a new anyone-can-pay cell. The cell holds zero SUDT of SudtType if it's set.
+// Occupied capacity is used when capacity is 0.
+func (r *AnyoneCanPayTransactionBuilder) AddAnyoneCanPayOutputByAddress(addr string, capacity uint64) (int, error) {
+	a, err := address.Decode(addr)
+	if err != nil {
+		return 0, err
+	}
+	if a.Script.CodeHash != r.acpCodeHash {
+		return 0, errors.New("not an anyone-can-pay address")
+	}
+	output := &types.CellOutput{
+		Capacity: capacity,
+		Lock:     a.Script,
+		Type:     r.SudtType,
+	}
+	data := []byte{}
+	if r.SudtType != nil {
+		data = systemscript.EncodeSudtAmount(big.NewInt(0))
+	}
+	if capacity == 0 {
+		output.Capacity = output.OccupiedCapacity(data)
+	}
+	return r.AddOutput(output, data), nil
+}
+
+func (r *AnyoneCanPayTransactionBuilder) AddChangeOutputByAddress(addr string) error {
+	if r.changeOutputIndex != -1 {
+		return errors.New("change output has been set")
+	}
+	err := r.AddOutputByAddress(addr, 0)
+	if err == nil {
+		r.changeOutputIndex = len(r.Outputs) - 1
+	}
+	return err
+}
+
+func (r *AnyoneCanPayTransactionBuilder) Build(contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	return r.build(context.Background(), contexts...)
+}
+
+// BuildCtx is Build with ctx bound to the queries of iterator, if it implements collector.CellIteratorCtx.
+func (r *AnyoneCanPayTransactionBuilder) BuildCtx(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	defer bindIteratorContext(r.iterator, ctx)()
+	return r.build(ctx, contexts...)
+}
+
+func (r *AnyoneCanPayTransactionBuilder) build(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	if r.changeOutputIndex == -1 {
+		return nil, ErrChangeOutputNotSet
+	}
+	acpIndex := 0
+	balancer := &udtBalancer{
+		builder:           &r.SimpleTransactionBuilder,
+		iterator:          r.iterator,
+		changeOutputIndex: r.changeOutputIndex,
+		feeRate:           r.FeeRate,
+		feeEstimator:      r.FeeEstimator,
+		// The change output receives SUDT change when paying SUDT
+		udtType: r.SudtType,
+		nextInput: func() *udtInput {
+			// consume anyone-can-pay cells at first, which are unlocked without signature
+			if acpIndex < len(r.acpCells) {
+				input := &udtInput{
+					cell:         r.acpCells[acpIndex],
+					lockContexts: []interface{}{&handler.AnyoneCanPayPaymentInfo{}},
+				}
+				acpIndex += 1
+				return input
+			}
+			return newUdtInput(r.getNextCell())
+		},
+	}
+	return balancer.build(ctx, contexts...)
+}
+
+func (r *AnyoneCanPayTransactionBuilder) getNextCell() *types.TransactionInput {
+	for {
+		if !r.iterator.HasNext() {
+			return nil
+		}
+		cell := r.iterator.Next()
+		if reflect.DeepEqual(cell.Output.Type, r.SudtType) {
+			return cell
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package builder
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/address"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func getAcpCell(args []byte, sudtAmount *big.Int) *types.TransactionInput {
+	cell := &types.TransactionInput{
+		OutPoint: &types.OutPoint{
+			TxHash: types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000004"),
+			Index:  0,
+		},
+		Output: &types.CellOutput{
+			Capacity: 14200000000,
+			Lock: &types.Script{
+				CodeHash: systemscript.GetCodeHash(types.NetworkTest, systemscript.AnyoneCanPay),
+				HashType: types.HashTypeType,
+				Args:     args,
+			},
+		},
+		OutputData: []byte{},
+	}
+	if sudtAmount != nil {
+		cell.Output.Type = sudtType
+		cell.OutputData = systemscript.EncodeSudtAmount(sudtAmount)
+	}
+	return cell
+}
+
+func TestAnyoneCanPayTransactionBuilderPayCkb(t *testing.T) {
+	args := common.FromHex("0xaf0b41c627807fbddcee75afa174d5a7e5135ebd09")
+	acpCell := getAcpCell(args, nil)
+	builder := NewAnyoneCanPayTransactionBuilder(types.NetworkTest, getMockIterator(), nil)
+	// minimum is 10^9 shannons
+	assert.Error(t, builder.AddAnyoneCanPayPayment(acpCell, 999999999, nil))
+	assert.NoError(t, builder.AddAnyoneCanPayPayment(acpCell, 1000000000, nil))
+	assert.NoError(t, builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"))
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, len(tx.TxView.Inputs))
+	assert.Equal(t, acpCell.OutPoint, tx.TxView.Inputs[0].PreviousOutput)
+	assert.Equal(t, uint64(15200000000), tx.TxView.Outputs[0].Capacity)
+	// no signature is required for anyone-can-pay cell
+	assert.Equal(t, []byte{}, tx.TxView.Witnesses[0])
+	witnessArgs, err := types.DeserializeWitnessArgs(tx.TxView.Witnesses[1])
+	assert.NoError(t, err)
+	assert.Equal(t, 65, len(witnessArgs.Lock))
+	acpDep := systemscript.GetInfo(types.NetworkTest, systemscript.AnyoneCanPay).OutPoint
+	found := false
+	for _, dep := range tx.TxView.CellDeps {
+		if *dep.OutPoint == *acpDep {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestAnyoneCanPayTransactionBuilderPaySudt(t *testing.T) {
+	args := common.FromHex("0xaf0b41c627807fbddcee75afa174d5a7e5135ebd0901")
+	acpCell := getAcpCell(args, big.NewInt(5))
+	builder := NewAnyoneCanPayTransactionBuilder(types.NetworkTest, getSudtMockIterator(), sudtType)
+	// minimum is 10 SUDT
+	assert.Error(t, builder.AddAnyoneCanPayPayment(acpCell, 0, big.NewInt(9)))
+	assert.NoError(t, builder.AddAnyoneCanPayPayment(acpCell, 0, big.NewInt(10)))
+	assert.NoError(t, builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"))
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, acpCell.Output.Capacity, tx.TxView.Outputs[0].Capacity)
+	assert.Equal(t, systemscript.EncodeSudtAmount(big.NewInt(15)), tx.TxView.OutputsData[0])
+	assert.Equal(t, systemscript.EncodeSudtAmount(big.NewInt(90)), tx.TxView.OutputsData[1])
+	assert.Equal(t, []byte{}, tx.TxView.Witnesses[0])
+}
+
+func TestAnyoneCanPayTransactionBuilderCreateCell(t *testing.T) {
+	owner := &address.Address{
+		Script: &types.Script{
+			CodeHash: systemscript.GetCodeHash(types.NetworkTest, systemscript.AnyoneCanPay),
+			HashType: types.HashTypeType,
+			Args:     common.FromHex("0xaf0b41c627807fbddcee75afa174d5a7e5135ebd"),
+		},
+		Network: types.NetworkTest,
+	}
+	builder := NewAnyoneCanPayTransactionBuilder(types.NetworkTest, getSudtMockIterator(), sudtType)
+	index, err := builder.AddAnyoneCanPayOutputByAddress(encodeAddress(t, owner), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, owner.Script, builder.Outputs[index].Lock)
+	assert.Equal(t, sudtType, builder.Outputs[index].Type)
+	assert.Equal(t, uint64(14200000000), builder.Outputs[index].Capacity)
+
+	_, err = builder.AddAnyoneCanPayOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c", 0)
+	assert.Error(t, err)
+}
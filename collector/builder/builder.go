@@ -1,10 +1,12 @@
 package builder
 
 import (
+	"context"
 	"errors"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/address"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/collector/handler"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
 	"reflect"
@@ -24,18 +26,39 @@ type SimpleTransactionBuilder struct {
 	ScriptHandlers []collector.ScriptHandler
 }
 
+// NewSimpleTransactionBuilder creates builder with handlers of system scripts available on network. It returns nil
+// for networks other than mainnet, testnet and those registered by systemscript.RegisterNetwork.
 func NewSimpleTransactionBuilder(network types.Network) *SimpleTransactionBuilder {
-	if network == types.NetworkMain || network == types.NetworkTest {
-		s := SimpleTransactionBuilder{}
-		s.Register(handler.NewSecp256k1Blake160SighashAllScriptHandler(network))
-		s.Register(handler.NewSecp256k1Blake160MultisigAllScriptHandler(network))
-		s.Register(handler.NewSudtScriptHandler(network))
-		s.Register(handler.NewDaoScriptHandler(network))
-		s.Register(handler.NewOmnilockScriptHandler(network))
-		return &s
-	} else {
+	if !systemscript.IsKnownNetwork(network) {
 		return nil
 	}
+	s := SimpleTransactionBuilder{}
+	// handler constructors return nil if their scripts are not registered for network
+	if h := handler.NewSecp256k1Blake160SighashAllScriptHandler(network); h != nil {
+		s.Register(h)
+	}
+	if h := handler.NewSecp256k1Blake160MultisigAllScriptHandler(network); h != nil {
+		s.Register(h)
+	}
+	if h := handler.NewSudtScriptHandler(network); h != nil {
+		s.Register(h)
+	}
+	if h := handler.NewDaoScriptHandler(network); h != nil {
+		s.Register(h)
+	}
+	if h := handler.NewOmnilockScriptHandler(network); h != nil {
+		s.Register(h)
+	}
+	if h := handler.NewChequeScriptHandler(network); h != nil {
+		s.Register(h)
+	}
+	if h := handler.NewAnyoneCanPayScriptHandler(network); h != nil {
+		s.Register(h)
+	}
+	if h := handler.NewXudtScriptHandler(network); h != nil {
+		s.Register(h)
+	}
+	return &s
 }
 
 func (r *SimpleTransactionBuilder) Register(handler collector.ScriptHandler) {
@@ -68,7 +91,10 @@ func (r *SimpleTransactionBuilder) AddCellDep(cellDep *types.CellDep) int {
 
 func (r *SimpleTransactionBuilder) AddInput(input *types.CellInput) int {
 	r.Inputs = append(r.Inputs, input)
-	r.Witnesses = append(r.Witnesses, []byte{})
+	// witness of this input may have been set for an output
+	if len(r.Witnesses) < len(r.Inputs) {
+		r.Witnesses = append(r.Witnesses, []byte{})
+	}
 	return len(r.Inputs) - 1
 }
 
@@ -108,10 +134,15 @@ func (r *SimpleTransactionBuilder) SetOutputData(index uint, data []byte) error
 	return nil
 }
 
+// SetWitness sets a field of the witness at index. Witnesses are extended with empty ones for an output's witness
+// beyond inputs, e.g. output_type of a type script only in outputs.
 func (r *SimpleTransactionBuilder) SetWitness(index uint, witnessType types.WitnessType, data []byte) error {
-	if index >= uint(len(r.Witnesses)) {
+	if index >= uint(len(r.Witnesses)) && witnessType != types.WitnessTypeOutputType {
 		return errors.New("index " + strconv.Itoa(int(index)) + " out of range")
 	}
+	for index >= uint(len(r.Witnesses)) {
+		r.Witnesses = append(r.Witnesses, []byte{})
+	}
 	var wArgs *types.WitnessArgs
 	var err error
 	w := r.Witnesses[index]
@@ -159,3 +190,25 @@ func (r *SimpleTransactionBuilder) BuildTransaction() *transaction.TransactionWi
 		ScriptGroups: r.scriptGroups,
 	}
 }
+
+// bindIteratorContext binds ctx to iterator if it implements collector.CellIteratorCtx, and returns a function
+// restoring the previous context.
+func bindIteratorContext(iterator collector.CellIterator, ctx context.Context) func() {
+	it, ok := iterator.(collector.CellIteratorCtx)
+	if !ok {
+		return func() {}
+	}
+	previous := it.Context()
+	it.SetContext(ctx)
+	return func() {
+		it.SetContext(previous)
+	}
+}
+
+// iteratorError returns the error stopping iterator, if it implements collector.CellIteratorErr
+func iteratorError(iterator collector.CellIterator) error {
+	if it, ok := iterator.(collector.CellIteratorErr); ok {
+		return it.Err()
+	}
+	return nil
+}
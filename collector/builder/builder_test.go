@@ -1,7 +1,14 @@
 package builder
 
 import (
+	"context"
+	"errors"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/indexer"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction/signer"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -100,3 +107,216 @@ func TestCkbTransactionBuilderMultipleInputs(t *testing.T) {
 	fee := 110000000000 - tx.TxView.Outputs[0].Capacity - tx.TxView.Outputs[1].Capacity
 	assert.Equal(t, uint64(516), fee)
 }
+
+type ctxLiveCellsGetter struct {
+	cells    []*types.TransactionInput
+	contexts []context.Context
+}
+
+func (g *ctxLiveCellsGetter) GetCells(searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.LiveCells, error) {
+	return g.GetCellsCtx(context.Background(), searchKey, order, limit, afterCursor)
+}
+
+func (g *ctxLiveCellsGetter) GetCellsCtx(ctx context.Context, searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.LiveCells, error) {
+	g.contexts = append(g.contexts, ctx)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	liveCells := &indexer.LiveCells{}
+	if afterCursor == "" {
+		for _, c := range g.cells {
+			liveCells.Objects = append(liveCells.Objects, &indexer.LiveCell{OutPoint: c.OutPoint, Output: c.Output, OutputData: c.OutputData})
+		}
+		liveCells.LastCursor = "0x01"
+	}
+	return liveCells, nil
+}
+
+func TestCkbTransactionBuilderBuildCtx(t *testing.T) {
+	type key struct{}
+	newBuilder := func(getter *ctxLiveCellsGetter) (*CkbTransactionBuilder, *collector.LiveCellIterator) {
+		iterator := &collector.LiveCellIterator{
+			LiveCellGetter: getter,
+			SearchOrder:    indexer.SearchOrderAsc,
+			Limit:          100,
+		}
+		builder := NewCkbTransactionBuilder(types.NetworkTest, iterator)
+		builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 100000000000)
+		if err := builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"); err != nil {
+			t.Fatal(err)
+		}
+		return builder, iterator
+	}
+
+	getter := &ctxLiveCellsGetter{cells: getMockIterator().Cells}
+	builder, iterator := newBuilder(getter)
+	ctx := context.WithValue(context.Background(), key{}, "build")
+	tx, err := builder.BuildCtx(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tx.TxView.Inputs))
+	assert.NotEmpty(t, getter.contexts)
+	for _, c := range getter.contexts {
+		assert.Equal(t, "build", c.Value(key{}))
+	}
+	// context is unbound after build
+	assert.Nil(t, iterator.Context())
+
+	getter = &ctxLiveCellsGetter{cells: getMockIterator().Cells}
+	builder, _ = newBuilder(getter)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = builder.BuildCtx(ctx)
+	assert.Equal(t, context.Canceled, err)
+}
+
+// failingLiveCellsGetter returns cells in the first page, and fails on the following pages
+type failingLiveCellsGetter struct {
+	cells []*types.TransactionInput
+	err   error
+}
+
+func (g *failingLiveCellsGetter) GetCells(searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.LiveCells, error) {
+	if afterCursor != "" {
+		return nil, g.err
+	}
+	liveCells := &indexer.LiveCells{LastCursor: "0x01"}
+	for _, c := range g.cells {
+		liveCells.Objects = append(liveCells.Objects, &indexer.LiveCell{OutPoint: c.OutPoint, Output: c.Output, OutputData: c.OutputData})
+	}
+	return liveCells, nil
+}
+
+func TestCkbTransactionBuilderIteratorError(t *testing.T) {
+	getterErr := errors.New("indexer is unavailable")
+	iterator := &collector.LiveCellIterator{
+		LiveCellGetter: &failingLiveCellsGetter{cells: getMockIterator().Cells[:1], err: getterErr},
+		SearchOrder:    indexer.SearchOrderAsc,
+		Limit:          100,
+	}
+	builder := NewCkbTransactionBuilder(types.NetworkTest, iterator)
+	builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 105000000000)
+	if err := builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := builder.Build()
+	assert.Equal(t, getterErr, err)
+	assert.Equal(t, getterErr, iterator.Err())
+
+	offChainIterator := collector.NewOffChainInputIterator(iterator, &collector.OffChainInputCollector{}, false)
+	assert.False(t, offChainIterator.HasNext())
+	assert.Equal(t, getterErr, offChainIterator.Err())
+}
+
+func TestCkbTransactionBuilderAutoChange(t *testing.T) {
+	builder := NewCkbTransactionBuilder(types.NetworkTest, getMockIterator())
+	builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 50100000000)
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tx.TxView.Outputs))
+	assert.Equal(t, lock, tx.TxView.Outputs[1].Lock)
+	assert.Equal(t, uint64(100000000000-50100000000-464), tx.TxView.Outputs[1].Capacity)
+
+	// change doesn't go to the lock of customized input
+	foreign := &types.TransactionInput{
+		OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x01"), Index: 0},
+		Output:   &types.CellOutput{Capacity: 10000000000, Lock: chequeReceiver.Script},
+	}
+	builder = NewCkbTransactionBuilder(types.NetworkTest, getMockIterator())
+	builder.transactionInputs = []*types.TransactionInput{foreign}
+	builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 50100000000)
+	tx, err = builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tx.TxView.Inputs))
+	assert.Equal(t, lock, tx.TxView.Outputs[1].Lock)
+
+	// customized input is enough without any cell of iterator
+	builder = NewCkbTransactionBuilder(types.NetworkTest, getMockIterator())
+	builder.transactionInputs = []*types.TransactionInput{foreign}
+	builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 6100000000)
+	_, err = builder.Build()
+	assert.Equal(t, ErrChangeOutputNotSet, err)
+}
+
+func TestCkbTransactionBuilderDustPolicy(t *testing.T) {
+	build := func(policy DustPolicy, threshold uint64) (*transaction.TransactionWithScriptGroups, error) {
+		builder := NewCkbTransactionBuilder(types.NetworkTest, getMockIterator())
+		builder.DustPolicy = policy
+		builder.DustThreshold = threshold
+		// change of all cells is less than 1 CKB
+		builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 109990000000)
+		if err := builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"); err != nil {
+			t.Fatal(err)
+		}
+		return builder.Build()
+	}
+
+	_, err := build(DustPolicyCollectMore, 0)
+	var capacityErr *InsufficientCapacityError
+	assert.True(t, errors.As(err, &capacityErr))
+	assert.Equal(t, "no enough capacity", err.Error())
+	assert.Equal(t, uint64(110000000000), capacityErr.InputsCapacity)
+
+	_, err = build(DustPolicyReject, 0)
+	var dustErr *DustChangeError
+	assert.True(t, errors.As(err, &dustErr))
+	assert.Equal(t, uint64(6100000000), dustErr.Occupied)
+
+	// the rest cells are collected before rejecting
+	builder := NewCkbTransactionBuilder(types.NetworkTest, getMockIterator())
+	builder.DustPolicy = DustPolicyReject
+	builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 99990000000)
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tx.TxView.Inputs))
+
+	_, err = build(DustPolicyFoldIntoFee, 1000)
+	assert.True(t, errors.As(err, &capacityErr))
+
+	// change is more than the default threshold
+	_, err = build(DustPolicyFoldIntoFee, DefaultDustThreshold/100)
+	assert.True(t, errors.As(err, &capacityErr))
+
+	tx, err = build(DustPolicyFoldIntoFee, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tx.TxView.Inputs))
+	assert.Equal(t, 1, len(tx.TxView.Outputs))
+	assert.Equal(t, uint64(109990000000), tx.TxView.Outputs[0].Capacity)
+}
+
+func TestSimpleTransactionBuilderSetOutputWitness(t *testing.T) {
+	builder := NewSimpleTransactionBuilder(types.NetworkTest)
+	assert.Error(t, builder.SetWitness(1, types.WitnessTypeLock, []byte{1}))
+	// witness of an output is allowed beyond inputs
+	assert.NoError(t, builder.SetWitness(1, types.WitnessTypeOutputType, []byte{1}))
+	assert.Equal(t, 2, len(builder.Witnesses))
+	builder.AddInput(&types.CellInput{})
+	builder.AddInput(&types.CellInput{})
+	builder.AddInput(&types.CellInput{})
+	assert.Equal(t, 3, len(builder.Witnesses))
+	witnessArgs, err := types.DeserializeWitnessArgs(builder.Witnesses[1])
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1}, witnessArgs.OutputType)
+}
+
+func TestCkbTransactionBuilderCustomNetwork(t *testing.T) {
+	devnet := types.Network(100)
+	assert.Nil(t, NewSimpleTransactionBuilder(devnet))
+	secp := *systemscript.GetInfo(types.NetworkTest, systemscript.Secp256k1Blake160SighashAll)
+	secp.OutPoint = &types.OutPoint{
+		TxHash: types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000100"),
+		Index:  0,
+	}
+	systemscript.RegisterNetwork(devnet, map[systemscript.SystemScript]*systemscript.Info{
+		systemscript.Secp256k1Blake160SighashAll: &secp,
+	})
+	defer systemscript.UnregisterNetwork(devnet)
+
+	builder := NewCkbTransactionBuilder(devnet, getMockIterator())
+	// only handlers of registered scripts are available
+	assert.Equal(t, 1, len(builder.ScriptHandlers))
+	builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 50100000000)
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.CellDep{secp.CellDep()}, tx.TxView.CellDeps)
+	assert.NotNil(t, signer.GetTransactionSignerInstance(devnet))
+}
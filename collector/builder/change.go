@@ -0,0 +1,81 @@
+package builder
+
+import (
+	"context"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"math/big"
+)
+
+// ChangeSplitPolicy splits change into Count outputs of similar size, so that later transactions can spend them in
+// parallel. Each output holds at least MinCapacity, or the occupied capacity of change output if it's larger. Change
+// is split into fewer outputs if it can't afford Count ones.
+type ChangeSplitPolicy struct {
+	Count       int
+	MinCapacity uint64
+}
+
+func NewChangeSplitPolicy(count int, minCapacity uint64) *ChangeSplitPolicy {
+	return &ChangeSplitPolicy{
+		Count:       count,
+		MinCapacity: minCapacity,
+	}
+}
+
+// splitChange splits the balanced change output at changeIndex by policy. fee is what's charged with the single change
+// output, and the extra fee of new outputs is charged from change. SUDT change is split as well when sudtChange is
+// not nil.
+func splitChange(ctx context.Context, b *SimpleTransactionBuilder, policy *ChangeSplitPolicy, estimator FeeEstimator, feeRate uint,
+	changeIndex int, fee uint64, sudtChange *big.Int, scriptGroupMap map[types.Hash]*transaction.ScriptGroup) error {
+	change := b.Outputs[changeIndex]
+	changeData := b.OutputsData[changeIndex]
+	minCapacity := change.OccupiedCapacity(changeData)
+	if policy.MinCapacity > minCapacity {
+		minCapacity = policy.MinCapacity
+	}
+	length := len(b.Outputs)
+	for n := policy.Count; n > 1; n-- {
+		for k := 1; k < n; k++ {
+			b.AddOutput(&types.CellOutput{Lock: change.Lock, Type: change.Type}, changeData)
+		}
+		// fee is recalculated with new outputs
+		newFee, err := estimateFee(ctx, estimator, feeRate, b.BuildTransaction().TxView)
+		if err != nil {
+			b.Outputs, b.OutputsData = b.Outputs[:length], b.OutputsData[:length]
+			return err
+		}
+		extra := uint64(0)
+		if newFee > fee {
+			extra = newFee - fee
+		}
+		if change.Capacity < extra || (change.Capacity-extra)/uint64(n) < minCapacity {
+			b.Outputs, b.OutputsData = b.Outputs[:length], b.OutputsData[:length]
+			continue
+		}
+
+		total := change.Capacity - extra
+		share := total / uint64(n)
+		change.Capacity = share + total%uint64(n)
+		for i := length; i < len(b.Outputs); i++ {
+			b.Outputs[i].Capacity = share
+		}
+		if sudtChange != nil {
+			count := big.NewInt(int64(n))
+			amountShare := new(big.Int).Div(sudtChange, count)
+			b.OutputsData[changeIndex] = systemscript.EncodeSudtAmount(new(big.Int).Add(amountShare, new(big.Int).Mod(sudtChange, count)))
+			for i := length; i < len(b.Outputs); i++ {
+				b.OutputsData[i] = systemscript.EncodeSudtAmount(amountShare)
+			}
+		}
+		if change.Type != nil {
+			if group := scriptGroupMap[change.Type.Hash()]; group != nil {
+				for i := length; i < len(b.Outputs); i++ {
+					group.OutputIndices = append(group.OutputIndices, uint32(i))
+				}
+			}
+		}
+		return nil
+	}
+	return nil
+}
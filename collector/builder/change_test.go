@@ -0,0 +1,62 @@
+package builder
+
+import (
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestCkbTransactionBuilderChangeSplit(t *testing.T) {
+	build := func(policy *ChangeSplitPolicy) *types.Transaction {
+		builder := NewCkbTransactionBuilder(types.NetworkTest, getMockIterator())
+		builder.ChangeSplit = policy
+		builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 10000000000)
+		if err := builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"); err != nil {
+			t.Fatal(err)
+		}
+		tx, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx.TxView
+	}
+
+	tx := build(NewChangeSplitPolicy(3, 0))
+	assert.Equal(t, 4, len(tx.Outputs))
+	assert.Equal(t, uint64(100000000000), tx.OutputsCapacity()+tx.CalculateFee(1000))
+	for i := 2; i < 4; i++ {
+		assert.Equal(t, tx.Outputs[1].Lock, tx.Outputs[i].Lock)
+		assert.InDelta(t, tx.Outputs[1].Capacity, tx.Outputs[i].Capacity, 2)
+	}
+
+	// change can't afford 3 outputs of 400 CKB
+	tx = build(NewChangeSplitPolicy(3, 40000000000))
+	assert.Equal(t, 3, len(tx.Outputs))
+	assert.Equal(t, uint64(100000000000), tx.OutputsCapacity()+tx.CalculateFee(1000))
+}
+
+func TestSudtTransactionBuilderChangeSplit(t *testing.T) {
+	builder := NewSudtTransactionBuilderFromSudtArgs(types.NetworkTest, getSudtMockIterator(), SudtTransactionTypeTransfer, sudtArgs)
+	builder.ChangeSplit = NewChangeSplitPolicy(2, 0)
+	if _, err := builder.AddSudtOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsqdamwzrffgc54ef48493nfd2sd0h4cjnxg4850up", big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsqdamwzrffgc54ef48493nfd2sd0h4cjnxg4850up"); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, len(tx.TxView.Outputs))
+	assert.Equal(t, builder.SudtType, tx.TxView.Outputs[2].Type)
+	assert.Equal(t, systemscript.EncodeSudtAmount(big.NewInt(50)), tx.TxView.OutputsData[1])
+	assert.Equal(t, systemscript.EncodeSudtAmount(big.NewInt(49)), tx.TxView.OutputsData[2])
+	assert.Equal(t, uint64(100000000000), tx.TxView.OutputsCapacity()+tx.TxView.CalculateFee(1000))
+	for _, group := range tx.ScriptGroups {
+		if group.GroupType == types.ScriptTypeType {
+			assert.Equal(t, []uint32{0, 1, 2}, group.OutputIndices)
+		}
+	}
+}
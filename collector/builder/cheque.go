@@ -0,0 +1,251 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector/handler"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"math/big"
+	"reflect"
+)
+
+type ChequeTransactionType uint
+
+const (
+	ChequeTransactionTypeDeposit ChequeTransactionType = iota
+	ChequeTransactionTypeClaim
+	ChequeTransactionTypeWithdraw
+)
+
+// ChequeWithdrawSince is the since of cheque inputs when sender withdraws, which is a relative epoch
+var ChequeWithdrawSince = uint64(0xA000000000000000) | handler.ChequeLockPeriodEpochs
+
+type ChequeTransactionBuilder struct {
+	SimpleTransactionBuilder
+	FeeRate uint
+	// FeeEstimator estimates fee when it's set, otherwise fee is charged by FeeRate. It's nil by default, set it to e.g.
+	// NodeFeeEstimator or CycleFeeEstimator to opt in.
+	FeeEstimator FeeEstimator
+	SudtType     *types.Script
+	// UnlockByLockHash unlocks cheque cells by an input locked by receiver (for claim) or sender (for withdraw)
+	// instead of signature. Cells returned by iterator should be locked by that lock.
+	UnlockByLockHash bool
+
+	iterator          collector.CellIterator
+	chequeCodeHash    types.Hash
+	chequeCells       []*types.TransactionInput
+	changeOutputIndex int
+	transactionType   ChequeTransactionType
+}
+
+// NewChequeTransactionBuilder creates a builder for cheque cells of sudtType. In deposit transaction, iterator
+// should return sender's SUDT cells, otherwise it should return cells for fee and change.
+func NewChequeTransactionBuilder(network types.Network, iterator collector.CellIterator,
+	transactionType ChequeTransactionType, sudtType *types.Script) *ChequeTransactionBuilder {
+	return &ChequeTransactionBuilder{
+		SimpleTransactionBuilder: *NewSimpleTransactionBuilder(network),
+		FeeRate:                  1000,
+		SudtType:                 sudtType,
+
+		iterator:          iterator,
+		chequeCodeHash:    systemscript.GetCodeHash(network, systemscript.Cheque),
+		changeOutputIndex: -1,
+		transactionType:   transactionType,
+	}
+}
+
+// AddChequeOutput adds a cheque cell holding SUDT of amount, which can be claimed by receiver.
+func (r *ChequeTransactionBuilder) AddChequeOutput(senderLock, receiverLock *types.Script, amount *big.Int) (int, error) {
+	if r.transactionType != ChequeTransactionTypeDeposit {
+		return 0, errors.New("cheque output can only be added in deposit transaction")
+	}
+	output := &types.CellOutput{
+		Lock: &types.Script{
+			CodeHash: r.chequeCodeHash,
+			HashType: types.HashTypeType,
+			Args:     systemscript.ChequeArgs(senderLock, receiverLock),
+		},
+		Type: r.SudtType,
+	}
+	data := systemscript.EncodeSudtAmount(amount)
+	output.Capacity = output.OccupiedCapacity(data)
+	return r.AddOutput(output, data), nil
+}
+
+// ClaimCheque consumes cheque cell, sends its SUDT to receiver and returns its capacity to sender, as cheque script
+// requires.
+func (r *ChequeTransactionBuilder) ClaimCheque(chequeCell *types.TransactionInput, senderLock, receiverLock *types.Script) error {
+	if r.transactionType != ChequeTransactionTypeClaim {
+		return errors.New("cheque can only be claimed in claim transaction")
+	}
+	if err := r.checkChequeCell(chequeCell, senderLock, receiverLock); err != nil {
+		return err
+	}
+	r.chequeCells = append(r.chequeCells, chequeCell)
+	receiverOutput := &types.CellOutput{
+		Lock: receiverLock,
+		Type: r.SudtType,
+	}
+	receiverOutput.Capacity = receiverOutput.OccupiedCapacity(chequeCell.OutputData)
+	r.AddOutput(receiverOutput, chequeCell.OutputData)
+	r.AddOutput(&types.CellOutput{
+		Capacity: chequeCell.Output.Capacity,
+		Lock:     senderLock,
+	}, []byte{})
+	return nil
+}
+
+// WithdrawCheque consumes cheque cell and returns its SUDT and capacity to sender. Cheque cell can be withdrawn
+// ChequeLockPeriodEpochs epochs after it's committed.
+func (r *ChequeTransactionBuilder) WithdrawCheque(chequeCell *types.TransactionInput, senderLock *types.Script) error {
+	if r.transactionType != ChequeTransactionTypeWithdraw {
+		return errors.New("cheque can only be withdrawn in withdraw transaction")
+	}
+	if err := r.checkChequeCell(chequeCell, senderLock, nil); err != nil {
+		return err
+	}
+	r.chequeCells = append(r.chequeCells, chequeCell)
+	r.AddOutput(&types.CellOutput{
+		Capacity: chequeCell.Output.Capacity,
+		Lock:     senderLock,
+		Type:     r.SudtType,
+	}, chequeCell.OutputData)
+	return nil
+}
+
+func (r *ChequeTransactionBuilder) checkChequeCell(chequeCell *types.TransactionInput, senderLock, receiverLock *types.Script) error {
+	lock := chequeCell.Output.Lock
+	if lock == nil || lock.CodeHash != r.chequeCodeHash || len(lock.Args) != 40 {
+		return errors.New("not a cheque cell")
+	}
+	if !reflect.DeepEqual(chequeCell.Output.Type, r.SudtType) {
+		return errors.New("cheque cell's type mismatches sudt type")
+	}
+	if receiverLock != nil {
+		hash := receiverLock.Hash()
+		if !bytes.Equal(lock.Args[0:20], hash[0:20]) {
+			return errors.New("receiver lock mismatches cheque args")
+		}
+	}
+	hash := senderLock.Hash()
+	if !bytes.Equal(lock.Args[20:40], hash[0:20]) {
+		return errors.New("sender lock mismatches cheque args")
+	}
+	return nil
+}
+
+func (r *ChequeTransactionBuilder) AddChangeOutputByAddress(addr string) error {
+	if r.changeOutputIndex != -1 {
+		return errors.New("change output has been set")
+	}
+	err := r.AddOutputByAddress(addr, 0)
+	if err == nil {
+		r.changeOutputIndex = len(r.Outputs) - 1
+	}
+	return err
+}
+
+func (r *ChequeTransactionBuilder) Build(contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	return r.build(context.Background(), contexts...)
+}
+
+// BuildCtx is Build with ctx bound to the queries of iterator, if it implements collector.CellIteratorCtx.
+func (r *ChequeTransactionBuilder) BuildCtx(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	defer bindIteratorContext(r.iterator, ctx)()
+	return r.build(ctx, contexts...)
+}
+
+func (r *ChequeTransactionBuilder) build(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	if r.SudtType == nil {
+		return nil, errors.New("sudt type is not set")
+	}
+	if r.changeOutputIndex == -1 {
+		return nil, ErrChangeOutputNotSet
+	}
+	if r.transactionType != ChequeTransactionTypeDeposit && len(r.chequeCells) == 0 {
+		return nil, errors.New("no cheque cell to claim or withdraw")
+	}
+	contexts = append(contexts, &handler.ChequeUnlockInfo{UnlockByLockHash: r.UnlockByLockHash})
+
+	var (
+		inputLockHashes = make([][]byte, 0)
+		chequeIndex     = 0
+	)
+	balancer := &udtBalancer{
+		builder:           &r.SimpleTransactionBuilder,
+		iterator:          r.iterator,
+		changeOutputIndex: r.changeOutputIndex,
+		feeRate:           r.FeeRate,
+		feeEstimator:      r.FeeEstimator,
+		nextInput: func() *udtInput {
+			// consume cheque cells at first
+			if chequeIndex < len(r.chequeCells) {
+				input := &udtInput{cell: r.chequeCells[chequeIndex]}
+				chequeIndex += 1
+				if r.transactionType == ChequeTransactionTypeWithdraw {
+					input.since = ChequeWithdrawSince
+				}
+				return input
+			}
+			cell := r.getNextCell()
+			if cell == nil {
+				return nil
+			}
+			hash := cell.Output.Lock.Hash()
+			inputLockHashes = append(inputLockHashes, hash[0:20])
+			return newUdtInput(cell)
+		},
+		// cheque cells can't be unlocked without signature until there is an input with expected lock
+		ready: func() bool {
+			return !r.UnlockByLockHash || r.transactionType == ChequeTransactionTypeDeposit || r.isUnlockedByLockHash(inputLockHashes)
+		},
+	}
+	// The change output receives SUDT change in deposit transaction
+	if r.transactionType == ChequeTransactionTypeDeposit {
+		balancer.udtType = r.SudtType
+	}
+	return balancer.build(ctx, contexts...)
+}
+
+// isUnlockedByLockHash checks whether every cheque cell has an input with receiver's lock (for claim) or
+// sender's lock (for withdraw).
+func (r *ChequeTransactionBuilder) isUnlockedByLockHash(inputLockHashes [][]byte) bool {
+	for _, cell := range r.chequeCells {
+		expected := cell.Output.Lock.Args[0:20]
+		if r.transactionType == ChequeTransactionTypeWithdraw {
+			expected = cell.Output.Lock.Args[20:40]
+		}
+		found := false
+		for _, hash := range inputLockHashes {
+			if bytes.Equal(hash, expected) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *ChequeTransactionBuilder) getNextCell() *types.TransactionInput {
+	for {
+		if !r.iterator.HasNext() {
+			return nil
+		}
+		cell := r.iterator.Next()
+		if r.transactionType == ChequeTransactionTypeDeposit {
+			// filter cell that has SUDT type
+			if reflect.DeepEqual(cell.Output.Type, r.SudtType) {
+				return cell
+			}
+		} else if cell.Output.Type == nil {
+			return cell
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package builder
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/address"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/secp256k1"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction/signer"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+var (
+	chequeReceiverKey, _ = secp256k1.HexToKey("9d8ca87d75d150692211fa62b0d30de4d1ee6c530d5678b40b8cedacf0750d0f")
+	chequeReceiver       = &address.Address{
+		Script: &types.Script{
+			CodeHash: systemscript.GetCodeHash(types.NetworkTest, systemscript.Secp256k1Blake160SighashAll),
+			HashType: types.HashTypeType,
+			Args:     common.FromHex("0xaf0b41c627807fbddcee75afa174d5a7e5135ebd"),
+		},
+		Network: types.NetworkTest,
+	}
+)
+
+func encodeAddress(t *testing.T, a *address.Address) string {
+	encoded, err := a.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+func getChequeCell(senderLock, receiverLock *types.Script) *types.TransactionInput {
+	return &types.TransactionInput{
+		OutPoint: &types.OutPoint{
+			TxHash: types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000003"),
+			Index:  0,
+		},
+		Output: &types.CellOutput{
+			Capacity: 16200000000,
+			Lock: &types.Script{
+				CodeHash: systemscript.GetCodeHash(types.NetworkTest, systemscript.Cheque),
+				HashType: types.HashTypeType,
+				Args:     systemscript.ChequeArgs(senderLock, receiverLock),
+			},
+			Type: sudtType,
+		},
+		OutputData: systemscript.EncodeSudtAmount(big.NewInt(10)),
+	}
+}
+
+func TestChequeTransactionBuilderDeposit(t *testing.T) {
+	builder := NewChequeTransactionBuilder(types.NetworkTest, getSudtMockIterator(), ChequeTransactionTypeDeposit, sudtType)
+	_, err := builder.AddChequeOutput(sudtSender.Script, chequeReceiver.Script, big.NewInt(10))
+	assert.NoError(t, err)
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, sudtSender)))
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(tx.TxView.Inputs))
+	assert.Equal(t, systemscript.ChequeArgs(sudtSender.Script, chequeReceiver.Script), tx.TxView.Outputs[0].Lock.Args)
+	assert.Equal(t, systemscript.EncodeSudtAmount(big.NewInt(10)), tx.TxView.OutputsData[0])
+	assert.Equal(t, systemscript.EncodeSudtAmount(big.NewInt(90)), tx.TxView.OutputsData[1])
+
+	builder = NewChequeTransactionBuilder(types.NetworkTest, getSudtMockIterator(), ChequeTransactionTypeClaim, sudtType)
+	_, err = builder.AddChequeOutput(sudtSender.Script, chequeReceiver.Script, big.NewInt(10))
+	assert.Error(t, err)
+}
+
+func TestChequeTransactionBuilderClaim(t *testing.T) {
+	chequeCell := getChequeCell(sudtSender.Script, chequeReceiver.Script)
+	iterator := getMockIterator()
+	for _, cell := range iterator.Cells {
+		cell.Output.Lock = chequeReceiver.Script
+	}
+	builder := NewChequeTransactionBuilder(types.NetworkTest, iterator, ChequeTransactionTypeClaim, sudtType)
+	assert.Error(t, builder.ClaimCheque(chequeCell, chequeReceiver.Script, sudtSender.Script))
+	assert.NoError(t, builder.ClaimCheque(chequeCell, sudtSender.Script, chequeReceiver.Script))
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, chequeReceiver)))
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, len(tx.TxView.Inputs))
+	assert.Equal(t, uint64(0), tx.TxView.Inputs[0].Since)
+	assert.Equal(t, chequeReceiver.Script, tx.TxView.Outputs[0].Lock)
+	assert.Equal(t, chequeCell.OutputData, tx.TxView.OutputsData[0])
+	assert.Equal(t, sudtSender.Script, tx.TxView.Outputs[1].Lock)
+	assert.Equal(t, chequeCell.Output.Capacity, tx.TxView.Outputs[1].Capacity)
+
+	signed, err := signer.GetTransactionSignerInstance(types.NetworkTest).SignTransaction(tx, &transaction.Context{Key: chequeReceiverKey})
+	assert.NoError(t, err)
+	// both cheque cell and receiver's cell are signed
+	assert.Equal(t, 2, len(signed))
+	witnessArgs, err := types.DeserializeWitnessArgs(tx.TxView.Witnesses[0])
+	assert.NoError(t, err)
+	assert.Equal(t, 65, len(witnessArgs.Lock))
+	assert.NotEqual(t, make([]byte, 65), witnessArgs.Lock)
+}
+
+func TestChequeTransactionBuilderClaimByLockHash(t *testing.T) {
+	chequeCell := getChequeCell(sudtSender.Script, chequeReceiver.Script)
+	builder := NewChequeTransactionBuilder(types.NetworkTest, getMockIterator(), ChequeTransactionTypeClaim, sudtType)
+	builder.UnlockByLockHash = true
+	assert.NoError(t, builder.ClaimCheque(chequeCell, sudtSender.Script, chequeReceiver.Script))
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, chequeReceiver)))
+	// cells of iterator are not locked by receiver
+	_, err := builder.Build()
+	assert.Error(t, err)
+
+	iterator := getMockIterator()
+	for _, cell := range iterator.Cells {
+		cell.Output.Lock = chequeReceiver.Script
+	}
+	builder = NewChequeTransactionBuilder(types.NetworkTest, iterator, ChequeTransactionTypeClaim, sudtType)
+	builder.UnlockByLockHash = true
+	assert.NoError(t, builder.ClaimCheque(chequeCell, sudtSender.Script, chequeReceiver.Script))
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, chequeReceiver)))
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{}, tx.TxView.Witnesses[0])
+}
+
+func TestChequeTransactionBuilderWithdraw(t *testing.T) {
+	chequeCell := getChequeCell(sudtSender.Script, chequeReceiver.Script)
+	builder := NewChequeTransactionBuilder(types.NetworkTest, getMockIterator(), ChequeTransactionTypeWithdraw, sudtType)
+	assert.NoError(t, builder.WithdrawCheque(chequeCell, sudtSender.Script))
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, sudtSender)))
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(0xA000000000000006), tx.TxView.Inputs[0].Since)
+	assert.Equal(t, uint64(0), tx.TxView.Inputs[1].Since)
+	assert.Equal(t, sudtSender.Script, tx.TxView.Outputs[0].Lock)
+	assert.Equal(t, sudtType, tx.TxView.Outputs[0].Type)
+	assert.Equal(t, chequeCell.Output.Capacity, tx.TxView.Outputs[0].Capacity)
+}
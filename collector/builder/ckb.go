@@ -1,21 +1,61 @@
 package builder
 
 import (
+	"context"
 	"errors"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/address"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/collector/handler"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/rpc"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
 )
 
+// DustPolicy decides what builder does when change is less than the occupied capacity of change output, which is
+// 61 CKB for a secp256k1 lock.
+type DustPolicy uint8
+
+const (
+	// DustPolicyCollectMore collects more cells until change can afford its output
+	DustPolicyCollectMore DustPolicy = iota
+	// DustPolicyFoldIntoFee drops change output and leaves change as fee if it's at most DustThreshold
+	DustPolicyFoldIntoFee
+	// DustPolicyReject collects more cells like DustPolicyCollectMore, but returns DustChangeError if change still can't
+	// afford its output after all cells are collected
+	DustPolicyReject
+)
+
+// DefaultDustThreshold is the default maximum change folded into fee by DustPolicyFoldIntoFee, which is 1 CKB
+const DefaultDustThreshold uint64 = 100000000
+
+// CkbTransactionBuilder collects cells without type from iterator for outputs and fee. When no change output is set,
+// change goes to the lock of the first cell from iterator. Customized inputs, e.g. cells of others, are never used to
+// derive change output, and ErrChangeOutputNotSet is returned if they are enough without any cell from iterator.
 type CkbTransactionBuilder struct {
 	SimpleTransactionBuilder
 	FeeRate uint
+	// FeeEstimator estimates fee when it's set, otherwise fee is charged by FeeRate. It's NodeFeeEstimator if builder
+	// is created with a client, and nil otherwise.
+	FeeEstimator FeeEstimator
+	// CoinSelector selects cells from cells of iterator when it's set, otherwise cells are taken in iterator order
+	CoinSelector CoinSelector
+	// MaxCandidates is the maximum number of cells collected for CoinSelector, which is DefaultMaxCandidates by
+	// default, and 0 means no limit
+	MaxCandidates int
+	DustPolicy    DustPolicy
+	// DustThreshold is the maximum change folded into fee by DustPolicyFoldIntoFee, which is DefaultDustThreshold by
+	// default, and 0 means no limit
+	DustThreshold uint64
+	// ChangeSplit splits change into several outputs when it's set
+	ChangeSplit *ChangeSplitPolicy
 
 	iterator               collector.CellIterator
 	transactionInputs      []*types.TransactionInput // customized inputs
 	transactionInputsIndex int
+	selectedCells          []*types.TransactionInput // selected cells followed by the rest of candidates
+	selectedCount          int
+	selectedIndex          int
+	noChange               bool
 
 	changeOutputIndex int
 	reward            uint64
@@ -25,12 +65,22 @@ func NewCkbTransactionBuilder(network types.Network, iterator collector.CellIter
 	return &CkbTransactionBuilder{
 		SimpleTransactionBuilder: *NewSimpleTransactionBuilder(network),
 		FeeRate:                  1000,
+		DustThreshold:            DefaultDustThreshold,
+		MaxCandidates:            DefaultMaxCandidates,
 
 		iterator:          iterator,
 		changeOutputIndex: -1,
 	}
 }
 
+// NewCkbTransactionBuilderWithClient creates a builder which charges fee with NodeFeeEstimator of client, so that fee
+// follows mempool conditions
+func NewCkbTransactionBuilderWithClient(network types.Network, iterator collector.CellIterator, client rpc.Client) *CkbTransactionBuilder {
+	builder := NewCkbTransactionBuilder(network, iterator)
+	builder.FeeEstimator = NewNodeFeeEstimator(client, 0)
+	return builder
+}
+
 func (r *CkbTransactionBuilder) AddChangeOutputByAddress(addr string) error {
 	if r.changeOutputIndex != -1 {
 		return errors.New("change output has been set")
@@ -86,6 +136,16 @@ func executeHandlers(builder *SimpleTransactionBuilder, group *transaction.Scrip
 }
 
 func (r *CkbTransactionBuilder) Build(contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	return r.build(context.Background(), contexts...)
+}
+
+// BuildCtx is Build with ctx bound to the queries of iterator, if it implements collector.CellIteratorCtx.
+func (r *CkbTransactionBuilder) BuildCtx(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	defer bindIteratorContext(r.iterator, ctx)()
+	return r.build(ctx, contexts...)
+}
+
+func (r *CkbTransactionBuilder) build(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
 	var (
 		err             error
 		script          *types.Script
@@ -107,16 +167,31 @@ func (r *CkbTransactionBuilder) Build(contexts ...interface{}) (*transaction.Tra
 		}
 	}
 
+	if r.CoinSelector != nil {
+		if err := r.selectCells(ctx, outputsCapacity); err != nil {
+			return nil, err
+		}
+	}
+
 	var (
 		enoughCapacity = false
 		inputsCapacity = uint64(0)
+		required       = outputsCapacity
+		dustErr        *DustChangeError
 		i              = -1
 	)
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		customized := r.transactionInputsIndex < len(r.transactionInputs)
 		cell := r.getNextCell()
 		if cell == nil {
 			break // break when can't find cell
 		}
+		if !customized {
+			r.deriveChangeOutput(cell)
+		}
 		r.AddInput(&types.CellInput{
 			Since:          0,
 			PreviousOutput: cell.OutPoint,
@@ -148,23 +223,89 @@ func (r *CkbTransactionBuilder) Build(contexts ...interface{}) (*transaction.Tra
 		}
 
 		inputsCapacity += cell.Output.Capacity
+		// consume all selected cells before checking capacity
+		if r.selectedIndex < r.selectedCount {
+			continue
+		}
+		if r.noChange {
+			// try only once, the excess grows with more cells
+			r.noChange = false
+			enough, err := r.dropChangeOutput(ctx, inputsCapacity+r.reward, outputsCapacity, scriptGroupMap)
+			if err != nil {
+				return nil, err
+			}
+			if enough {
+				enoughCapacity = true
+				break
+			}
+		}
 		tx := r.BuildTransaction().TxView
 		// check if there is enough capacity for output capacity and change
-		fee := tx.CalculateFee(uint64(r.FeeRate))
-		if (inputsCapacity + r.reward) < (outputsCapacity + fee) {
+		fee, err := estimateFee(ctx, r.FeeEstimator, r.FeeRate, tx)
+		if err != nil {
+			return nil, err
+		}
+		required = outputsCapacity + fee
+		if (inputsCapacity + r.reward) < required {
 			continue
 		}
-		changeCapacity := inputsCapacity + r.reward - outputsCapacity - fee
+		if r.changeOutputIndex == -1 {
+			return nil, ErrChangeOutputNotSet
+		}
+		changeCapacity := inputsCapacity + r.reward - required
 		changeOutput := r.Outputs[r.changeOutputIndex]
 		changeOutputData := r.OutputsData[r.changeOutputIndex]
-		if changeCapacity >= changeOutput.OccupiedCapacity(changeOutputData) {
+		occupied := changeOutput.OccupiedCapacity(changeOutputData)
+		if changeCapacity >= occupied {
 			changeOutput.Capacity = changeCapacity
+			// re-balance change, or collect more cells if change can't afford fee by weight
+			charged, err := estimateFeeByWeight(ctx, r.FeeEstimator, &r.SimpleTransactionBuilder, scriptGroupMap, fee)
+			if err != nil {
+				return nil, err
+			}
+			if charged > fee {
+				extra := charged - fee
+				if changeCapacity < extra || changeCapacity-extra < occupied {
+					continue
+				}
+				changeOutput.Capacity = changeCapacity - extra
+			}
+			if r.ChangeSplit != nil {
+				if err := splitChange(ctx, &r.SimpleTransactionBuilder, r.ChangeSplit, r.FeeEstimator, r.FeeRate, r.changeOutputIndex, charged, nil, scriptGroupMap); err != nil {
+					return nil, err
+				}
+			}
 			enoughCapacity = true
 			break
 		}
+		// change can't afford its output
+		if r.DustPolicy == DustPolicyReject {
+			dustErr = &DustChangeError{Change: changeCapacity, Occupied: occupied}
+			continue
+		}
+		if r.DustPolicy == DustPolicyFoldIntoFee && (r.DustThreshold == 0 || changeCapacity <= r.DustThreshold) {
+			enough, err := r.dropChangeOutput(ctx, inputsCapacity+r.reward, outputsCapacity, scriptGroupMap)
+			if err != nil {
+				return nil, err
+			}
+			if enough {
+				enoughCapacity = true
+				break
+			}
+		}
+	}
+	// iterator stops when its query is cancelled or fails
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := iteratorError(r.iterator); err != nil {
+		return nil, err
+	}
+	if !enoughCapacity && dustErr != nil {
+		return nil, dustErr
 	}
 	if !enoughCapacity {
-		return nil, errors.New("no enough capacity")
+		return nil, &InsufficientCapacityError{InputsCapacity: inputsCapacity + r.reward, Required: required}
 	}
 	r.scriptGroups = make([]*transaction.ScriptGroup, 0)
 	for _, v := range scriptGroupMap {
@@ -173,6 +314,143 @@ func (r *CkbTransactionBuilder) Build(contexts ...interface{}) (*transaction.Tra
 	return r.BuildTransaction(), nil
 }
 
+// deriveChangeOutput adds change output with the lock of cell from iterator if change output is not set
+func (r *CkbTransactionBuilder) deriveChangeOutput(cell *types.TransactionInput) {
+	if r.changeOutputIndex == -1 && cell.Output.Lock != nil {
+		r.changeOutputIndex = r.AddOutput(&types.CellOutput{Lock: cell.Output.Lock}, []byte{})
+	}
+}
+
+func (r *CkbTransactionBuilder) getFeeRate(ctx context.Context) (uint64, error) {
+	if provider, ok := r.FeeEstimator.(FeeRateProvider); ok {
+		return provider.FeeRate(ctx)
+	}
+	return uint64(r.FeeRate), nil
+}
+
+// selectCells collects cells without type from iterator as candidates, and selects them by CoinSelector. Collecting
+// stops at MaxCandidates, or once StreamingCoinSelector has enough.
+func (r *CkbTransactionBuilder) selectCells(ctx context.Context, outputsCapacity uint64) error {
+	feeRate, err := r.getFeeRate(ctx)
+	if err != nil {
+		return err
+	}
+	var (
+		candidates   []*types.TransactionInput
+		target       *CoinSelectionTarget
+		streaming, _ = r.CoinSelector.(StreamingCoinSelector)
+	)
+	for r.iterator.HasNext() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		cell := r.iterator.Next()
+		if cell.Output.Type != nil {
+			continue
+		}
+		if target == nil {
+			// change output is derived before selection, so that its cost is counted
+			r.deriveChangeOutput(cell)
+			target = r.coinSelectionTarget(outputsCapacity, feeRate)
+		}
+		candidates = append(candidates, cell)
+		if r.MaxCandidates > 0 && len(candidates) >= r.MaxCandidates {
+			break
+		}
+		if streaming != nil && len(candidates)%candidatePageSize == 0 && streaming.Enough(candidates, target) {
+			break
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := iteratorError(r.iterator); err != nil {
+		return err
+	}
+	if target == nil {
+		target = r.coinSelectionTarget(outputsCapacity, feeRate)
+	}
+
+	selection, err := r.CoinSelector.SelectCells(candidates, target)
+	if err != nil {
+		return err
+	}
+	selected := make(map[*types.TransactionInput]bool)
+	for _, cell := range selection.Cells {
+		selected[cell] = true
+	}
+	r.selectedCells = append([]*types.TransactionInput{}, selection.Cells...)
+	r.selectedCount = len(selection.Cells)
+	for _, cell := range candidates {
+		if !selected[cell] {
+			r.selectedCells = append(r.selectedCells, cell)
+		}
+	}
+	r.noChange = selection.NoChange && r.changeOutputIndex != -1
+	return nil
+}
+
+// coinSelectionTarget estimates fee by size, including customized inputs and the lock witness placeholder
+func (r *CkbTransactionBuilder) coinSelectionTarget(outputsCapacity uint64, feeRate uint64) *CoinSelectionTarget {
+	tx := r.BuildTransaction().TxView
+	extraSize := lockWitnessPlaceholderSize + inputSize*uint64(len(r.transactionInputs))
+	fee := calculateFee(tx.SizeInBlock()+extraSize, feeRate)
+	feeWithoutChange := fee
+	changeCost := uint64(0)
+	if r.changeOutputIndex != -1 {
+		feeWithoutChange = calculateFee(withoutOutput(tx, r.changeOutputIndex).SizeInBlock()+extraSize, feeRate)
+		changeCost = r.Outputs[r.changeOutputIndex].OccupiedCapacity(r.OutputsData[r.changeOutputIndex]) + fee - feeWithoutChange
+	}
+	provided := r.reward
+	for _, input := range r.transactionInputs {
+		provided += input.Output.Capacity
+	}
+	target := &CoinSelectionTarget{
+		InputFee:   calculateFee(inputSize, feeRate),
+		ChangeCost: changeCost,
+		Inputs:     len(r.transactionInputs),
+	}
+	if outputsCapacity+feeWithoutChange > provided {
+		target.Capacity = outputsCapacity + feeWithoutChange - provided
+	}
+	return target
+}
+
+// dropChangeOutput removes change output if inputs are enough for outputs and fee without it, the excess is left as
+// fee.
+func (r *CkbTransactionBuilder) dropChangeOutput(ctx context.Context, inputsCapacity uint64, outputsCapacity uint64, scriptGroupMap map[types.Hash]*transaction.ScriptGroup) (bool, error) {
+	index := r.changeOutputIndex
+	outputs, outputsData := r.Outputs, r.OutputsData
+	changeCapacity := outputs[index].Capacity
+	r.Outputs = append(append([]*types.CellOutput{}, outputs[:index]...), outputs[index+1:]...)
+	r.OutputsData = append(append([][]byte{}, outputsData[:index]...), outputsData[index+1:]...)
+
+	fee, err := estimateFee(ctx, r.FeeEstimator, r.FeeRate, r.BuildTransaction().TxView)
+	if err != nil {
+		return false, err
+	}
+	if fee, err = estimateFeeByWeight(ctx, r.FeeEstimator, &r.SimpleTransactionBuilder, scriptGroupMap, fee); err != nil {
+		return false, err
+	}
+	if inputsCapacity < outputsCapacity-changeCapacity+fee {
+		r.Outputs, r.OutputsData = outputs, outputsData
+		return false, nil
+	}
+	for _, group := range scriptGroupMap {
+		indices := make([]uint32, 0, len(group.OutputIndices))
+		for _, i := range group.OutputIndices {
+			if i > uint32(index) {
+				indices = append(indices, i-1)
+			} else if i < uint32(index) {
+				indices = append(indices, i)
+			}
+		}
+		group.OutputIndices = indices
+	}
+	r.changeOutputIndex = -1
+	return true, nil
+}
+
 func (r *CkbTransactionBuilder) getNextCell() *types.TransactionInput {
 	// consume customized inputs at first
 	if r.transactionInputsIndex < len(r.transactionInputs) {
@@ -180,6 +458,12 @@ func (r *CkbTransactionBuilder) getNextCell() *types.TransactionInput {
 		r.transactionInputsIndex += 1
 		return t
 	}
+	// consume cells of CoinSelector before the rest of iterator
+	if r.CoinSelector != nil && r.selectedIndex < len(r.selectedCells) {
+		t := r.selectedCells[r.selectedIndex]
+		r.selectedIndex += 1
+		return t
+	}
 
 	for {
 		if !r.iterator.HasNext() {
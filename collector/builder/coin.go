@@ -0,0 +1,243 @@
+package builder
+
+import (
+	"fmt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"sort"
+)
+
+const (
+	// inputSize is the size of an input and its empty witness in transaction
+	inputSize uint64 = 44 + 4 + 4
+	// lockWitnessPlaceholderSize is the extra size of WitnessArgs with a 65-byte lock placeholder
+	lockWitnessPlaceholderSize uint64 = 85
+	// candidatePageSize is the number of candidates between checks of StreamingCoinSelector, which is the default
+	// page size of LiveCellIterator
+	candidatePageSize = 100
+)
+
+// DefaultMaxCandidates is the default maximum number of candidates collected from iterator for CoinSelector
+const DefaultMaxCandidates = 1000
+
+// CoinSelectionTarget is the capacity that selected cells should cover
+type CoinSelectionTarget struct {
+	// Capacity is outputs capacity without change plus fee of transaction without inputs
+	Capacity uint64
+	// InputFee is the fee charged for each input, cells are valued by capacity minus InputFee
+	InputFee uint64
+	// ChangeCost is the occupied capacity of change output plus its fee. Cells covering Capacity within ChangeCost
+	// can't afford change.
+	ChangeCost uint64
+	// Inputs is the number of inputs already in transaction, e.g. customized inputs of builder
+	Inputs int
+}
+
+// CoinSelection is the result of CoinSelector
+type CoinSelection struct {
+	Cells []*types.TransactionInput
+	// NoChange means cells match target exactly, so that builder drops change output and leaves the excess as fee
+	NoChange bool
+}
+
+// CoinSelector selects input cells from candidates collected by iterator of builder. Builder consumes all selected
+// cells, and falls back to the rest of candidates and cells left in iterator if they are not enough for the actual fee.
+type CoinSelector interface {
+	SelectCells(candidates []*types.TransactionInput, target *CoinSelectionTarget) (*CoinSelection, error)
+}
+
+// StreamingCoinSelector is implemented by CoinSelector which doesn't need all cells of iterator. Builder collects
+// candidates page by page, and stops once Enough returns true for the candidates collected so far.
+type StreamingCoinSelector interface {
+	CoinSelector
+	Enough(candidates []*types.TransactionInput, target *CoinSelectionTarget) bool
+}
+
+// LargestFirstSelector selects the largest cells at first, which uses the fewest inputs. It streams candidates, so the
+// largest cells are selected among the pages of iterator which cover target.
+type LargestFirstSelector struct{}
+
+func (s *LargestFirstSelector) SelectCells(candidates []*types.TransactionInput, target *CoinSelectionTarget) (*CoinSelection, error) {
+	return selectInOrder(sortCells(candidates, true), target), nil
+}
+
+func (s *LargestFirstSelector) Enough(candidates []*types.TransactionInput, target *CoinSelectionTarget) bool {
+	return coversTarget(candidates, target)
+}
+
+// SmallestFirstSelector selects the smallest cells at first, which consumes small cells of wallet. It streams
+// candidates, so the smallest cells are selected among the pages of iterator which cover target.
+type SmallestFirstSelector struct{}
+
+func (s *SmallestFirstSelector) SelectCells(candidates []*types.TransactionInput, target *CoinSelectionTarget) (*CoinSelection, error) {
+	return selectInOrder(sortCells(candidates, false), target), nil
+}
+
+func (s *SmallestFirstSelector) Enough(candidates []*types.TransactionInput, target *CoinSelectionTarget) bool {
+	return coversTarget(candidates, target)
+}
+
+// BranchAndBoundSelector searches cells matching target within ChangeCost, so that no change output is needed. It
+// selects by Fallback when there is no exact match in MaxTries steps.
+type BranchAndBoundSelector struct {
+	MaxTries int
+	Fallback CoinSelector
+}
+
+func NewBranchAndBoundSelector() *BranchAndBoundSelector {
+	return &BranchAndBoundSelector{
+		MaxTries: 100000,
+		Fallback: &LargestFirstSelector{},
+	}
+}
+
+func (s *BranchAndBoundSelector) SelectCells(candidates []*types.TransactionInput, target *CoinSelectionTarget) (*CoinSelection, error) {
+	cells := sortCells(candidates, true)
+	values := make([]uint64, len(cells))
+	remaining := uint64(0)
+	for i, cell := range cells {
+		values[i] = effectiveCapacity(cell, target.InputFee)
+		remaining += values[i]
+	}
+	lower := target.Capacity
+	upper := target.Capacity + target.ChangeCost
+
+	var (
+		best      []int
+		bestWaste uint64
+		selected  []int
+		tries     = s.MaxTries
+	)
+	var search func(i int, sum uint64, remaining uint64)
+	search = func(i int, sum uint64, remaining uint64) {
+		if tries <= 0 || (best != nil && bestWaste == 0) {
+			return
+		}
+		tries -= 1
+		if sum >= upper || sum+remaining < lower {
+			return
+		}
+		if sum >= lower {
+			// adding more cells only wastes more
+			if waste := sum - lower; best == nil || waste < bestWaste {
+				best = append([]int{}, selected...)
+				bestWaste = waste
+			}
+			return
+		}
+		if i == len(values) || values[i] == 0 {
+			return
+		}
+		selected = append(selected, i)
+		search(i+1, sum+values[i], remaining-values[i])
+		selected = selected[:len(selected)-1]
+		search(i+1, sum, remaining-values[i])
+	}
+	search(0, 0, remaining)
+
+	if best == nil {
+		if s.Fallback == nil {
+			return nil, fmt.Errorf("no exact match in %d tries", s.MaxTries)
+		}
+		return s.Fallback.SelectCells(candidates, target)
+	}
+	selection := &CoinSelection{NoChange: true}
+	for _, i := range best {
+		selection.Cells = append(selection.Cells, cells[i])
+	}
+	return selection, nil
+}
+
+// ConsolidationSelector merges small cells into change. It selects the largest cells needed for target, then fills up
+// to MaxInputs inputs with the smallest cells. MaxInputs counts the inputs already in transaction, and there is no cap
+// when it's 0.
+type ConsolidationSelector struct {
+	MaxInputs int
+}
+
+func NewConsolidationSelector(maxInputs int) *ConsolidationSelector {
+	return &ConsolidationSelector{MaxInputs: maxInputs}
+}
+
+func (s *ConsolidationSelector) SelectCells(candidates []*types.TransactionInput, target *CoinSelectionTarget) (*CoinSelection, error) {
+	cells := sortCells(candidates, true)
+	required := selectInOrder(cells, target).Cells
+	maxCells := s.MaxInputs - target.Inputs
+	if s.MaxInputs > 0 && len(required) > maxCells {
+		return nil, fmt.Errorf("can't cover target capacity with %d inputs", s.MaxInputs)
+	}
+	selection := &CoinSelection{Cells: required}
+	// fill with the smallest cells which are worth their input fee
+	for i := len(cells) - 1; i >= len(required); i-- {
+		if s.MaxInputs > 0 && len(selection.Cells) >= maxCells {
+			break
+		}
+		if effectiveCapacity(cells[i], target.InputFee) > 0 {
+			selection.Cells = append(selection.Cells, cells[i])
+		}
+	}
+	return selection, nil
+}
+
+// selectInOrder selects cells in order until they cover target with change. It selects all cells if they can't.
+func selectInOrder(cells []*types.TransactionInput, target *CoinSelectionTarget) *CoinSelection {
+	selection := &CoinSelection{}
+	sum := uint64(0)
+	for _, cell := range cells {
+		value := effectiveCapacity(cell, target.InputFee)
+		if value == 0 {
+			continue
+		}
+		selection.Cells = append(selection.Cells, cell)
+		sum += value
+		if sum >= target.Capacity+target.ChangeCost {
+			break
+		}
+	}
+	return selection
+}
+
+// coversTarget checks if cells cover target with change
+func coversTarget(cells []*types.TransactionInput, target *CoinSelectionTarget) bool {
+	sum := uint64(0)
+	for _, cell := range cells {
+		sum += effectiveCapacity(cell, target.InputFee)
+		if sum >= target.Capacity+target.ChangeCost {
+			return true
+		}
+	}
+	return false
+}
+
+func sortCells(cells []*types.TransactionInput, descending bool) []*types.TransactionInput {
+	sorted := append([]*types.TransactionInput{}, cells...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if descending {
+			return sorted[i].Output.Capacity > sorted[j].Output.Capacity
+		}
+		return sorted[i].Output.Capacity < sorted[j].Output.Capacity
+	})
+	return sorted
+}
+
+func effectiveCapacity(cell *types.TransactionInput, inputFee uint64) uint64 {
+	if cell.Output.Capacity <= inputFee {
+		return 0
+	}
+	return cell.Output.Capacity - inputFee
+}
+
+func calculateFee(size uint64, feeRate uint64) uint64 {
+	fee := size * feeRate / 1000
+	if fee*1000 < size*feeRate {
+		fee += 1
+	}
+	return fee
+}
+
+// withoutOutput returns a copy of tx without output at index
+func withoutOutput(tx *types.Transaction, index int) *types.Transaction {
+	copied := *tx
+	copied.Outputs = append(append([]*types.CellOutput{}, tx.Outputs[:index]...), tx.Outputs[index+1:]...)
+	copied.OutputsData = append(append([][]byte{}, tx.OutputsData[:index]...), tx.OutputsData[index+1:]...)
+	return &copied
+}
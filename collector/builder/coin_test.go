@@ -0,0 +1,158 @@
+package builder
+
+import (
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func getCapacityCells(capacities ...uint64) []*types.TransactionInput {
+	cells := make([]*types.TransactionInput, 0, len(capacities))
+	for i, capacity := range capacities {
+		cells = append(cells, &types.TransactionInput{
+			OutPoint: &types.OutPoint{Index: uint32(i)},
+			Output:   &types.CellOutput{Capacity: capacity, Lock: lock},
+		})
+	}
+	return cells
+}
+
+func capacitiesOf(cells []*types.TransactionInput) []uint64 {
+	capacities := make([]uint64, 0, len(cells))
+	for _, cell := range cells {
+		capacities = append(capacities, cell.Output.Capacity)
+	}
+	return capacities
+}
+
+func TestCoinSelectors(t *testing.T) {
+	cells := getCapacityCells(30, 100, 10, 50, 20)
+	target := &CoinSelectionTarget{Capacity: 55, ChangeCost: 5}
+
+	selection, err := (&LargestFirstSelector{}).SelectCells(cells, target)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{100}, capacitiesOf(selection.Cells))
+
+	selection, err = (&SmallestFirstSelector{}).SelectCells(cells, target)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{10, 20, 30}, capacitiesOf(selection.Cells))
+
+	selection, err = NewBranchAndBoundSelector().SelectCells(cells, &CoinSelectionTarget{Capacity: 55, ChangeCost: 6})
+	assert.NoError(t, err)
+	assert.True(t, selection.NoChange)
+	assert.Equal(t, []uint64{50, 10}, capacitiesOf(selection.Cells))
+
+	// fallback without exact match
+	selection, err = NewBranchAndBoundSelector().SelectCells(cells, &CoinSelectionTarget{Capacity: 205, ChangeCost: 1})
+	assert.NoError(t, err)
+	assert.False(t, selection.NoChange)
+	assert.Equal(t, []uint64{100, 50, 30, 20, 10}, capacitiesOf(selection.Cells))
+
+	// input fee is counted
+	selection, err = NewBranchAndBoundSelector().SelectCells(cells, &CoinSelectionTarget{Capacity: 90, InputFee: 5, ChangeCost: 1})
+	assert.NoError(t, err)
+	assert.True(t, selection.NoChange)
+	assert.Equal(t, []uint64{50, 30, 20, 10}, capacitiesOf(selection.Cells))
+
+	selection, err = NewConsolidationSelector(3).SelectCells(cells, target)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{100, 10, 20}, capacitiesOf(selection.Cells))
+	_, err = NewConsolidationSelector(1).SelectCells(cells, &CoinSelectionTarget{Capacity: 120})
+	assert.Error(t, err)
+	// existing inputs count in MaxInputs
+	selection, err = NewConsolidationSelector(3).SelectCells(cells, &CoinSelectionTarget{Capacity: 50, Inputs: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{100, 10}, capacitiesOf(selection.Cells))
+	_, err = NewConsolidationSelector(3).SelectCells(cells, &CoinSelectionTarget{Capacity: 120, Inputs: 2})
+	assert.Error(t, err)
+
+	assert.True(t, (&LargestFirstSelector{}).Enough(cells, &CoinSelectionTarget{Capacity: 200, ChangeCost: 10}))
+	assert.False(t, (&SmallestFirstSelector{}).Enough(cells, &CoinSelectionTarget{Capacity: 200, InputFee: 1, ChangeCost: 10}))
+}
+
+func TestCkbTransactionBuilderCoinSelector(t *testing.T) {
+	build := func(selector CoinSelector, capacity uint64) *types.Transaction {
+		builder := NewCkbTransactionBuilder(types.NetworkTest, getMockIterator())
+		builder.CoinSelector = selector
+		builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", capacity)
+		if err := builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"); err != nil {
+			t.Fatal(err)
+		}
+		tx, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx.TxView
+	}
+
+	tx := build(&SmallestFirstSelector{}, 3000000000)
+	assert.Equal(t, 1, len(tx.Inputs))
+	assert.Equal(t, uint32(1), tx.Inputs[0].PreviousOutput.Index)
+
+	tx = build(&LargestFirstSelector{}, 3000000000)
+	assert.Equal(t, 1, len(tx.Inputs))
+	assert.Equal(t, uint32(0), tx.Inputs[0].PreviousOutput.Index)
+
+	// the 1000 CKB cell can't afford a change output
+	tx = build(&LargestFirstSelector{}, 99900000000)
+	assert.Equal(t, 2, len(tx.Inputs))
+	assert.Equal(t, 2, len(tx.Outputs))
+
+	// exact match drops change output
+	tx = build(NewBranchAndBoundSelector(), 99900000000)
+	assert.Equal(t, 1, len(tx.Inputs))
+	assert.Equal(t, 1, len(tx.Outputs))
+	assert.Equal(t, uint64(99900000000), tx.Outputs[0].Capacity)
+	assert.Equal(t, 1, len(tx.Witnesses))
+
+	// cost of derived change output is counted in selection
+	builder := NewCkbTransactionBuilder(types.NetworkTest, getMockIterator())
+	builder.CoinSelector = NewBranchAndBoundSelector()
+	builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 99900000000)
+	txWithGroups, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(txWithGroups.TxView.Inputs))
+	assert.Equal(t, 1, len(txWithGroups.TxView.Outputs))
+
+	// customized inputs count in MaxInputs of ConsolidationSelector
+	builder = NewCkbTransactionBuilder(types.NetworkTest, getMockIterator())
+	builder.CoinSelector = NewConsolidationSelector(2)
+	builder.transactionInputs = getCapacityCells(50000000000)
+	builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 10000000000)
+	assert.NoError(t, builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"))
+	txWithGroups, err = builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(txWithGroups.TxView.Inputs))
+}
+
+func TestCkbTransactionBuilderCandidates(t *testing.T) {
+	capacities := make([]uint64, 250)
+	for i := range capacities {
+		capacities[i] = 10000000000
+	}
+	build := func(selector CoinSelector, maxCandidates int, capacity uint64) (*mockIterator, *types.Transaction) {
+		iterator := &mockIterator{Cells: getCapacityCells(capacities...)}
+		builder := NewCkbTransactionBuilder(types.NetworkTest, iterator)
+		builder.CoinSelector = selector
+		builder.MaxCandidates = maxCandidates
+		builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", capacity)
+		if err := builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"); err != nil {
+			t.Fatal(err)
+		}
+		tx, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return iterator, tx.TxView
+	}
+
+	// the first page covers target, so the rest of iterator isn't collected
+	iterator, tx := build(&LargestFirstSelector{}, 0, 50000000000)
+	assert.Equal(t, 100, iterator.index)
+	assert.Equal(t, 6, len(tx.Inputs))
+
+	// candidates are capped, and cells left in iterator are collected when selected cells are not enough
+	iterator, tx = build(NewBranchAndBoundSelector(), 10, 150000000000)
+	assert.Equal(t, 16, iterator.index)
+	assert.Equal(t, 16, len(tx.Inputs))
+}
@@ -0,0 +1,86 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/rpc"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction/signer"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+)
+
+// DefaultScriptCycles is the default cycles of a script group in TableCycleEstimator, which is a bit more than a
+// secp256k1 signature verification consumes
+const DefaultScriptCycles uint64 = 2000000
+
+// CycleEstimator estimates the cycles consumed by all scripts of transaction
+type CycleEstimator interface {
+	EstimateCycles(ctx context.Context, tx *transaction.TransactionWithScriptGroups) (uint64, error)
+}
+
+// RpcCycleEstimator estimates cycles by RPC estimate_cycles. Scripts fail with witness placeholders, so a copy of the
+// transaction is signed by Signer with Contexts at first, which should unlock all lock script groups.
+type RpcCycleEstimator struct {
+	Client   rpc.Client
+	Signer   *signer.TransactionSigner
+	Contexts []*transaction.Context
+}
+
+func NewRpcCycleEstimator(client rpc.Client, txSigner *signer.TransactionSigner, contexts ...*transaction.Context) *RpcCycleEstimator {
+	return &RpcCycleEstimator{
+		Client:   client,
+		Signer:   txSigner,
+		Contexts: contexts,
+	}
+}
+
+func (e *RpcCycleEstimator) EstimateCycles(ctx context.Context, tx *transaction.TransactionWithScriptGroups) (uint64, error) {
+	if e.Signer == nil {
+		return 0, errors.New("signer is required to estimate cycles by RPC")
+	}
+	txView := *tx.TxView
+	txView.Witnesses = append([][]byte{}, tx.TxView.Witnesses...)
+	signed := &transaction.TransactionWithScriptGroups{
+		TxView:       &txView,
+		ScriptGroups: tx.ScriptGroups,
+	}
+	if _, err := e.Signer.SignTransaction(signed, e.Contexts...); err != nil {
+		return 0, err
+	}
+	result, err := e.Client.EstimateCycles(ctx, signed.TxView)
+	if err != nil {
+		return 0, err
+	}
+	return result.Cycles, nil
+}
+
+// TableCycleEstimator estimates cycles offline by a table of cycles per script group, which is keyed by script code
+// hash. DefaultCycles is used for scripts not in table.
+type TableCycleEstimator struct {
+	Cycles        map[types.Hash]uint64
+	DefaultCycles uint64
+}
+
+func NewTableCycleEstimator(defaultCycles uint64) *TableCycleEstimator {
+	return &TableCycleEstimator{
+		Cycles:        make(map[types.Hash]uint64),
+		DefaultCycles: defaultCycles,
+	}
+}
+
+func (e *TableCycleEstimator) Set(codeHash types.Hash, cycles uint64) {
+	e.Cycles[codeHash] = cycles
+}
+
+func (e *TableCycleEstimator) EstimateCycles(ctx context.Context, tx *transaction.TransactionWithScriptGroups) (uint64, error) {
+	total := uint64(0)
+	for _, group := range tx.ScriptGroups {
+		// type scripts only in outputs also run
+		if cycles, ok := e.Cycles[group.Script.CodeHash]; ok {
+			total += cycles
+		} else {
+			total += e.DefaultCycles
+		}
+	}
+	return total, nil
+}
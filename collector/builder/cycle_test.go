@@ -0,0 +1,71 @@
+package builder
+
+import (
+	"context"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction/signer"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTableCycleEstimator(t *testing.T) {
+	estimator := NewTableCycleEstimator(100)
+	secpCodeHash := systemscript.GetCodeHash(types.NetworkTest, systemscript.Secp256k1Blake160SighashAll)
+	estimator.Set(secpCodeHash, 1000)
+	tx := &transaction.TransactionWithScriptGroups{
+		ScriptGroups: []*transaction.ScriptGroup{
+			{Script: &types.Script{CodeHash: secpCodeHash}},
+			{Script: &types.Script{CodeHash: types.HexToHash("0x01")}},
+		},
+	}
+	cycles, err := estimator.EstimateCycles(context.Background(), tx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1100), cycles)
+}
+
+func TestCkbTransactionBuilderCycleFeeEstimator(t *testing.T) {
+	build := func(iterator *mockIterator, estimator CycleEstimator) *types.Transaction {
+		builder := NewCkbTransactionBuilder(types.NetworkTest, iterator)
+		builder.FeeEstimator = NewCycleFeeEstimator(estimator, NewStaticFeeEstimator(1000))
+		builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 50100000000)
+		if err := builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"); err != nil {
+			t.Fatal(err)
+		}
+		tx, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx.TxView
+	}
+	fee := func(tx *types.Transaction) uint64 {
+		return 100000000000 - tx.Outputs[0].Capacity - tx.Outputs[1].Capacity
+	}
+
+	// fee by size is charged when cycles are few
+	tx := build(getMockIterator(), NewTableCycleEstimator(1000))
+	assert.Equal(t, uint64(464), fee(tx))
+
+	// change is re-balanced with fee by weight
+	tx = build(getMockIterator(), NewTableCycleEstimator(10000000))
+	assert.Equal(t, tx.CalculateFeeWithTxWeight(10000000, 1000), fee(tx))
+	assert.True(t, fee(tx) > 464)
+
+	// transaction is signed before estimated by RPC
+	iterator := getMockIterator()
+	for _, cell := range iterator.Cells {
+		cell.Output.Lock = chequeReceiver.Script
+	}
+	client := &feeMockClient{cycles: 10000000}
+	estimator := NewRpcCycleEstimator(client, signer.GetTransactionSignerInstance(types.NetworkTest), &transaction.Context{Key: chequeReceiverKey})
+	tx = build(iterator, estimator)
+	assert.Equal(t, tx.CalculateFeeWithTxWeight(client.cycles, 1000), fee(tx))
+	witnessArgs, err := types.DeserializeWitnessArgs(client.estimated.Witnesses[0])
+	assert.NoError(t, err)
+	assert.NotEqual(t, make([]byte, 65), witnessArgs.Lock)
+	// the built transaction keeps placeholder
+	witnessArgs, err = types.DeserializeWitnessArgs(tx.Witnesses[0])
+	assert.NoError(t, err)
+	assert.Equal(t, make([]byte, 65), witnessArgs.Lock)
+}
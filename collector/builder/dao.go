@@ -29,7 +29,12 @@ type DaoTransactionBuilder struct {
 }
 
 func NewDaoTransactionBuilder(network types.Network, iterator collector.CellIterator, daoOutPoint *types.OutPoint, client rpc.Client) (*DaoTransactionBuilder, error) {
-	cellWithStatus, err := client.GetLiveCell(context.Background(), daoOutPoint, true)
+	return NewDaoTransactionBuilderCtx(context.Background(), network, iterator, daoOutPoint, client)
+}
+
+// NewDaoTransactionBuilderCtx is NewDaoTransactionBuilder with ctx for RPC calls
+func NewDaoTransactionBuilderCtx(ctx context.Context, network types.Network, iterator collector.CellIterator, daoOutPoint *types.OutPoint, client rpc.Client) (*DaoTransactionBuilder, error) {
+	cellWithStatus, err := client.GetLiveCell(ctx, daoOutPoint, true)
 	if err != nil {
 		return nil, err
 	}
@@ -47,18 +52,18 @@ func NewDaoTransactionBuilder(network types.Network, iterator collector.CellIter
 	depositCellCapacity := uint64(0)
 	reward := uint64(0)
 	if transactionType == DaoTransactionTypeWithdraw {
-		txWithStatus, err := client.GetTransaction(context.Background(), daoOutPoint.TxHash)
+		txWithStatus, err := client.GetTransaction(ctx, daoOutPoint.TxHash)
 		if err != nil {
 			return nil, err
 		}
-		header, err := client.GetHeader(context.Background(), *txWithStatus.TxStatus.BlockHash)
+		header, err := client.GetHeader(ctx, *txWithStatus.TxStatus.BlockHash)
 		if err != nil {
 			return nil, err
 		}
 		depositBlockNumber = header.Number
 		depositCellCapacity = txWithStatus.Transaction.Outputs[daoOutPoint.Index].Capacity
 	} else if transactionType == DaoTransactionTypeClaim {
-		reward, err = getDaoReward(daoOutPoint, client)
+		reward, err = getDaoReward(ctx, daoOutPoint, client)
 		if err != nil {
 			return nil, err
 		}
@@ -68,6 +73,8 @@ func NewDaoTransactionBuilder(network types.Network, iterator collector.CellIter
 		CkbTransactionBuilder: CkbTransactionBuilder{
 			SimpleTransactionBuilder: *NewSimpleTransactionBuilder(network),
 			FeeRate:                  1000,
+			DustThreshold:            DefaultDustThreshold,
+			MaxCandidates:            DefaultMaxCandidates,
 			iterator:                 iterator,
 			transactionInputs:        []*types.TransactionInput{input}, // add dao inputs
 			transactionInputsIndex:   0,
@@ -93,8 +100,8 @@ func getTransactionType(outputData []byte) (DaoTransactionType, error) {
 	}
 }
 
-func getDaoReward(withdrawOutPoint *types.OutPoint, client rpc.Client) (uint64, error) {
-	txWithStatus, err := client.GetTransaction(context.Background(), withdrawOutPoint.TxHash)
+func getDaoReward(ctx context.Context, withdrawOutPoint *types.OutPoint, client rpc.Client) (uint64, error) {
+	txWithStatus, err := client.GetTransaction(ctx, withdrawOutPoint.TxHash)
 	if err != nil {
 		return 0, err
 	}
@@ -107,7 +114,7 @@ func getDaoReward(withdrawOutPoint *types.OutPoint, client rpc.Client) (uint64,
 	)
 	for i := 0; i < len(withdrawTx.Inputs); i++ {
 		outPoint := withdrawTx.Inputs[i].PreviousOutput
-		txWithStatus, err := client.GetTransaction(context.Background(), outPoint.TxHash)
+		txWithStatus, err := client.GetTransaction(ctx, outPoint.TxHash)
 		if err != nil {
 			return 0, err
 		}
@@ -124,11 +131,11 @@ func getDaoReward(withdrawOutPoint *types.OutPoint, client rpc.Client) (uint64,
 	if depositCell == nil {
 		return 0, errors.New("can't find deposit cell")
 	}
-	depositBlockHeader, err := client.GetHeader(context.Background(), depositBlockHash)
+	depositBlockHeader, err := client.GetHeader(ctx, depositBlockHash)
 	if err != nil {
 		return 0, err
 	}
-	withdrawBlockHeader, err := client.GetHeader(context.Background(), *withdrawBlockHash)
+	withdrawBlockHeader, err := client.GetHeader(ctx, *withdrawBlockHash)
 	if err != nil {
 		return 0, err
 	}
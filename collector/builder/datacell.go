@@ -0,0 +1,111 @@
+package builder
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/address"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+)
+
+// DataCellTransactionBuilder creates, updates and destroys data cells guarded by Type ID, such as deployed contracts
+// or NFT-style cells. Capacity is collected from iterator like CkbTransactionBuilder.
+type DataCellTransactionBuilder struct {
+	CkbTransactionBuilder
+	// Type ID scripts of created cells, whose args are computed after inputs are collected
+	createdTypes []*types.Script
+}
+
+func NewDataCellTransactionBuilder(network types.Network, iterator collector.CellIterator) *DataCellTransactionBuilder {
+	return &DataCellTransactionBuilder{
+		CkbTransactionBuilder: *NewCkbTransactionBuilder(network, iterator),
+	}
+}
+
+// CreateDataCell creates a cell of data locked by address, with a new Type ID. Occupied capacity is used when
+// capacity is 0. It returns the created type script, whose args are set when transaction is built.
+func (r *DataCellTransactionBuilder) CreateDataCell(addr string, capacity uint64, data []byte) (*types.Script, error) {
+	a, err := address.Decode(addr)
+	if err != nil {
+		return nil, err
+	}
+	// placeholder args are distinct so that outputs are in different script groups
+	args := make([]byte, 32)
+	binary.LittleEndian.PutUint64(args, uint64(len(r.createdTypes)))
+	typeScript := &types.Script{
+		CodeHash: systemscript.TypeIdCodeHash,
+		HashType: types.HashTypeType,
+		Args:     args,
+	}
+	output := &types.CellOutput{
+		Capacity: capacity,
+		Lock:     a.Script,
+		Type:     typeScript,
+	}
+	if capacity == 0 {
+		output.Capacity = output.OccupiedCapacity(data)
+	}
+	r.createdTypes = append(r.createdTypes, typeScript)
+	r.AddOutput(output, data)
+	return typeScript, nil
+}
+
+// UpdateDataCell consumes a Type ID cell and recreates it with new data, keeping its lock and type. The cell keeps
+// its capacity if it's enough for new data, otherwise the extra capacity is collected from iterator.
+func (r *DataCellTransactionBuilder) UpdateDataCell(cell *types.TransactionInput, data []byte) (int, error) {
+	if !systemscript.IsTypeIdScript(cell.Output.Type) {
+		return 0, errors.New("not a type id cell")
+	}
+	output := &types.CellOutput{
+		Capacity: cell.Output.Capacity,
+		Lock:     cell.Output.Lock,
+		Type:     cell.Output.Type,
+	}
+	if occupied := output.OccupiedCapacity(data); occupied > output.Capacity {
+		output.Capacity = occupied
+	}
+	r.transactionInputs = append(r.transactionInputs, cell)
+	return r.AddOutput(output, data), nil
+}
+
+// DestroyDataCell consumes a Type ID cell without recreating it, and its capacity goes to change.
+func (r *DataCellTransactionBuilder) DestroyDataCell(cell *types.TransactionInput) error {
+	if !systemscript.IsTypeIdScript(cell.Output.Type) {
+		return errors.New("not a type id cell")
+	}
+	r.transactionInputs = append(r.transactionInputs, cell)
+	return nil
+}
+
+func (r *DataCellTransactionBuilder) Build(contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	return r.build(context.Background(), contexts...)
+}
+
+// BuildCtx is Build with ctx bound to the queries of iterator, if it implements collector.CellIteratorCtx.
+func (r *DataCellTransactionBuilder) BuildCtx(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	defer bindIteratorContext(r.iterator, ctx)()
+	return r.build(ctx, contexts...)
+}
+
+func (r *DataCellTransactionBuilder) build(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	if _, err := r.CkbTransactionBuilder.build(ctx, contexts...); err != nil {
+		return nil, err
+	}
+	// Type ID args depend on the first input, and args length is fixed so fee is unaffected
+	for _, typeScript := range r.createdTypes {
+		index := -1
+		for i, output := range r.Outputs {
+			if output.Type == typeScript {
+				index = i
+			}
+		}
+		if index == -1 {
+			return nil, errors.New("created type id cell is not in outputs")
+		}
+		typeScript.Args = systemscript.TypeIdArgs(r.Inputs[0], uint64(index))
+	}
+	return r.BuildTransaction(), nil
+}
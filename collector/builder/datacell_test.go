@@ -0,0 +1,77 @@
+package builder
+
+import (
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func getTypeIdCell(data []byte) *types.TransactionInput {
+	return &types.TransactionInput{
+		OutPoint: &types.OutPoint{
+			TxHash: types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000005"),
+			Index:  0,
+		},
+		Output: &types.CellOutput{
+			Capacity: 10000000000,
+			Lock:     lock,
+			Type: &types.Script{
+				CodeHash: systemscript.TypeIdCodeHash,
+				HashType: types.HashTypeType,
+				Args:     make([]byte, 32),
+			},
+		},
+		OutputData: data,
+	}
+}
+
+func TestDataCellTransactionBuilderCreate(t *testing.T) {
+	builder := NewDataCellTransactionBuilder(types.NetworkTest, getMockIterator())
+	first, err := builder.CreateDataCell(encodeAddress(t, xudtOwner), 0, []byte{1, 2, 3})
+	assert.NoError(t, err)
+	second, err := builder.CreateDataCell(encodeAddress(t, xudtOwner), 20000000000, []byte{4})
+	assert.NoError(t, err)
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, xudtOwner)))
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(12900000000), tx.TxView.Outputs[0].Capacity)
+	assert.Equal(t, systemscript.TypeIdArgs(tx.TxView.Inputs[0], 0), first.Args)
+	assert.Equal(t, systemscript.TypeIdArgs(tx.TxView.Inputs[0], 1), second.Args)
+	assert.Equal(t, first, tx.TxView.Outputs[0].Type)
+	groups := 0
+	for _, group := range tx.ScriptGroups {
+		if group.GroupType == types.ScriptTypeType {
+			assert.Equal(t, 1, len(group.OutputIndices))
+			assert.Equal(t, tx.TxView.Outputs[group.OutputIndices[0]].Type, group.Script)
+			groups += 1
+		}
+	}
+	assert.Equal(t, 2, groups)
+}
+
+func TestDataCellTransactionBuilderUpdateAndDestroy(t *testing.T) {
+	cell := getTypeIdCell([]byte{1})
+	builder := NewDataCellTransactionBuilder(types.NetworkTest, getMockIterator())
+	_, err := builder.UpdateDataCell(cell, make([]byte, 100))
+	assert.NoError(t, err)
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, xudtOwner)))
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, cell.OutPoint, tx.TxView.Inputs[0].PreviousOutput)
+	assert.Equal(t, cell.Output.Type, tx.TxView.Outputs[0].Type)
+	// capacity is increased for new data
+	assert.Equal(t, tx.TxView.Outputs[0].OccupiedCapacity(tx.TxView.OutputsData[0]), tx.TxView.Outputs[0].Capacity)
+
+	builder = NewDataCellTransactionBuilder(types.NetworkTest, getMockIterator())
+	assert.NoError(t, builder.DestroyDataCell(cell))
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, xudtOwner)))
+	tx, err = builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tx.TxView.Inputs))
+	assert.Equal(t, 1, len(tx.TxView.Outputs))
+	assert.Nil(t, tx.TxView.Outputs[0].Type)
+
+	assert.Error(t, builder.DestroyDataCell(getMockIterator().Cells[0]))
+}
@@ -0,0 +1,154 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/address"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/blake2b"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+)
+
+// DeploymentRecord is the deployment of a code cell or dep group cell. It's compatible with systemscript.Info, so
+// that handlers can be created from it, e.g. with CellDep() and CodeHash.
+type DeploymentRecord struct {
+	systemscript.Info
+	// DataHash is the hash of cell data
+	DataHash types.Hash `json:"data_hash"`
+	// TypeId is the Type ID script of upgradable code cell
+	TypeId *types.Script `json:"type_id,omitempty"`
+}
+
+type deployment struct {
+	output   *types.CellOutput
+	dataHash types.Hash
+	depType  types.DepType
+	codeHash types.Hash
+	hashType types.ScriptHashType
+}
+
+// DeployTransactionBuilder deploys binaries into code cells and creates dep group cells. Code cells created with
+// Type ID can be upgraded by DataCellTransactionBuilder.UpdateDataCell.
+type DeployTransactionBuilder struct {
+	DataCellTransactionBuilder
+	deployments []*deployment
+	txHash      *types.Hash
+}
+
+func NewDeployTransactionBuilder(network types.Network, iterator collector.CellIterator) *DeployTransactionBuilder {
+	return &DeployTransactionBuilder{
+		DataCellTransactionBuilder: *NewDataCellTransactionBuilder(network, iterator),
+	}
+}
+
+// AddCodeCell deploys binary into a code cell locked by address. Script referencing it uses the data hash with hash
+// type data1, or the Type ID hash with hash type type if it's upgradable.
+func (r *DeployTransactionBuilder) AddCodeCell(addr string, binary []byte, upgradable bool) error {
+	d := &deployment{
+		dataHash: types.BytesToHash(blake2b.Blake256(binary)),
+		depType:  types.DepTypeCode,
+		hashType: types.HashTypeData1,
+	}
+	if upgradable {
+		if _, err := r.CreateDataCell(addr, 0, binary); err != nil {
+			return err
+		}
+		d.hashType = types.HashTypeType
+	} else {
+		a, err := address.Decode(addr)
+		if err != nil {
+			return err
+		}
+		output := &types.CellOutput{Lock: a.Script}
+		output.Capacity = output.OccupiedCapacity(binary)
+		r.AddOutput(output, binary)
+	}
+	d.output = r.Outputs[len(r.Outputs)-1]
+	d.codeHash = d.dataHash
+	r.deployments = append(r.deployments, d)
+	return nil
+}
+
+// AddDepGroupCell creates a dep group cell of outPoints, which should be live cells deployed by previous
+// transactions. codeHash and hashType are of the script referencing the dep group, and are only used in records.
+func (r *DeployTransactionBuilder) AddDepGroupCell(addr string, outPoints []*types.OutPoint, codeHash types.Hash, hashType types.ScriptHashType) error {
+	if len(outPoints) == 0 {
+		return errors.New("dep group should not be empty")
+	}
+	a, err := address.Decode(addr)
+	if err != nil {
+		return err
+	}
+	data := types.SerializeOutPointVec(outPoints)
+	output := &types.CellOutput{Lock: a.Script}
+	output.Capacity = output.OccupiedCapacity(data)
+	r.AddOutput(output, data)
+	r.deployments = append(r.deployments, &deployment{
+		output:   output,
+		dataHash: types.BytesToHash(blake2b.Blake256(data)),
+		depType:  types.DepTypeDepGroup,
+		codeHash: codeHash,
+		hashType: hashType,
+	})
+	return nil
+}
+
+func (r *DeployTransactionBuilder) Build(contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	return r.build(context.Background(), contexts...)
+}
+
+// BuildCtx is Build with ctx bound to the queries of iterator, if it implements collector.CellIteratorCtx.
+func (r *DeployTransactionBuilder) BuildCtx(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	defer bindIteratorContext(r.iterator, ctx)()
+	return r.build(ctx, contexts...)
+}
+
+func (r *DeployTransactionBuilder) build(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	tx, err := r.DataCellTransactionBuilder.build(ctx, contexts...)
+	if err != nil {
+		return nil, err
+	}
+	// signing doesn't change transaction hash
+	txHash := tx.TxView.ComputeHash()
+	r.txHash = &txHash
+	return tx, nil
+}
+
+// Records returns deployment records in the order cells are added, after transaction is built.
+func (r *DeployTransactionBuilder) Records() ([]*DeploymentRecord, error) {
+	if r.txHash == nil {
+		return nil, errors.New("transaction is not built")
+	}
+	records := make([]*DeploymentRecord, 0, len(r.deployments))
+	for _, d := range r.deployments {
+		index := -1
+		for i, output := range r.Outputs {
+			if output == d.output {
+				index = i
+			}
+		}
+		if index == -1 {
+			return nil, errors.New("deployed cell is not in outputs")
+		}
+		record := &DeploymentRecord{
+			Info: systemscript.Info{
+				CodeHash: d.codeHash,
+				HashType: d.hashType,
+				OutPoint: &types.OutPoint{
+					TxHash: *r.txHash,
+					Index:  uint32(index),
+				},
+				DepType: d.depType,
+			},
+			DataHash: d.dataHash,
+		}
+		if d.output.Type != nil {
+			record.TypeId = d.output.Type
+			record.CodeHash = d.output.Type.Hash()
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,59 @@
+package builder
+
+import (
+	"encoding/json"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/blake2b"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDeployTransactionBuilder(t *testing.T) {
+	binary := []byte("contract binary")
+	builder := NewDeployTransactionBuilder(types.NetworkTest, getMockIterator())
+	assert.NoError(t, builder.AddCodeCell(encodeAddress(t, xudtOwner), binary, false))
+	assert.NoError(t, builder.AddCodeCell(encodeAddress(t, xudtOwner), binary, true))
+	depOutPoints := []*types.OutPoint{
+		{TxHash: types.HexToHash("0x01"), Index: 0},
+		{TxHash: types.HexToHash("0x01"), Index: 1},
+	}
+	assert.NoError(t, builder.AddDepGroupCell(encodeAddress(t, xudtOwner), depOutPoints, types.HexToHash("0x02"), types.HashTypeType))
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, xudtOwner)))
+	_, err := builder.Records()
+	assert.Error(t, err)
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+
+	records, err := builder.Records()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(records))
+	txHash := tx.TxView.ComputeHash()
+	dataHash := types.BytesToHash(blake2b.Blake256(binary))
+
+	assert.Equal(t, dataHash, records[0].CodeHash)
+	assert.Equal(t, types.HashTypeData1, records[0].HashType)
+	assert.Equal(t, &types.OutPoint{TxHash: txHash, Index: 0}, records[0].OutPoint)
+	assert.Equal(t, types.DepTypeCode, records[0].DepType)
+	assert.Nil(t, records[0].TypeId)
+	assert.Equal(t, binary, tx.TxView.OutputsData[0])
+
+	assert.Equal(t, dataHash, records[1].DataHash)
+	assert.Equal(t, tx.TxView.Outputs[1].Type.Hash(), records[1].CodeHash)
+	assert.Equal(t, types.HashTypeType, records[1].HashType)
+	assert.True(t, systemscript.IsTypeIdScript(records[1].TypeId))
+
+	assert.Equal(t, types.DepTypeDepGroup, records[2].DepType)
+	assert.Equal(t, uint32(2), records[2].OutPoint.Index)
+	data := tx.TxView.OutputsData[2]
+	assert.Equal(t, 4+36*2, len(data))
+	assert.Equal(t, depOutPoints[1].Serialize(), data[40:])
+	assert.Equal(t, &types.CellDep{OutPoint: records[2].OutPoint, DepType: types.DepTypeDepGroup}, records[2].CellDep())
+
+	// records can be saved and loaded as system script info
+	encoded, err := json.Marshal(records[1])
+	assert.NoError(t, err)
+	var info systemscript.Info
+	assert.NoError(t, json.Unmarshal(encoded, &info))
+	assert.Equal(t, records[1].Info, info)
+}
@@ -0,0 +1,31 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrChangeOutputNotSet is returned when builder needs a change output but there is none.
+var ErrChangeOutputNotSet = errors.New("change output is not set")
+
+// InsufficientCapacityError is returned when cells of iterator can't cover outputs and fee.
+type InsufficientCapacityError struct {
+	InputsCapacity uint64
+	// Required is outputs capacity plus fee of the last attempt
+	Required uint64
+}
+
+func (e *InsufficientCapacityError) Error() string {
+	return "no enough capacity"
+}
+
+// DustChangeError is returned when change is less than the occupied capacity of change output and DustPolicy rejects
+// it.
+type DustChangeError struct {
+	Change   uint64
+	Occupied uint64
+}
+
+func (e *DustChangeError) Error() string {
+	return fmt.Sprintf("change %d is less than occupied capacity %d of change output", e.Change, e.Occupied)
+}
@@ -0,0 +1,175 @@
+package builder
+
+import (
+	"context"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/rpc"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"sync"
+	"time"
+)
+
+// DefaultMinFeeRate is the default minimum fee rate of CKB node in shannons/KB
+const DefaultMinFeeRate uint64 = 1000
+
+// FeeEstimator estimates the fee of transaction being built. Builders call it each time inputs change, so it should be
+// cheap, and estimation which needs the whole transaction implements WeightFeeEstimator. Builders created with a client
+// use NodeFeeEstimator, and others charge fee by their FeeRate unless one is set.
+type FeeEstimator interface {
+	EstimateFee(ctx context.Context, tx *types.Transaction) (uint64, error)
+}
+
+// WeightFeeEstimator is implemented by FeeEstimator which also charges fee by transaction weight. Builders call it
+// once inputs are enough for outputs and fee by size, and collect more cells if change can't afford the difference.
+type WeightFeeEstimator interface {
+	EstimateFeeByWeight(ctx context.Context, tx *transaction.TransactionWithScriptGroups) (uint64, error)
+}
+
+// FeeRateProvider provides fee rate in shannons/KB
+type FeeRateProvider interface {
+	FeeRate(ctx context.Context) (uint64, error)
+}
+
+// StaticFeeEstimator charges fee by transaction size with a fixed fee rate
+type StaticFeeEstimator struct {
+	Rate uint64
+}
+
+func NewStaticFeeEstimator(feeRate uint64) *StaticFeeEstimator {
+	return &StaticFeeEstimator{Rate: feeRate}
+}
+
+func (e *StaticFeeEstimator) FeeRate(ctx context.Context) (uint64, error) {
+	return e.Rate, nil
+}
+
+func (e *StaticFeeEstimator) EstimateFee(ctx context.Context, tx *types.Transaction) (uint64, error) {
+	return tx.CalculateFee(e.Rate), nil
+}
+
+// NodeFeeEstimator charges fee by transaction size with the median fee rate of recent blocks, which is returned by
+// RPC get_fee_rate_statics. Target is the number of recent blocks to count, and node uses its default when it's 0.
+// Fee rate is cached for CacheDuration.
+type NodeFeeEstimator struct {
+	Client        rpc.Client
+	Target        uint64
+	MinFeeRate    uint64
+	CacheDuration time.Duration
+
+	mutex     sync.Mutex
+	feeRate   uint64
+	fetchedAt time.Time
+}
+
+func NewNodeFeeEstimator(client rpc.Client, target uint64) *NodeFeeEstimator {
+	return &NodeFeeEstimator{
+		Client:        client,
+		Target:        target,
+		MinFeeRate:    DefaultMinFeeRate,
+		CacheDuration: time.Minute,
+	}
+}
+
+func (e *NodeFeeEstimator) FeeRate(ctx context.Context) (uint64, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if !e.fetchedAt.IsZero() && time.Since(e.fetchedAt) < e.CacheDuration {
+		return e.feeRate, nil
+	}
+	var target interface{}
+	if e.Target > 0 {
+		target = e.Target
+	}
+	statics, err := e.Client.GetFeeRateStatics(ctx, target)
+	if err != nil {
+		return 0, err
+	}
+	feeRate := e.MinFeeRate
+	// statics is empty when there is no transaction in recent blocks
+	if statics != nil && statics.Median > feeRate {
+		feeRate = statics.Median
+	}
+	e.feeRate = feeRate
+	e.fetchedAt = time.Now()
+	return feeRate, nil
+}
+
+func (e *NodeFeeEstimator) EstimateFee(ctx context.Context, tx *types.Transaction) (uint64, error) {
+	feeRate, err := e.FeeRate(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return tx.CalculateFee(feeRate), nil
+}
+
+// CycleFeeEstimator charges fee by transaction size while collecting cells, and by transaction weight, which counts
+// both size and cycles of scripts, once the transaction is balanced. Cycles are estimated by CycleEstimator, which is a
+// TableCycleEstimator of DefaultScriptCycles by default.
+type CycleFeeEstimator struct {
+	CycleEstimator  CycleEstimator
+	FeeRateProvider FeeRateProvider
+}
+
+func NewCycleFeeEstimator(cycleEstimator CycleEstimator, feeRateProvider FeeRateProvider) *CycleFeeEstimator {
+	if cycleEstimator == nil {
+		cycleEstimator = NewTableCycleEstimator(DefaultScriptCycles)
+	}
+	return &CycleFeeEstimator{
+		CycleEstimator:  cycleEstimator,
+		FeeRateProvider: feeRateProvider,
+	}
+}
+
+func (e *CycleFeeEstimator) FeeRate(ctx context.Context) (uint64, error) {
+	return e.FeeRateProvider.FeeRate(ctx)
+}
+
+func (e *CycleFeeEstimator) EstimateFee(ctx context.Context, tx *types.Transaction) (uint64, error) {
+	feeRate, err := e.FeeRate(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return tx.CalculateFee(feeRate), nil
+}
+
+func (e *CycleFeeEstimator) EstimateFeeByWeight(ctx context.Context, tx *transaction.TransactionWithScriptGroups) (uint64, error) {
+	feeRate, err := e.FeeRate(ctx)
+	if err != nil {
+		return 0, err
+	}
+	cycles, err := e.CycleEstimator.EstimateCycles(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	return tx.TxView.CalculateFeeWithTxWeight(cycles, feeRate), nil
+}
+
+// estimateFee estimates fee by estimator, or by feeRate if estimator is nil
+func estimateFee(ctx context.Context, estimator FeeEstimator, feeRate uint, tx *types.Transaction) (uint64, error) {
+	if estimator == nil {
+		return tx.CalculateFee(uint64(feeRate)), nil
+	}
+	return estimator.EstimateFee(ctx, tx)
+}
+
+// estimateFeeByWeight returns the fee by weight of transaction if estimator implements WeightFeeEstimator and it's more
+// than fee, otherwise it returns fee
+func estimateFeeByWeight(ctx context.Context, estimator FeeEstimator, b *SimpleTransactionBuilder,
+	scriptGroupMap map[types.Hash]*transaction.ScriptGroup, fee uint64) (uint64, error) {
+	weightEstimator, ok := estimator.(WeightFeeEstimator)
+	if !ok {
+		return fee, nil
+	}
+	tx := b.BuildTransaction()
+	for _, group := range scriptGroupMap {
+		tx.ScriptGroups = append(tx.ScriptGroups, group)
+	}
+	weightFee, err := weightEstimator.EstimateFeeByWeight(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	if weightFee > fee {
+		return weightFee, nil
+	}
+	return fee, nil
+}
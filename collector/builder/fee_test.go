@@ -0,0 +1,107 @@
+package builder
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/rpc"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type feeMockClient struct {
+	rpc.Client
+	statics     *types.FeeRateStatics
+	cycles      uint64
+	estimated   *types.Transaction
+	targets     []interface{}
+	staticsCall int
+}
+
+func (c *feeMockClient) GetFeeRateStatics(ctx context.Context, target interface{}) (*types.FeeRateStatics, error) {
+	c.staticsCall += 1
+	c.targets = append(c.targets, target)
+	return c.statics, nil
+}
+
+func (c *feeMockClient) EstimateCycles(ctx context.Context, transaction *types.Transaction) (*types.EstimateCycles, error) {
+	c.estimated = transaction
+	return &types.EstimateCycles{Cycles: c.cycles}, nil
+}
+
+func TestNodeFeeEstimator(t *testing.T) {
+	client := &feeMockClient{statics: &types.FeeRateStatics{Mean: 3000, Median: 2000}}
+	estimator := NewNodeFeeEstimator(client, 10)
+	feeRate, err := estimator.FeeRate(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2000), feeRate)
+	assert.Equal(t, []interface{}{uint64(10)}, client.targets)
+
+	// fee rate is cached
+	client.statics = &types.FeeRateStatics{Median: 5000}
+	_, err = estimator.FeeRate(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, client.staticsCall)
+
+	// minimum fee rate is used when there is no statics
+	client.statics = nil
+	estimator = NewNodeFeeEstimator(client, 0)
+	feeRate, err = estimator.FeeRate(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultMinFeeRate, feeRate)
+	assert.Nil(t, client.targets[1])
+}
+
+func TestCkbTransactionBuilderFeeEstimator(t *testing.T) {
+	build := func(estimator FeeEstimator) *types.Transaction {
+		builder := NewCkbTransactionBuilder(types.NetworkTest, getMockIterator())
+		builder.FeeEstimator = estimator
+		builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 50100000000)
+		if err := builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"); err != nil {
+			t.Fatal(err)
+		}
+		tx, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx.TxView
+	}
+	fee := func(tx *types.Transaction) uint64 {
+		return 100000000000 - tx.Outputs[0].Capacity - tx.Outputs[1].Capacity
+	}
+
+	tx := build(NewStaticFeeEstimator(1000))
+	assert.Equal(t, uint64(464), fee(tx))
+
+	tx = build(NewNodeFeeEstimator(&feeMockClient{statics: &types.FeeRateStatics{Median: 2000}}, 0))
+	assert.Equal(t, uint64(928), fee(tx))
+
+	// builders created with a client follow the fee rate of node by default
+	client := &feeMockClient{statics: &types.FeeRateStatics{Median: 2000}}
+	assert.IsType(t, &NodeFeeEstimator{}, NewCkbTransactionBuilderWithClient(types.NetworkTest, getMockIterator(), client).FeeEstimator)
+	sudtBuilder := NewSudtTransactionBuilderWithClient(types.NetworkTest, getMockIterator(), SudtTransactionTypeTransfer, []byte{}, client)
+	assert.IsType(t, &NodeFeeEstimator{}, sudtBuilder.FeeEstimator)
+
+	tx = build(NewCycleFeeEstimator(NewTableCycleEstimator(10000000), NewStaticFeeEstimator(1000)))
+	assert.Equal(t, tx.CalculateFeeWithTxWeight(10000000, 1000), fee(tx))
+	assert.True(t, fee(tx) > 464)
+
+	// a secp256k1 input is charged by size with default cycles
+	tx = build(NewCycleFeeEstimator(nil, NewStaticFeeEstimator(1000)))
+	assert.Equal(t, uint64(464), fee(tx))
+}
+
+func TestUdtBuilderCycleFeeEstimator(t *testing.T) {
+	acpCell := getAcpCell(common.FromHex("0xaf0b41c627807fbddcee75afa174d5a7e5135ebd"), nil)
+	builder := NewAnyoneCanPayTransactionBuilder(types.NetworkTest, getMockIterator(), nil)
+	builder.FeeEstimator = NewCycleFeeEstimator(NewTableCycleEstimator(10000000), NewStaticFeeEstimator(1000))
+	assert.NoError(t, builder.AddAnyoneCanPayPayment(acpCell, 1000000000, nil))
+	assert.NoError(t, builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"))
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+
+	// change is re-balanced with fee by weight of two lock groups
+	fee := acpCell.Output.Capacity + 100000000000 - tx.TxView.Outputs[0].Capacity - tx.TxView.Outputs[1].Capacity
+	assert.Equal(t, tx.TxView.CalculateFeeWithTxWeight(20000000, 1000), fee)
+	assert.True(t, fee > tx.TxView.CalculateFee(1000))
+}
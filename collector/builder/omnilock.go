@@ -0,0 +1,121 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/address"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction/signer/omnilock"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"math/big"
+)
+
+// AddOmnilockAcpDeposit deposits capacity and UDT into an Omnilock cell with anyone-can-pay mode enabled. The cell is
+// added as input and a new cell with the increased amount is added as output. Either capacity or udtAmount can be
+// zero, and at least one of them should be no less than the minimum set in Omnilock args.
+//
+// An OmnilockConfiguration with mode OmnolockModeAnyoneCanPay should be passed to Build for the cell's lock.
+func (r *CkbTransactionBuilder) AddOmnilockAcpDeposit(acpCell *types.TransactionInput, capacity uint64, udtAmount *big.Int) error {
+	args, err := omnilock.NewOmnilockArgsFromAgrs(acpCell.Output.Lock.Args)
+	if err != nil {
+		return err
+	}
+	if !args.OmniConfig.IsAnyoneCanPayModeEnabled() {
+		return errors.New("anyone-can-pay mode is not enabled in omnilock args")
+	}
+	if udtAmount == nil {
+		udtAmount = big.NewInt(0)
+	}
+	if udtAmount.Sign() < 0 {
+		return errors.New("udt amount should not be negative")
+	}
+	if capacity == 0 && udtAmount.Sign() == 0 {
+		return errors.New("either capacity or udt amount should be deposited")
+	}
+	ckbMinimum := exponent(args.OmniConfig.MinimumCKBExponentInAcp)
+	udtMinimum := exponent(args.OmniConfig.MinimumSUDTExponentInAcp)
+	if err := checkPaymentMinimum(capacity, udtAmount, ckbMinimum, udtMinimum); err != nil {
+		return err
+	}
+	data := acpCell.OutputData
+	if udtAmount.Sign() > 0 {
+		if acpCell.Output.Type == nil {
+			return errors.New("can't deposit udt to cell without type script")
+		}
+		if len(data) < 16 {
+			return errors.New("udt amount in cell data should be 16 bytes")
+		}
+		amount, err := systemscript.DecodeSudtAmount(data[0:16])
+		if err != nil {
+			return err
+		}
+		amount.Add(amount, udtAmount)
+		// keep data after the amount, which is used by some UDT such as xUDT
+		data = append(systemscript.EncodeSudtAmount(amount), data[16:]...)
+	}
+	output := &types.CellOutput{
+		Capacity: acpCell.Output.Capacity + capacity,
+		Lock:     acpCell.Output.Lock,
+		Type:     acpCell.Output.Type,
+	}
+	r.transactionInputs = append(r.transactionInputs, acpCell)
+	r.AddOutput(output, data)
+	return nil
+}
+
+// AddOmnilockSupplyIssuance issues sUDT to receiver in Omnilock supply mode. The info cell, whose type script hash is
+// set in the args of its Omnilock lock, is consumed and recreated with the increased current supply. sudtType should
+// be the sUDT owned by the Omnilock lock.
+//
+// An OmnilockConfiguration for the info cell's lock should be passed to Build for signing.
+func (r *CkbTransactionBuilder) AddOmnilockSupplyIssuance(infoCell *types.TransactionInput, sudtType *types.Script, receiver string, amount *big.Int) error {
+	args, err := omnilock.NewOmnilockArgsFromAgrs(infoCell.Output.Lock.Args)
+	if err != nil {
+		return err
+	}
+	if !args.OmniConfig.IsSupplyModeEnabled() {
+		return errors.New("supply mode is not enabled in omnilock args")
+	}
+	if infoCell.Output.Type == nil || infoCell.Output.Type.Hash() != args.OmniConfig.TypeScriptHashForSupply {
+		return errors.New("type script hash of info cell mismatches the one in omnilock args")
+	}
+	if amount == nil || amount.Sign() <= 0 {
+		return errors.New("issued amount should be positive")
+	}
+	info, err := omnilock.DecodeToSupplyInfo(infoCell.OutputData)
+	if err != nil {
+		return err
+	}
+	if sudtType.Hash() != info.SudtScriptHash {
+		return errors.New("sudt type script hash mismatches the one in info cell")
+	}
+	info.CurrentSupply = new(big.Int).Add(info.CurrentSupply, amount)
+	if info.CurrentSupply.Cmp(info.MaxSupply) > 0 {
+		return fmt.Errorf("current supply %s exceeds max supply %s", info.CurrentSupply, info.MaxSupply)
+	}
+	a, err := address.Decode(receiver)
+	if err != nil {
+		return err
+	}
+
+	r.transactionInputs = append(r.transactionInputs, infoCell)
+	// keep the data after supply info unchanged
+	infoData := append(info.Encode(), infoCell.OutputData[65:]...)
+	r.AddOutput(&types.CellOutput{
+		Capacity: infoCell.Output.Capacity,
+		Lock:     infoCell.Output.Lock,
+		Type:     infoCell.Output.Type,
+	}, infoData)
+	sudtOutput := &types.CellOutput{
+		Lock: a.Script,
+		Type: sudtType,
+	}
+	sudtData := systemscript.EncodeSudtAmount(amount)
+	sudtOutput.Capacity = sudtOutput.OccupiedCapacity(sudtData)
+	r.AddOutput(sudtOutput, sudtData)
+	return nil
+}
+
+func exponent(n byte) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
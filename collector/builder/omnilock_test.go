@@ -0,0 +1,150 @@
+package builder
+
+import (
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction/signer"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction/signer/omnilock"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func omnilockScript(config *omnilock.OmniConfig) (*types.Script, *omnilock.OmnilockArgs) {
+	args := &omnilock.OmnilockArgs{
+		Authentication: &omnilock.Authentication{Flag: omnilock.AuthFlagCKBSecp256k1Blake160},
+		OmniConfig:     config,
+	}
+	copy(args.Authentication.AuthContent[:], lock.Args)
+	return &types.Script{
+		CodeHash: systemscript.GetCodeHash(types.NetworkTest, systemscript.Omnilock),
+		HashType: types.HashTypeType,
+		Args:     args.Encode(),
+	}, args
+}
+
+func TestOmnilockAcpDeposit(t *testing.T) {
+	acpLock, args := omnilockScript(&omnilock.OmniConfig{
+		Flag:                     0b10,
+		MinimumCKBExponentInAcp:  8,
+		MinimumSUDTExponentInAcp: 2,
+	})
+	acpCell := &types.TransactionInput{
+		OutPoint: &types.OutPoint{
+			TxHash: types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001"),
+			Index:  0,
+		},
+		Output: &types.CellOutput{
+			Capacity: 20000000000,
+			Lock:     acpLock,
+			Type:     sudtType,
+		},
+		OutputData: systemscript.EncodeSudtAmount(big.NewInt(100)),
+	}
+
+	builder := NewCkbTransactionBuilder(types.NetworkTest, getMockIterator())
+	assert.Error(t, builder.AddOmnilockAcpDeposit(acpCell, 99999999, nil))
+	assert.Error(t, builder.AddOmnilockAcpDeposit(acpCell, 0, big.NewInt(99)))
+	assert.Error(t, builder.AddOmnilockAcpDeposit(acpCell, 0, nil))
+	assert.Error(t, builder.AddOmnilockAcpDeposit(acpCell, 99999999, big.NewInt(99)))
+	// enough capacity with a small UDT top-up is accepted
+	assert.NoError(t, builder.AddOmnilockAcpDeposit(acpCell, 100000000, big.NewInt(1)))
+	assert.Equal(t, systemscript.EncodeSudtAmount(big.NewInt(101)), builder.OutputsData[0])
+	builder = NewCkbTransactionBuilder(types.NetworkTest, getMockIterator())
+	assert.NoError(t, builder.AddOmnilockAcpDeposit(acpCell, 100000000, big.NewInt(100)))
+	assert.NoError(t, builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"))
+
+	tx, err := builder.Build(&signer.OmnilockConfiguration{Args: args, Mode: signer.OmnolockModeAnyoneCanPay})
+	assert.NoError(t, err)
+	assert.Equal(t, acpCell.OutPoint, tx.TxView.Inputs[0].PreviousOutput)
+	assert.Equal(t, uint64(20100000000), tx.TxView.Outputs[0].Capacity)
+	assert.Equal(t, systemscript.EncodeSudtAmount(big.NewInt(200)), tx.TxView.OutputsData[0])
+	assert.Equal(t, []byte{}, tx.TxView.Witnesses[0])
+	omnilockDep := systemscript.GetInfo(types.NetworkTest, systemscript.Omnilock).OutPoint
+	found := false
+	for _, dep := range tx.TxView.CellDeps {
+		if *dep.OutPoint == *omnilockDep {
+			found = true
+		}
+	}
+	assert.True(t, found)
+
+	noAcpLock, _ := omnilockScript(&omnilock.OmniConfig{})
+	acpCell.Output.Lock = noAcpLock
+	assert.Error(t, builder.AddOmnilockAcpDeposit(acpCell, 100000000, nil))
+}
+
+func TestOmnilockTimeLock(t *testing.T) {
+	since := uint64(0x2000000000000100)
+	timeLock, args := omnilockScript(&omnilock.OmniConfig{
+		Flag:             0b100,
+		SinceForTimeLock: since,
+	})
+	iterator := getMockIterator()
+	for _, cell := range iterator.Cells {
+		cell.Output.Lock = timeLock
+	}
+	builder := NewCkbTransactionBuilder(types.NetworkTest, iterator)
+	builder.AddOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r", 100000000000)
+	assert.NoError(t, builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"))
+	tx, err := builder.Build(&signer.OmnilockConfiguration{Args: args, Mode: signer.OmnolockModeAuth})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tx.TxView.Inputs))
+	for _, input := range tx.TxView.Inputs {
+		assert.Equal(t, since, input.Since)
+	}
+}
+
+func TestOmnilockSupplyIssuance(t *testing.T) {
+	infoType := &types.Script{
+		CodeHash: types.HexToHash("0x00000000000000000000000000000000000000000000000000545950455f4944"),
+		HashType: types.HashTypeType,
+		Args:     types.HexToHash("0x01").Bytes(),
+	}
+	issuerLock, args := omnilockScript(&omnilock.OmniConfig{
+		Flag:                    0b1000,
+		TypeScriptHashForSupply: infoType.Hash(),
+	})
+	issuedType := &types.Script{
+		CodeHash: systemscript.GetCodeHash(types.NetworkTest, systemscript.Sudt),
+		HashType: types.HashTypeType,
+		Args:     issuerLock.Hash().Bytes(),
+	}
+	info := &omnilock.SupplyInfo{
+		CurrentSupply:  big.NewInt(100),
+		MaxSupply:      big.NewInt(1000),
+		SudtScriptHash: issuedType.Hash(),
+	}
+	infoCell := &types.TransactionInput{
+		OutPoint: &types.OutPoint{
+			TxHash: types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002"),
+			Index:  0,
+		},
+		Output: &types.CellOutput{
+			Capacity: 30000000000,
+			Lock:     issuerLock,
+			Type:     infoType,
+		},
+		OutputData: info.Encode(),
+	}
+	receiver := "ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r"
+
+	builder := NewCkbTransactionBuilder(types.NetworkTest, getMockIterator())
+	assert.Error(t, builder.AddOmnilockSupplyIssuance(infoCell, issuedType, receiver, big.NewInt(901)))
+	assert.Error(t, builder.AddOmnilockSupplyIssuance(infoCell, sudtType, receiver, big.NewInt(1)))
+	assert.NoError(t, builder.AddOmnilockSupplyIssuance(infoCell, issuedType, receiver, big.NewInt(900)))
+	assert.NoError(t, builder.AddChangeOutputByAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq02cgdvd5mng9924xarf3rflqzafzmzlpsuhh83c"))
+	tx, err := builder.Build(&signer.OmnilockConfiguration{Args: args, Mode: signer.OmnolockModeAuth})
+	assert.NoError(t, err)
+
+	assert.Equal(t, infoCell.OutPoint, tx.TxView.Inputs[0].PreviousOutput)
+	newInfo, err := omnilock.DecodeToSupplyInfo(tx.TxView.OutputsData[0])
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000), newInfo.CurrentSupply)
+	assert.Equal(t, issuedType, tx.TxView.Outputs[1].Type)
+	assert.Equal(t, systemscript.EncodeSudtAmount(big.NewInt(900)), tx.TxView.OutputsData[1])
+	// signature placeholder is set for the info cell
+	witnessArgs, err := types.DeserializeWitnessArgs(tx.TxView.Witnesses[0])
+	assert.NoError(t, err)
+	assert.NotEmpty(t, witnessArgs.Lock)
+}
@@ -1,9 +1,11 @@
 package builder
 
 import (
+	"context"
 	"errors"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/address"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/rpc"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
@@ -20,8 +22,13 @@ const (
 
 type SudtTransactionBuilder struct {
 	SimpleTransactionBuilder
-	FeeRate  uint
-	SudtType *types.Script
+	FeeRate uint
+	// FeeEstimator estimates fee when it's set, otherwise fee is charged by FeeRate. It's NodeFeeEstimator if builder
+	// is created with a client, and nil otherwise.
+	FeeEstimator FeeEstimator
+	SudtType     *types.Script
+	// ChangeSplit splits change into several outputs when it's set, and SUDT change is split along with capacity
+	ChangeSplit *ChangeSplitPolicy
 
 	iterator          collector.CellIterator
 	changeOutputIndex int
@@ -47,6 +54,15 @@ func NewSudtTransactionBuilderFromSudtArgs(network types.Network, iterator colle
 	return builder
 }
 
+// NewSudtTransactionBuilderWithClient creates a builder which charges fee with NodeFeeEstimator of client, so that fee
+// follows mempool conditions
+func NewSudtTransactionBuilderWithClient(network types.Network, iterator collector.CellIterator,
+	transactionType SudtTransactionType, sudtArgs []byte, client rpc.Client) *SudtTransactionBuilder {
+	builder := NewSudtTransactionBuilderFromSudtArgs(network, iterator, transactionType, sudtArgs)
+	builder.FeeEstimator = NewNodeFeeEstimator(client, 0)
+	return builder
+}
+
 func NewSudtTransactionBuilderFromSudtOwnerAddress(network types.Network, iterator collector.CellIterator,
 	transactionType SudtTransactionType, sudtOwnerAddress string) (*SudtTransactionBuilder, error) {
 
@@ -99,120 +115,38 @@ func (r *SudtTransactionBuilder) AddChangeOutputByAddress(addr string) error {
 }
 
 func (r *SudtTransactionBuilder) Build(contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	return r.build(context.Background(), contexts...)
+}
+
+// BuildCtx is Build with ctx bound to the queries of iterator, if it implements collector.CellIteratorCtx.
+func (r *SudtTransactionBuilder) BuildCtx(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	defer bindIteratorContext(r.iterator, ctx)()
+	return r.build(ctx, contexts...)
+}
+
+func (r *SudtTransactionBuilder) build(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
 	if r.SudtType == nil {
 		return nil, errors.New("sudt type is not set")
 	}
-
+	if r.changeOutputIndex == -1 {
+		return nil, ErrChangeOutputNotSet
+	}
+	balancer := &udtBalancer{
+		builder:           &r.SimpleTransactionBuilder,
+		iterator:          r.iterator,
+		changeOutputIndex: r.changeOutputIndex,
+		feeRate:           r.FeeRate,
+		feeEstimator:      r.FeeEstimator,
+		changeSplit:       r.ChangeSplit,
+		nextInput: func() *udtInput {
+			return newUdtInput(r.getNextCell()) // only get SUDT cell
+		},
+	}
 	// If transaction type is SudtTransactionTypeTransfer, we need the change output to receive SUDT
 	if r.transactionType == SudtTransactionTypeTransfer {
-		r.Outputs[r.changeOutputIndex].Type = r.SudtType
-		r.OutputsData[r.changeOutputIndex] = systemscript.EncodeSudtAmount(big.NewInt(0))
-	}
-
-	var (
-		err              error
-		script           *types.Script
-		scriptGroup      *transaction.ScriptGroup
-		scriptGroupMap   = make(map[types.Hash]*transaction.ScriptGroup)
-		outputsCapacity  = uint64(0)
-		outputSudtAmount = big.NewInt(0)
-	)
-	for i := 0; i < len(r.Outputs); i++ {
-		outputsCapacity += r.Outputs[i].Capacity
-		data := r.OutputsData[i]
-		if err := addSudtAmount(outputSudtAmount, data); err != nil {
-			return nil, err
-		}
-		script = r.Outputs[i].Type
-		if script != nil {
-			if scriptGroup, err = getOrPutScriptGroup(scriptGroupMap, script, types.ScriptTypeType); err != nil {
-				return nil, err
-			}
-			scriptGroup.OutputIndices = append(scriptGroup.OutputIndices, uint32(i))
-			if err := executeHandlers(&r.SimpleTransactionBuilder, scriptGroup, contexts); err != nil {
-				return nil, err
-			}
-		}
-	}
-
-	var (
-		enoughCapacity   = false
-		inputsCapacity   = uint64(0)
-		inputsSudtAmount = big.NewInt(0)
-		i                = -1
-	)
-	for {
-		cell := r.getNextCell() // only get SUDT cell
-		if cell == nil {
-			break // break when can't find cell
-		}
-		r.AddInput(&types.CellInput{
-			Since:          0,
-			PreviousOutput: cell.OutPoint,
-		})
-		i += 1
-
-		// process input' LOCK
-		script = cell.Output.Lock
-		if script != nil {
-			if scriptGroup, err = getOrPutScriptGroup(scriptGroupMap, script, types.ScriptTypeLock); err != nil {
-				return nil, err
-			}
-			scriptGroup.InputIndices = append(scriptGroup.InputIndices, uint32(i))
-			if err := executeHandlers(&r.SimpleTransactionBuilder, scriptGroup, contexts...); err != nil {
-				return nil, err
-			}
-		}
-
-		// process input' TYPE
-		script = cell.Output.Type
-		if script != nil {
-			if scriptGroup, err = getOrPutScriptGroup(scriptGroupMap, script, types.ScriptTypeType); err != nil {
-				return nil, err
-			}
-			scriptGroup.InputIndices = append(scriptGroup.InputIndices, uint32(i))
-			if err := executeHandlers(&r.SimpleTransactionBuilder, scriptGroup, contexts...); err != nil {
-				return nil, err
-			}
-		}
-
-		inputsCapacity += cell.Output.Capacity
-		if err := addSudtAmount(inputsSudtAmount, cell.OutputData); err != nil {
-			return nil, err
-		}
-		// continue to iterator if no enough SUDT amount in transfer mod
-		if r.transactionType == SudtTransactionTypeTransfer && inputsSudtAmount.Cmp(outputSudtAmount) < 0 {
-			continue
-		}
-
-		tx := r.BuildTransaction().TxView
-		// check if there is enough capacity for output capacity and change
-		fee := tx.CalculateFee(uint64(r.FeeRate))
-		if inputsCapacity < (outputsCapacity + fee) {
-			continue
-		}
-		changeCapacity := inputsCapacity - outputsCapacity - fee
-		changeOutput := r.Outputs[r.changeOutputIndex]
-		changeOutputData := r.OutputsData[r.changeOutputIndex]
-		if changeCapacity >= changeOutput.OccupiedCapacity(changeOutputData) {
-			changeOutput.Capacity = changeCapacity
-			if r.transactionType == SudtTransactionTypeTransfer {
-				diff := big.NewInt(0)
-				diff.Sub(inputsSudtAmount, outputSudtAmount)
-				r.OutputsData[r.changeOutputIndex] = systemscript.EncodeSudtAmount(diff)
-			}
-			enoughCapacity = true
-			break
-		}
-	}
-	if !enoughCapacity {
-		return nil, errors.New("no enough capacity")
-	}
-	r.scriptGroups = make([]*transaction.ScriptGroup, 0)
-	for _, g := range scriptGroupMap {
-		r.scriptGroups = append(r.scriptGroups, g)
+		balancer.udtType = r.SudtType
 	}
-	return r.BuildTransaction(), nil
+	return balancer.build(ctx, contexts...)
 }
 
 func (r *SudtTransactionBuilder) getNextCell() *types.TransactionInput {
@@ -0,0 +1,229 @@
+package builder
+
+import (
+	"context"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"math/big"
+	"reflect"
+)
+
+// udtInput is an input to collect, with its since and the contexts to handle its lock script. Contexts of build are
+// used if lockContexts is nil.
+type udtInput struct {
+	cell         *types.TransactionInput
+	since        uint64
+	lockContexts []interface{}
+}
+
+func newUdtInput(cell *types.TransactionInput) *udtInput {
+	if cell == nil {
+		return nil
+	}
+	return &udtInput{cell: cell}
+}
+
+// udtBalancer collects inputs of a UDT transaction until they cover the UDT amount and capacity of outputs, and puts
+// the balance into the change output. Builders customize it by hooks.
+type udtBalancer struct {
+	builder           *SimpleTransactionBuilder
+	iterator          collector.CellIterator
+	changeOutputIndex int
+	feeRate           uint
+	feeEstimator      FeeEstimator
+	changeSplit       *ChangeSplitPolicy
+	// udtType is the UDT which inputs should cover and the change output receives, or nil if amounts aren't checked
+	udtType *types.Script
+
+	// nextInput returns the next input to collect, or nil if there is no more
+	nextInput func() *udtInput
+	// ready returns false if collected inputs can't unlock the transaction yet, e.g. an input of owner is required
+	ready func() bool
+	// notReadyError is returned instead of InsufficientCapacityError when inputs run out before ready
+	notReadyError error
+	// addAmount adds the UDT amount in cell data, which is addSudtAmount if it's nil
+	addAmount func(amount *big.Int, data []byte) error
+	// typeContexts returns the contexts to handle the type script of an input or output, or nil to use contexts of build
+	typeContexts func(script *types.Script, isInput bool) []interface{}
+}
+
+func (b *udtBalancer) build(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	r := b.builder
+	if b.udtType != nil {
+		r.Outputs[b.changeOutputIndex].Type = b.udtType
+		r.OutputsData[b.changeOutputIndex] = systemscript.EncodeSudtAmount(big.NewInt(0))
+	}
+
+	var (
+		err             error
+		script          *types.Script
+		scriptGroup     *transaction.ScriptGroup
+		scriptGroupMap  = make(map[types.Hash]*transaction.ScriptGroup)
+		outputsCapacity = uint64(0)
+		outputsAmount   = big.NewInt(0)
+	)
+	for i := 0; i < len(r.Outputs); i++ {
+		outputsCapacity += r.Outputs[i].Capacity
+		script = r.Outputs[i].Type
+		if script != nil {
+			if b.isUdt(script) {
+				if err := b.addUdtAmount(outputsAmount, r.OutputsData[i]); err != nil {
+					return nil, err
+				}
+			}
+			if scriptGroup, err = getOrPutScriptGroup(scriptGroupMap, script, types.ScriptTypeType); err != nil {
+				return nil, err
+			}
+			scriptGroup.OutputIndices = append(scriptGroup.OutputIndices, uint32(i))
+			if err := executeHandlers(r, scriptGroup, b.contextsOfType(script, false, contexts)...); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var (
+		enoughCapacity = false
+		inputsCapacity = uint64(0)
+		inputsAmount   = big.NewInt(0)
+		required       = outputsCapacity
+		i              = -1
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		input := b.nextInput()
+		if input == nil {
+			break // break when can't find cell
+		}
+		cell := input.cell
+		r.AddInput(&types.CellInput{
+			Since:          input.since,
+			PreviousOutput: cell.OutPoint,
+		})
+		i += 1
+
+		// process input's LOCK
+		script = cell.Output.Lock
+		if script != nil {
+			lockContexts := input.lockContexts
+			if lockContexts == nil {
+				lockContexts = contexts
+			}
+			if scriptGroup, err = getOrPutScriptGroup(scriptGroupMap, script, types.ScriptTypeLock); err != nil {
+				return nil, err
+			}
+			scriptGroup.InputIndices = append(scriptGroup.InputIndices, uint32(i))
+			if err := executeHandlers(r, scriptGroup, lockContexts...); err != nil {
+				return nil, err
+			}
+		}
+
+		// process input's TYPE
+		script = cell.Output.Type
+		if script != nil {
+			if b.isUdt(script) {
+				if err := b.addUdtAmount(inputsAmount, cell.OutputData); err != nil {
+					return nil, err
+				}
+			}
+			if scriptGroup, err = getOrPutScriptGroup(scriptGroupMap, script, types.ScriptTypeType); err != nil {
+				return nil, err
+			}
+			scriptGroup.InputIndices = append(scriptGroup.InputIndices, uint32(i))
+			if err := executeHandlers(r, scriptGroup, b.contextsOfType(script, true, contexts)...); err != nil {
+				return nil, err
+			}
+		}
+
+		inputsCapacity += cell.Output.Capacity
+		// continue to iterator if no enough UDT amount
+		if b.udtType != nil && inputsAmount.Cmp(outputsAmount) < 0 {
+			continue
+		}
+		if b.ready != nil && !b.ready() {
+			continue
+		}
+
+		tx := r.BuildTransaction().TxView
+		// check if there is enough capacity for output capacity and change
+		fee, err := estimateFee(ctx, b.feeEstimator, b.feeRate, tx)
+		if err != nil {
+			return nil, err
+		}
+		required = outputsCapacity + fee
+		if inputsCapacity < required {
+			continue
+		}
+		changeCapacity := inputsCapacity - required
+		changeOutput := r.Outputs[b.changeOutputIndex]
+		changeOutputData := r.OutputsData[b.changeOutputIndex]
+		if changeCapacity >= changeOutput.OccupiedCapacity(changeOutputData) {
+			changeOutput.Capacity = changeCapacity
+			// re-balance change, or collect more cells if change can't afford fee by weight
+			charged, err := estimateFeeByWeight(ctx, b.feeEstimator, r, scriptGroupMap, fee)
+			if err != nil {
+				return nil, err
+			}
+			if charged > fee {
+				extra := charged - fee
+				if changeCapacity < extra || changeCapacity-extra < changeOutput.OccupiedCapacity(changeOutputData) {
+					continue
+				}
+				changeOutput.Capacity = changeCapacity - extra
+			}
+			var udtChange *big.Int
+			if b.udtType != nil {
+				udtChange = new(big.Int).Sub(inputsAmount, outputsAmount)
+				r.OutputsData[b.changeOutputIndex] = systemscript.EncodeSudtAmount(udtChange)
+			}
+			if b.changeSplit != nil {
+				if err := splitChange(ctx, r, b.changeSplit, b.feeEstimator, b.feeRate, b.changeOutputIndex, charged, udtChange, scriptGroupMap); err != nil {
+					return nil, err
+				}
+			}
+			enoughCapacity = true
+			break
+		}
+	}
+	// iterator stops when its query is cancelled or fails
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := iteratorError(b.iterator); err != nil {
+		return nil, err
+	}
+	if !enoughCapacity && b.notReadyError != nil && b.ready != nil && !b.ready() {
+		return nil, b.notReadyError
+	}
+	if !enoughCapacity {
+		return nil, &InsufficientCapacityError{InputsCapacity: inputsCapacity, Required: required}
+	}
+	r.scriptGroups = make([]*transaction.ScriptGroup, 0)
+	for _, g := range scriptGroupMap {
+		r.scriptGroups = append(r.scriptGroups, g)
+	}
+	return r.BuildTransaction(), nil
+}
+
+func (b *udtBalancer) isUdt(script *types.Script) bool {
+	return b.udtType != nil && reflect.DeepEqual(script, b.udtType)
+}
+
+func (b *udtBalancer) addUdtAmount(amount *big.Int, data []byte) error {
+	if b.addAmount != nil {
+		return b.addAmount(amount, data)
+	}
+	return addSudtAmount(amount, data)
+}
+
+func (b *udtBalancer) contextsOfType(script *types.Script, isInput bool, contexts []interface{}) []interface{} {
+	if b.typeContexts != nil {
+		if typeContexts := b.typeContexts(script, isInput); typeContexts != nil {
+			return typeContexts
+		}
+	}
+	return contexts
+}
@@ -0,0 +1,208 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/address"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector/handler"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"math/big"
+	"reflect"
+)
+
+type XudtTransactionType uint
+
+const (
+	XudtTransactionTypeIssue XudtTransactionType = iota
+	XudtTransactionTypeTransfer
+)
+
+// XudtTransactionBuilder issues or transfers xUDT. In issue mode, iterator should return owner's cells, which are
+// collected for capacity and prove ownership. In transfer mode, iterator should return xUDT cells of XudtType.
+type XudtTransactionBuilder struct {
+	SimpleTransactionBuilder
+	FeeRate uint
+	// FeeEstimator estimates fee when it's set, otherwise fee is charged by FeeRate. It's nil by default, set it to e.g.
+	// NodeFeeEstimator or CycleFeeEstimator to opt in.
+	FeeEstimator FeeEstimator
+	XudtType     *types.Script
+	// XudtInfo provides extension scripts and their data for xUDT witness when it's set
+	XudtInfo *handler.XudtInfo
+
+	iterator          collector.CellIterator
+	changeOutputIndex int
+	transactionType   XudtTransactionType
+}
+
+func NewXudtTransactionBuilderFromXudtArgs(network types.Network, iterator collector.CellIterator,
+	transactionType XudtTransactionType, xudtArgs []byte) *XudtTransactionBuilder {
+	return &XudtTransactionBuilder{
+		SimpleTransactionBuilder: *NewSimpleTransactionBuilder(network),
+		FeeRate:                  1000,
+		XudtType:                 systemscript.NewScript(systemscript.Xudt, xudtArgs, network),
+
+		iterator:          iterator,
+		changeOutputIndex: -1,
+		transactionType:   transactionType,
+	}
+}
+
+// NewXudtTransactionBuilderFromXudtOwnerAddress creates builder of xUDT owned by the lock of address, without flags
+// or extension scripts.
+func NewXudtTransactionBuilderFromXudtOwnerAddress(network types.Network, iterator collector.CellIterator,
+	transactionType XudtTransactionType, xudtOwnerAddress string) (*XudtTransactionBuilder, error) {
+	addr, err := address.Decode(xudtOwnerAddress)
+	if err != nil {
+		return nil, err
+	}
+	xudtArgs := systemscript.NewXudtArgs(addr.Script).Encode()
+	return NewXudtTransactionBuilderFromXudtArgs(network, iterator, transactionType, xudtArgs), nil
+}
+
+func (r *XudtTransactionBuilder) AddXudtOutputByAddress(addr string, xudtAmount *big.Int) (int, error) {
+	a, err := address.Decode(addr)
+	if err != nil {
+		return 0, err
+	}
+	output := &types.CellOutput{
+		Capacity: 0,
+		Lock:     a.Script,
+		Type:     r.XudtType,
+	}
+	data := systemscript.EncodeSudtAmount(xudtAmount)
+	output.Capacity = output.OccupiedCapacity(data)
+	return r.AddOutput(output, data), nil
+}
+
+func (r *XudtTransactionBuilder) AddXudtOutputWithCapacityByAddress(addr string, capacity uint64, xudtAmount *big.Int) (int, error) {
+	a, err := address.Decode(addr)
+	if err != nil {
+		return 0, err
+	}
+	output := &types.CellOutput{
+		Capacity: capacity,
+		Lock:     a.Script,
+		Type:     r.XudtType,
+	}
+	data := systemscript.EncodeSudtAmount(xudtAmount)
+	return r.AddOutput(output, data), nil
+}
+
+func (r *XudtTransactionBuilder) AddChangeOutputByAddress(addr string) error {
+	if r.changeOutputIndex != -1 {
+		return errors.New("change output has been set")
+	}
+	err := r.AddOutputByAddress(addr, 0)
+	if err == nil {
+		r.changeOutputIndex = len(r.Outputs) - 1
+	}
+	return err
+}
+
+func (r *XudtTransactionBuilder) Build(contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	return r.build(context.Background(), contexts...)
+}
+
+// BuildCtx is Build with ctx bound to the queries of iterator, if it implements collector.CellIteratorCtx.
+func (r *XudtTransactionBuilder) BuildCtx(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	defer bindIteratorContext(r.iterator, ctx)()
+	return r.build(ctx, contexts...)
+}
+
+func (r *XudtTransactionBuilder) build(ctx context.Context, contexts ...interface{}) (*transaction.TransactionWithScriptGroups, error) {
+	if r.XudtType == nil {
+		return nil, errors.New("xudt type is not set")
+	}
+	if r.changeOutputIndex == -1 {
+		return nil, ErrChangeOutputNotSet
+	}
+	xudtArgs, err := systemscript.DecodeToXudtArgs(r.XudtType.Args)
+	if err != nil {
+		return nil, err
+	}
+	// xUDT witness is put in the first input in transfer mode, or the first output in issue mode
+	info := r.XudtInfo
+	if info == nil {
+		info = &handler.XudtInfo{}
+	}
+	xudtContexts := append(append([]interface{}{}, contexts...), info)
+
+	ownerFound := false
+	balancer := &udtBalancer{
+		builder:           &r.SimpleTransactionBuilder,
+		iterator:          r.iterator,
+		changeOutputIndex: r.changeOutputIndex,
+		feeRate:           r.FeeRate,
+		feeEstimator:      r.FeeEstimator,
+		addAmount:         addXudtAmount,
+		nextInput: func() *udtInput {
+			cell := r.getNextCell()
+			if cell != nil && isXudtOwner(xudtArgs, cell.Output) {
+				ownerFound = true
+			}
+			return newUdtInput(cell)
+		},
+		typeContexts: func(script *types.Script, isInput bool) []interface{} {
+			if (isInput || r.transactionType == XudtTransactionTypeIssue) && reflect.DeepEqual(script, r.XudtType) {
+				return xudtContexts
+			}
+			return nil
+		},
+	}
+	// The change output receives xUDT change when transferring
+	if r.transactionType == XudtTransactionTypeTransfer {
+		balancer.udtType = r.XudtType
+	}
+	// issuing requires an input of owner
+	if r.transactionType == XudtTransactionTypeIssue {
+		balancer.ready = func() bool {
+			return ownerFound
+		}
+		balancer.notReadyError = errors.New("no input is owned by xudt owner")
+	}
+	return balancer.build(ctx, contexts...)
+}
+
+func (r *XudtTransactionBuilder) getNextCell() *types.TransactionInput {
+	for {
+		if !r.iterator.HasNext() {
+			return nil
+		}
+		cell := r.iterator.Next()
+		// issuing collects owner's cells without type or with owner type, and transferring collects xUDT cells
+		if r.transactionType == XudtTransactionTypeIssue && (cell.Output.Type == nil || r.isOwnerType(cell.Output.Type)) {
+			return cell
+		}
+		if r.transactionType == XudtTransactionTypeTransfer && reflect.DeepEqual(cell.Output.Type, r.XudtType) {
+			return cell
+		}
+	}
+}
+
+// isOwnerType checks if the type script enables owner mode by input type
+func (r *XudtTransactionBuilder) isOwnerType(script *types.Script) bool {
+	args, err := systemscript.DecodeToXudtArgs(r.XudtType.Args)
+	if err != nil {
+		return false
+	}
+	return args.Flags&systemscript.XudtOwnerModeInputType != 0 && script.Hash() == args.OwnerHash
+}
+
+// isXudtOwner checks if the cell enables owner mode, by its lock script hash or type script hash as flags tell
+func isXudtOwner(args *systemscript.XudtArgs, output *types.CellOutput) bool {
+	if args.Flags&systemscript.XudtOwnerModeInputLockDisabled == 0 && output.Lock != nil && output.Lock.Hash() == args.OwnerHash {
+		return true
+	}
+	return args.Flags&systemscript.XudtOwnerModeInputType != 0 && output.Type != nil && output.Type.Hash() == args.OwnerHash
+}
+
+// addXudtAmount adds the amount in the first 16 bytes of xUDT data, which may be followed by extension data
+func addXudtAmount(a *big.Int, b []byte) error {
+	if len(b) < 16 {
+		return errors.New("xudt data should be at least 16 bytes")
+	}
+	return addSudtAmount(a, b[:16])
+}
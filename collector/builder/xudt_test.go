@@ -0,0 +1,104 @@
+package builder
+
+import (
+	"github.com/nervosnetwork/ckb-sdk-go/v2/address"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector/handler"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+var xudtOwner = &address.Address{Script: lock, Network: types.NetworkTest}
+
+func getXudtMockIterator(xudtType *types.Script) *mockIterator {
+	iterator := getMockIterator()
+	for i, cell := range iterator.Cells {
+		cell.Output.Type = xudtType
+		cell.OutputData = append(systemscript.EncodeSudtAmount(big.NewInt(int64(100*(i+1)))), 0xff)
+	}
+	return iterator
+}
+
+func TestXudtTransactionBuilderIssue(t *testing.T) {
+	builder, err := NewXudtTransactionBuilderFromXudtOwnerAddress(types.NetworkTest, getMockIterator(), XudtTransactionTypeIssue, encodeAddress(t, xudtOwner))
+	assert.NoError(t, err)
+	_, err = builder.AddXudtOutputByAddress(encodeAddress(t, chequeReceiver), big.NewInt(1000))
+	assert.NoError(t, err)
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, xudtOwner)))
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(tx.TxView.Inputs))
+	assert.Equal(t, lock.Hash().Bytes(), tx.TxView.Outputs[0].Type.Args)
+	assert.Equal(t, systemscript.GetCodeHash(types.NetworkTest, systemscript.Xudt), tx.TxView.Outputs[0].Type.CodeHash)
+	assert.Equal(t, systemscript.EncodeSudtAmount(big.NewInt(1000)), tx.TxView.OutputsData[0])
+	assert.Nil(t, tx.TxView.Outputs[1].Type)
+	// no xUDT witness without extension scripts
+	witnessArgs, err := types.DeserializeWitnessArgs(tx.TxView.Witnesses[0])
+	assert.NoError(t, err)
+	assert.Nil(t, witnessArgs.OutputType)
+
+	// cells of iterator are not owned
+	builder, err = NewXudtTransactionBuilderFromXudtOwnerAddress(types.NetworkTest, getMockIterator(), XudtTransactionTypeIssue, encodeAddress(t, chequeReceiver))
+	assert.NoError(t, err)
+	_, err = builder.AddXudtOutputByAddress(encodeAddress(t, chequeReceiver), big.NewInt(1000))
+	assert.NoError(t, err)
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, xudtOwner)))
+	_, err = builder.Build()
+	assert.EqualError(t, err, "no input is owned by xudt owner")
+}
+
+func TestXudtTransactionBuilderExtensionWitness(t *testing.T) {
+	extension := &types.Script{CodeHash: types.HexToHash("0x01"), HashType: types.HashTypeData1, Args: []byte{}}
+	args := systemscript.NewXudtArgs(lock)
+	args.Flags = systemscript.XudtFlagsExtensionScriptsHash
+	args.ExtensionScripts = []*types.Script{extension}
+	builder := NewXudtTransactionBuilderFromXudtArgs(types.NetworkTest, getMockIterator(), XudtTransactionTypeIssue, args.Encode())
+	_, err := builder.AddXudtOutputByAddress(encodeAddress(t, chequeReceiver), big.NewInt(1000))
+	assert.NoError(t, err)
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, xudtOwner)))
+	// extension scripts are required
+	_, err = builder.Build()
+	assert.Error(t, err)
+
+	builder = NewXudtTransactionBuilderFromXudtArgs(types.NetworkTest, getMockIterator(), XudtTransactionTypeIssue, args.Encode())
+	builder.XudtInfo = &handler.XudtInfo{ExtensionScripts: args.ExtensionScripts}
+	_, err = builder.AddXudtOutputByAddress(encodeAddress(t, chequeReceiver), big.NewInt(1000))
+	assert.NoError(t, err)
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, xudtOwner)))
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+	witnessArgs, err := types.DeserializeWitnessArgs(tx.TxView.Witnesses[0])
+	assert.NoError(t, err)
+	expected := &systemscript.XudtWitness{
+		RawExtensionData: args.ExtensionScripts,
+		ExtensionData:    [][]byte{{}},
+	}
+	assert.Equal(t, expected.Serialize(), witnessArgs.OutputType)
+	assert.Equal(t, 65, len(witnessArgs.Lock))
+	assert.Equal(t, 1, len(tx.TxView.Witnesses))
+}
+
+func TestXudtTransactionBuilderTransfer(t *testing.T) {
+	xudtType := systemscript.NewScript(systemscript.Xudt, systemscript.NewXudtArgs(lock).Encode(), types.NetworkTest)
+	builder := NewXudtTransactionBuilderFromXudtArgs(types.NetworkTest, getXudtMockIterator(xudtType), XudtTransactionTypeTransfer, xudtType.Args)
+	_, err := builder.AddXudtOutputByAddress(encodeAddress(t, chequeReceiver), big.NewInt(150))
+	assert.NoError(t, err)
+	assert.NoError(t, builder.AddChangeOutputByAddress(encodeAddress(t, xudtOwner)))
+	tx, err := builder.Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, len(tx.TxView.Inputs))
+	assert.Equal(t, xudtType, tx.TxView.Outputs[1].Type)
+	assert.Equal(t, systemscript.EncodeSudtAmount(big.NewInt(150)), tx.TxView.OutputsData[1])
+	cellDep := systemscript.GetInfo(types.NetworkTest, systemscript.Xudt).OutPoint
+	found := false
+	for _, dep := range tx.TxView.CellDeps {
+		if *dep.OutPoint == *cellDep {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
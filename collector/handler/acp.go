@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"reflect"
+)
+
+// AnyoneCanPayPaymentInfo tells that cells of the anyone-can-pay lock are paid by others, in which case they are
+// unlocked without signature.
+type AnyoneCanPayPaymentInfo struct {
+}
+
+type AnyoneCanPayScriptHandler struct {
+	CellDep  *types.CellDep
+	CodeHash types.Hash
+}
+
+func NewAnyoneCanPayScriptHandler(network types.Network) *AnyoneCanPayScriptHandler {
+	info := systemscript.GetInfo(network, systemscript.AnyoneCanPay)
+	if info == nil {
+		return nil
+	}
+	return &AnyoneCanPayScriptHandler{
+		CellDep:  info.CellDep(),
+		CodeHash: info.CodeHash,
+	}
+}
+
+func (r *AnyoneCanPayScriptHandler) isMatched(script *types.Script) bool {
+	if script == nil {
+		return false
+	}
+	return reflect.DeepEqual(script.CodeHash, r.CodeHash)
+}
+
+func (r *AnyoneCanPayScriptHandler) BuildTransaction(builder collector.TransactionBuilder, group *transaction.ScriptGroup, context interface{}) (bool, error) {
+	if group == nil || !r.isMatched(group.Script) {
+		return false, nil
+	}
+	builder.AddCellDep(r.CellDep)
+	switch context.(type) {
+	case AnyoneCanPayPaymentInfo, *AnyoneCanPayPaymentInfo:
+		return true, nil
+	}
+	// cells spent by owner are unlocked by signature
+	index := group.InputIndices[0]
+	lock := [65]byte{}
+	if err := builder.SetWitness(uint(index), types.WitnessTypeLock, lock[:]); err != nil {
+		return false, err
+	}
+	return true, nil
+}
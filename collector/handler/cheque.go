@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"reflect"
+)
+
+// ChequeLockPeriodEpochs is the number of epochs after which sender can withdraw a cheque cell
+const ChequeLockPeriodEpochs = 6
+
+// ChequeUnlockInfo tells how cheque cells are unlocked. When UnlockByLockHash is true, there is an input locked by
+// receiver's lock (for claim) or sender's lock (for withdraw) in transaction, so signature is not required.
+type ChequeUnlockInfo struct {
+	UnlockByLockHash bool
+}
+
+type ChequeScriptHandler struct {
+	CellDep  *types.CellDep
+	CodeHash types.Hash
+}
+
+func NewChequeScriptHandler(network types.Network) *ChequeScriptHandler {
+	info := systemscript.GetInfo(network, systemscript.Cheque)
+	if info == nil {
+		return nil
+	}
+	return &ChequeScriptHandler{
+		CellDep:  info.CellDep(),
+		CodeHash: info.CodeHash,
+	}
+}
+
+func (r *ChequeScriptHandler) isMatched(script *types.Script) bool {
+	if script == nil {
+		return false
+	}
+	return reflect.DeepEqual(script.CodeHash, r.CodeHash)
+}
+
+func (r *ChequeScriptHandler) BuildTransaction(builder collector.TransactionBuilder, group *transaction.ScriptGroup, context interface{}) (bool, error) {
+	if group == nil || !r.isMatched(group.Script) {
+		return false, nil
+	}
+	var info *ChequeUnlockInfo
+	switch context.(type) {
+	case ChequeUnlockInfo, *ChequeUnlockInfo:
+		var ok bool
+		if info, ok = context.(*ChequeUnlockInfo); !ok {
+			v, _ := context.(ChequeUnlockInfo)
+			info = &v
+		}
+	default:
+		return false, nil
+	}
+	builder.AddCellDep(r.CellDep)
+	if !info.UnlockByLockHash {
+		index := group.InputIndices[0]
+		lock := [65]byte{}
+		if err := builder.SetWitness(uint(index), types.WitnessTypeLock, lock[:]); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -14,24 +14,13 @@ type Secp256k1Blake160SighashAllScriptHandler struct {
 }
 
 func NewSecp256k1Blake160SighashAllScriptHandler(network types.Network) *Secp256k1Blake160SighashAllScriptHandler {
-	var txHash types.Hash
-	if network == types.NetworkMain {
-		txHash = types.HexToHash("0x71a7ba8fc96349fea0ed3a5c47992e3b4084b031a42264a018e0072e8172e46c")
-	} else if network == types.NetworkTest {
-		txHash = types.HexToHash("0xf8de3bb47d055cdf460d93a2a6e1b05f7432f9777c8c474abf4eec1d4aee5d37")
-	} else {
+	info := systemscript.GetInfo(network, systemscript.Secp256k1Blake160SighashAll)
+	if info == nil {
 		return nil
 	}
-
 	return &Secp256k1Blake160SighashAllScriptHandler{
-		CellDep: &types.CellDep{
-			OutPoint: &types.OutPoint{
-				TxHash: txHash,
-				Index:  0,
-			},
-			DepType: types.DepTypeDepGroup,
-		},
-		CodeHash: systemscript.GetCodeHash(network, systemscript.Secp256k1Blake160SighashAll),
+		CellDep:  info.CellDep(),
+		CodeHash: info.CodeHash,
 	}
 }
 
@@ -61,23 +50,12 @@ type Secp256k1Blake160MultisigAllScriptHandler struct {
 }
 
 func NewSecp256k1Blake160MultisigAllScriptHandler(network types.Network) *Secp256k1Blake160MultisigAllScriptHandler {
-	var txHash types.Hash
-	if network == types.NetworkMain {
-		txHash = types.HexToHash("0x71a7ba8fc96349fea0ed3a5c47992e3b4084b031a42264a018e0072e8172e46c")
-	} else if network == types.NetworkTest {
-		txHash = types.HexToHash("0xf8de3bb47d055cdf460d93a2a6e1b05f7432f9777c8c474abf4eec1d4aee5d37")
-	} else {
+	info := systemscript.GetInfo(network, systemscript.Secp256k1Blake160MultisigAll)
+	if info == nil {
 		return nil
 	}
-
 	return &Secp256k1Blake160MultisigAllScriptHandler{
-		cellDep: &types.CellDep{
-			OutPoint: &types.OutPoint{
-				TxHash: txHash,
-				Index:  1,
-			},
-			DepType: types.DepTypeDepGroup,
-		},
+		cellDep: info.CellDep(),
 		network: network,
 	}
 }
@@ -29,24 +29,13 @@ type DaoScriptHandler struct {
 }
 
 func NewDaoScriptHandler(network types.Network) *DaoScriptHandler {
-	var txHash types.Hash
-	if network == types.NetworkMain {
-		txHash = types.HexToHash("0xe2fb199810d49a4d8beec56718ba2593b665db9d52299a0f9e6e75416d73ff5c")
-	} else if network == types.NetworkTest {
-		txHash = types.HexToHash("0x8f8c79eb6671709633fe6a46de93c0fedc9c1b8a6527a18d3983879542635c9f")
-	} else {
+	info := systemscript.GetInfo(network, systemscript.Dao)
+	if info == nil {
 		return nil
 	}
-
 	return &DaoScriptHandler{
-		CellDep: &types.CellDep{
-			OutPoint: &types.OutPoint{
-				TxHash: txHash,
-				Index:  2,
-			},
-			DepType: types.DepTypeCode,
-		},
-		CodeHash: systemscript.GetCodeHash(network, systemscript.Dao),
+		CellDep:  info.CellDep(),
+		CodeHash: info.CodeHash,
 	}
 }
 
@@ -102,7 +91,11 @@ type ClaimInfo struct {
 }
 
 func NewClaimInfo(client rpc.Client, withdrawOutpoint *types.OutPoint) (*ClaimInfo, error) {
-	txWithStatus, err := client.GetTransaction(context.Background(), withdrawOutpoint.TxHash)
+	return NewClaimInfoCtx(context.Background(), client, withdrawOutpoint)
+}
+
+func NewClaimInfoCtx(ctx context.Context, client rpc.Client, withdrawOutpoint *types.OutPoint) (*ClaimInfo, error) {
+	txWithStatus, err := client.GetTransaction(ctx, withdrawOutpoint.TxHash)
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +104,7 @@ func NewClaimInfo(client rpc.Client, withdrawOutpoint *types.OutPoint) (*ClaimIn
 	var depositBlockHash types.Hash
 	for i := 0; i < len(withdrawTx.Inputs); i++ {
 		outPoint := withdrawTx.Inputs[i].PreviousOutput
-		txWithStatus, err := client.GetTransaction(context.Background(), outPoint.TxHash)
+		txWithStatus, err := client.GetTransaction(ctx, outPoint.TxHash)
 		if err != nil {
 			return nil, err
 		}
@@ -125,11 +118,11 @@ func NewClaimInfo(client rpc.Client, withdrawOutpoint *types.OutPoint) (*ClaimIn
 	if reflect.DeepEqual(depositBlockHash, types.Hash{}) {
 		return nil, errors.New("can't find deposit cell")
 	}
-	depositBlockHeader, err := client.GetHeader(context.Background(), depositBlockHash)
+	depositBlockHeader, err := client.GetHeader(ctx, depositBlockHash)
 	if err != nil {
 		return nil, err
 	}
-	withdrawBlockHeader, err := client.GetHeader(context.Background(), *withdrawBlockHash)
+	withdrawBlockHeader, err := client.GetHeader(ctx, *withdrawBlockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -175,12 +168,16 @@ type WithdrawInfo struct {
 }
 
 func NewWithdrawInfo(client rpc.Client, depositOutPoint *types.OutPoint) (*WithdrawInfo, error) {
-	txWithStatus, err := client.GetTransaction(context.Background(), depositOutPoint.TxHash)
+	return NewWithdrawInfoCtx(context.Background(), client, depositOutPoint)
+}
+
+func NewWithdrawInfoCtx(ctx context.Context, client rpc.Client, depositOutPoint *types.OutPoint) (*WithdrawInfo, error) {
+	txWithStatus, err := client.GetTransaction(ctx, depositOutPoint.TxHash)
 	if err != nil {
 		return nil, err
 	}
 	depositBlockHash := txWithStatus.TxStatus.BlockHash
-	header, err := client.GetHeader(context.Background(), *depositBlockHash)
+	header, err := client.GetHeader(ctx, *depositBlockHash)
 	if err != nil {
 		return nil, err
 	}
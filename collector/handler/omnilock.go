@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
@@ -19,54 +20,18 @@ type OmnilockScriptHandler struct {
 }
 
 func NewOmnilockScriptHandler(network types.Network) *OmnilockScriptHandler {
-	switch network {
-	case types.NetworkTest:
-		return &OmnilockScriptHandler{
-			SingleSignCellDep: &types.CellDep{
-				OutPoint: &types.OutPoint{
-					TxHash: types.HexToHash("0xf8de3bb47d055cdf460d93a2a6e1b05f7432f9777c8c474abf4eec1d4aee5d37"),
-					Index:  0,
-				},
-				DepType: types.DepTypeDepGroup,
-			},
-			MultiSignCellDep: &types.CellDep{
-				OutPoint: &types.OutPoint{
-					TxHash: types.HexToHash("0xf8de3bb47d055cdf460d93a2a6e1b05f7432f9777c8c474abf4eec1d4aee5d37"),
-					Index:  1,
-				},
-				DepType: types.DepTypeDepGroup,
-			},
-			CellDep: &types.CellDep{
-				OutPoint: systemscript.GetInfo(network, systemscript.Omnilock).OutPoint,
-				DepType:  systemscript.GetInfo(network, systemscript.Omnilock).DepType,
-			},
-			CodeHash: systemscript.GetCodeHash(network, systemscript.Omnilock),
-		}
-	case types.NetworkMain:
-		return &OmnilockScriptHandler{
-			SingleSignCellDep: &types.CellDep{
-				OutPoint: &types.OutPoint{
-					TxHash: types.HexToHash("0x71a7ba8fc96349fea0ed3a5c47992e3b4084b031a42264a018e0072e8172e46c"),
-					Index:  0,
-				},
-				DepType: types.DepTypeDepGroup,
-			},
-			MultiSignCellDep: &types.CellDep{
-				OutPoint: &types.OutPoint{
-					TxHash: types.HexToHash("0x71a7ba8fc96349fea0ed3a5c47992e3b4084b031a42264a018e0072e8172e46c"),
-					Index:  1,
-				},
-				DepType: types.DepTypeDepGroup,
-			},
-			CellDep: &types.CellDep{
-				OutPoint: systemscript.GetInfo(network, systemscript.Omnilock).OutPoint,
-				DepType:  systemscript.GetInfo(network, systemscript.Omnilock).DepType,
-			},
-			CodeHash: systemscript.GetCodeHash(network, systemscript.Omnilock),
-		}
-	default:
+	info := systemscript.GetInfo(network, systemscript.Omnilock)
+	singleSign := systemscript.GetInfo(network, systemscript.Secp256k1Blake160SighashAll)
+	multiSign := systemscript.GetInfo(network, systemscript.Secp256k1Blake160MultisigAll)
+	if info == nil || singleSign == nil || multiSign == nil {
 		return nil
 	}
+	return &OmnilockScriptHandler{
+		SingleSignCellDep: singleSign.CellDep(),
+		MultiSignCellDep:  multiSign.CellDep(),
+		CellDep:           info.CellDep(),
+		CodeHash:          info.CodeHash,
+	}
 }
 
 func (o *OmnilockScriptHandler) BuildTransaction(builder collector.TransactionBuilder, group *transaction.ScriptGroup, context interface{}) (bool, error) {
@@ -74,6 +39,9 @@ func (o *OmnilockScriptHandler) BuildTransaction(builder collector.TransactionBu
 		return false, nil
 	}
 	if config, ok := context.(*signer.OmnilockConfiguration); ok {
+		if config.Args != nil && !bytes.Equal(group.Script.Args, config.Args.Encode()) {
+			return false, nil
+		}
 		builder.AddCellDep(o.CellDep)
 
 		switch config.Mode {
@@ -81,6 +49,8 @@ func (o *OmnilockScriptHandler) BuildTransaction(builder collector.TransactionBu
 			return o.buildTransactionForAuthMode(builder, group, config)
 		case signer.OmnolockModeAdministrator:
 			return o.buildTransactionForAdministratorMode(builder, group, config)
+		case signer.OmnolockModeAnyoneCanPay:
+			return o.buildTransactionForAnyoneCanPayMode(builder, group, config)
 		default:
 			return false, fmt.Errorf("unknown Omnilock mode %d", config.Mode)
 		}
@@ -92,19 +62,13 @@ func (o *OmnilockScriptHandler) BuildTransaction(builder collector.TransactionBu
 func (o *OmnilockScriptHandler) buildTransactionForAuthMode(builder collector.TransactionBuilder, group *transaction.ScriptGroup, configuration *signer.OmnilockConfiguration) (bool, error) {
 	omnilockWitnessLock := new(omnilock.OmnilockWitnessLock)
 	switch configuration.Args.Authentication.Flag {
-	case omnilock.AuthFlagCKBSecp256k1Blake160:
+	case omnilock.AuthFlagCKBSecp256k1Blake160, omnilock.AuthFlagEthereum, omnilock.AuthFlagBitcoin, omnilock.AuthFlagDogcoin:
 		builder.AddCellDep(o.SingleSignCellDep)
 		omnilockWitnessLock.Signature = make([]byte, 65)
-	case omnilock.AuthFlagEthereum:
-		return false, fmt.Errorf("unsupported flag Ethereum")
 	case omnilock.AuthFlagEOS:
 		return false, fmt.Errorf("unsupported flag EOS")
 	case omnilock.AuthFlagTRON:
 		return false, fmt.Errorf("unsupported flag TRON")
-	case omnilock.AuthFlagBitcoin:
-		return false, fmt.Errorf("unsupported flag Bitcoin")
-	case omnilock.AuthFlagDogcoin:
-		return false, fmt.Errorf("unsupported flag Dogecoin")
 	case omnilock.AuthFlagCKBMultiSig:
 		builder.AddCellDep(o.MultiSignCellDep)
 		omnilockWitnessLock.Signature = configuration.MultisigConfig.WitnessEmptyPlaceholderInLock()
@@ -116,12 +80,58 @@ func (o *OmnilockScriptHandler) buildTransactionForAuthMode(builder collector.Tr
 	default:
 		return false, fmt.Errorf("unknown auth flag %d", configuration.Args.Authentication.Flag)
 	}
+	if err := setSinceForTimeLock(builder, group, configuration); err != nil {
+		return false, err
+	}
 	builder.SetWitness(uint(group.InputIndices[0]), types.WitnessTypeLock, omnilockWitnessLock.SerializeAsPlaceholder())
 	return true, nil
 }
 
+func (o *OmnilockScriptHandler) buildTransactionForAnyoneCanPayMode(builder collector.TransactionBuilder, group *transaction.ScriptGroup, configuration *signer.OmnilockConfiguration) (bool, error) {
+	if configuration.Args == nil || !configuration.Args.OmniConfig.IsAnyoneCanPayModeEnabled() {
+		return false, fmt.Errorf("anyone-can-pay mode is not enabled in omnilock args")
+	}
+	// Omnilock checks anyone-can-pay rules when witness lock is empty, so only cell dep is needed
+	return true, nil
+}
+
+// setSinceForTimeLock sets since of all inputs in group when time-lock mode is enabled, otherwise Omnilock rejects
+// the transaction.
+func setSinceForTimeLock(builder collector.TransactionBuilder, group *transaction.ScriptGroup, configuration *signer.OmnilockConfiguration) error {
+	if configuration.Args == nil || !configuration.Args.OmniConfig.IsTimeLockModeEnabled() {
+		return nil
+	}
+	for _, i := range group.InputIndices {
+		if err := builder.SetSince(uint(i), configuration.Args.OmniConfig.SinceForTimeLock); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (o *OmnilockScriptHandler) buildTransactionForAdministratorMode(builder collector.TransactionBuilder, group *transaction.ScriptGroup, configuration *signer.OmnilockConfiguration) (bool, error) {
-	return false, fmt.Errorf("unsupported")
+	if configuration.AdminListCell == nil {
+		return false, fmt.Errorf("admin list cell is required in administrator mode")
+	}
+	if configuration.OmnilockIdentity == nil || configuration.OmnilockIdentity.Identity == nil {
+		return false, fmt.Errorf("omnilock identity is required in administrator mode")
+	}
+	builder.AddCellDep(configuration.AdminListCell)
+	omnilockWitnessLock := new(omnilock.OmnilockWitnessLock)
+	omnilockWitnessLock.OmnilockIdentity = configuration.OmnilockIdentity
+	switch configuration.OmnilockIdentity.Identity.Flag {
+	case omnilock.OmnilockFlagCKBSecp256k1Blake160:
+		builder.AddCellDep(o.SingleSignCellDep)
+		omnilockWitnessLock.Signature = make([]byte, 65)
+	case omnilock.OmnilockFlagLockScriptHash:
+	default:
+		return false, fmt.Errorf("unknown flag %d", configuration.OmnilockIdentity.Identity.Flag)
+	}
+	if err := setSinceForTimeLock(builder, group, configuration); err != nil {
+		return false, err
+	}
+	builder.SetWitness(uint(group.InputIndices[0]), types.WitnessTypeLock, omnilockWitnessLock.SerializeAsPlaceholder())
+	return true, nil
 }
 
 func (o *OmnilockScriptHandler) isMatched(script *types.Script) bool {
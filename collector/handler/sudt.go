@@ -14,23 +14,13 @@ type SudtScriptHandler struct {
 }
 
 func NewSudtScriptHandler(network types.Network) *SudtScriptHandler {
-	var txHash types.Hash
-	if network == types.NetworkMain {
-		txHash = types.HexToHash("0xc7813f6a415144643970c2e88e0bb6ca6a8edc5dd7c1022746f628284a9936d5")
-	} else if network == types.NetworkTest {
-		txHash = types.HexToHash("0xe12877ebd2c3c364dc46c5c992bcfaf4fee33fa13eebdf82c591fc9825aab769")
-	} else {
+	info := systemscript.GetInfo(network, systemscript.Sudt)
+	if info == nil {
 		return nil
 	}
 	return &SudtScriptHandler{
-		CellDep: &types.CellDep{
-			OutPoint: &types.OutPoint{
-				TxHash: txHash,
-				Index:  0,
-			},
-			DepType: types.DepTypeCode,
-		},
-		CodeHash: systemscript.GetCodeHash(network, systemscript.Sudt),
+		CellDep:  info.CellDep(),
+		CodeHash: info.CodeHash,
 	}
 }
 
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/collector"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/blake2b"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"reflect"
+)
+
+// XudtInfo provides extension scripts and owner info for the xUDT witness. Extension scripts are required when args
+// only holds their hash, and ExtensionCellDeps are added for them. The data of each extension script is empty if
+// ExtensionData is not set.
+type XudtInfo struct {
+	ExtensionScripts  []*types.Script
+	ExtensionData     [][]byte
+	ExtensionCellDeps []*types.CellDep
+	OwnerScript       *types.Script
+	OwnerSignature    []byte
+}
+
+type XudtScriptHandler struct {
+	CellDep  *types.CellDep
+	CodeHash types.Hash
+}
+
+func NewXudtScriptHandler(network types.Network) *XudtScriptHandler {
+	info := systemscript.GetInfo(network, systemscript.Xudt)
+	if info == nil {
+		return nil
+	}
+	return &XudtScriptHandler{
+		CellDep:  info.CellDep(),
+		CodeHash: info.CodeHash,
+	}
+}
+
+func (r *XudtScriptHandler) isMatched(script *types.Script) bool {
+	if script == nil {
+		return false
+	}
+	return reflect.DeepEqual(script.CodeHash, r.CodeHash)
+}
+
+func (r *XudtScriptHandler) BuildTransaction(builder collector.TransactionBuilder, group *transaction.ScriptGroup, context interface{}) (bool, error) {
+	if group == nil || !r.isMatched(group.Script) {
+		return false, nil
+	}
+	args, err := systemscript.DecodeToXudtArgs(group.Script.Args)
+	if err != nil {
+		return false, err
+	}
+	builder.AddCellDep(r.CellDep)
+
+	var info *XudtInfo
+	switch context.(type) {
+	case XudtInfo, *XudtInfo:
+		var ok bool
+		if info, ok = context.(*XudtInfo); !ok {
+			v, _ := context.(XudtInfo)
+			info = &v
+		}
+	default:
+		return true, nil
+	}
+	witness, err := r.buildWitness(args, info)
+	if err != nil {
+		return false, err
+	}
+	if witness == nil {
+		return true, nil
+	}
+	for _, cellDep := range info.ExtensionCellDeps {
+		builder.AddCellDep(cellDep)
+	}
+	// witness is put in the first input of group, or the first output if there is no input
+	if len(group.InputIndices) > 0 {
+		err = builder.SetWitness(uint(group.InputIndices[0]), types.WitnessTypeInputType, witness.Serialize())
+	} else if len(group.OutputIndices) > 0 {
+		err = builder.SetWitness(uint(group.OutputIndices[0]), types.WitnessTypeOutputType, witness.Serialize())
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// buildWitness returns nil if neither extension scripts nor owner info are used
+func (r *XudtScriptHandler) buildWitness(args *systemscript.XudtArgs, info *XudtInfo) (*systemscript.XudtWitness, error) {
+	witness := &systemscript.XudtWitness{
+		OwnerScript:    info.OwnerScript,
+		OwnerSignature: info.OwnerSignature,
+	}
+	var scripts []*types.Script
+	switch args.ExtensionFlags() {
+	case systemscript.XudtFlagsExtensionScripts:
+		scripts = args.ExtensionScripts
+	case systemscript.XudtFlagsExtensionScriptsHash:
+		if len(info.ExtensionScripts) == 0 {
+			return nil, errors.New("extension scripts are required by xudt args")
+		}
+		hash := blake2b.Blake160(systemscript.SerializeScriptVec(info.ExtensionScripts))
+		if !bytes.Equal(hash, args.ExtensionScriptsHash) {
+			return nil, errors.New("extension scripts mismatch hash in xudt args")
+		}
+		scripts = info.ExtensionScripts
+		witness.RawExtensionData = scripts
+	}
+	if len(scripts) == 0 && witness.OwnerScript == nil && witness.OwnerSignature == nil {
+		return nil, nil
+	}
+	witness.ExtensionData = info.ExtensionData
+	if witness.ExtensionData == nil {
+		witness.ExtensionData = make([][]byte, len(scripts))
+		for i := range witness.ExtensionData {
+			witness.ExtensionData[i] = []byte{}
+		}
+	}
+	if len(witness.ExtensionData) != len(scripts) {
+		return nil, errors.New("extension data should match extension scripts")
+	}
+	return witness, nil
+}
@@ -18,6 +18,26 @@ type LiveCellsGetter interface {
 	GetCells(searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.LiveCells, error)
 }
 
+// LiveCellsGetterCtx is implemented by LiveCellsGetter which can query with a given context
+type LiveCellsGetterCtx interface {
+	GetCellsCtx(ctx context.Context, searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.LiveCells, error)
+}
+
+// CellIteratorErr is implemented by CellIterator whose queries can fail, e.g. when the indexer is unavailable. HasNext
+// returns false when a query fails, and Err returns the error, so that builders can tell it from running out of cells.
+type CellIteratorErr interface {
+	CellIterator
+	Err() error
+}
+
+// CellIteratorCtx is implemented by CellIterator whose queries can be bound to a context, so that builders can
+// cancel or time-limit them.
+type CellIteratorCtx interface {
+	CellIterator
+	Context() context.Context
+	SetContext(ctx context.Context)
+}
+
 type CkbLiveCellGetter struct {
 	Client  rpc.Client
 	Context context.Context
@@ -28,6 +48,10 @@ func (c *CkbLiveCellGetter) GetCells(searchKey *indexer.SearchKey, order indexer
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	return c.GetCellsCtx(ctx, searchKey, order, limit, afterCursor)
+}
+
+func (c *CkbLiveCellGetter) GetCellsCtx(ctx context.Context, searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.LiveCells, error) {
 	return c.Client.GetCells(ctx, searchKey, order, limit, afterCursor)
 }
 
@@ -41,6 +65,10 @@ func (c *LightClientLiveCellGetter) GetCells(searchKey *indexer.SearchKey, order
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	return c.GetCellsCtx(ctx, searchKey, order, limit, afterCursor)
+}
+
+func (c *LightClientLiveCellGetter) GetCellsCtx(ctx context.Context, searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.LiveCells, error) {
 	return c.Client.GetCells(ctx, searchKey, order, limit, afterCursor)
 }
 
@@ -87,6 +115,23 @@ func NewLiveCellIteratorByLightClientFromAddress(client lightclient.Client, addr
 	return newLiveCellIteratorFromAddress(&LightClientLiveCellGetter{Client: client}, addr)
 }
 
+// NewLiveCellIteratorCtx creates an iterator whose queries use ctx, unless it's rebound by SetContext
+func NewLiveCellIteratorCtx(ctx context.Context, client rpc.Client, key *indexer.SearchKey) CellIterator {
+	return newLiveCellIterator(&CkbLiveCellGetter{Client: client, Context: ctx}, key)
+}
+
+func NewLiveCellIteratorFromAddressCtx(ctx context.Context, client rpc.Client, addr string) (CellIterator, error) {
+	return newLiveCellIteratorFromAddress(&CkbLiveCellGetter{Client: client, Context: ctx}, addr)
+}
+
+func NewLiveCellIteratorByLightClientCtx(ctx context.Context, client lightclient.Client, key *indexer.SearchKey) CellIterator {
+	return newLiveCellIterator(&LightClientLiveCellGetter{Client: client, Context: ctx}, key)
+}
+
+func NewLiveCellIteratorByLightClientFromAddressCtx(ctx context.Context, client lightclient.Client, addr string) (CellIterator, error) {
+	return newLiveCellIteratorFromAddress(&LightClientLiveCellGetter{Client: client, Context: ctx}, addr)
+}
+
 type LiveCellIterator struct {
 	LiveCellGetter LiveCellsGetter
 	SearchKey      *indexer.SearchKey
@@ -95,6 +140,23 @@ type LiveCellIterator struct {
 	afterCursor    string
 	cells          []*types.TransactionInput
 	index          int
+	ctx            context.Context
+	err            error
+}
+
+// Context returns the context bound by SetContext, or nil if there isn't
+func (r *LiveCellIterator) Context() context.Context {
+	return r.ctx
+}
+
+// SetContext binds ctx to later queries, if LiveCellGetter implements LiveCellsGetterCtx. Set nil to unbind.
+func (r *LiveCellIterator) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// Err returns the error of the last query, or nil if it succeeded. HasNext queries again after a failure.
+func (r *LiveCellIterator) Err() error {
+	return r.err
 }
 
 func (r *LiveCellIterator) HasNext() bool {
@@ -112,7 +174,16 @@ func (r *LiveCellIterator) update() bool {
 	if r.index >= 0 && r.index < len(r.cells) {
 		return false
 	}
-	liveCells, err := r.LiveCellGetter.GetCells(r.SearchKey, r.SearchOrder, r.Limit, r.afterCursor)
+	var (
+		liveCells *indexer.LiveCells
+		err       error
+	)
+	if getter, ok := r.LiveCellGetter.(LiveCellsGetterCtx); ok && r.ctx != nil {
+		liveCells, err = getter.GetCellsCtx(r.ctx, r.SearchKey, r.SearchOrder, r.Limit, r.afterCursor)
+	} else {
+		liveCells, err = r.LiveCellGetter.GetCells(r.SearchKey, r.SearchOrder, r.Limit, r.afterCursor)
+	}
+	r.err = err
 	if err != nil {
 		return false
 	}
@@ -42,11 +42,15 @@ func (c *LiveCellCollector) HasNext() bool {
 }
 
 func (c *LiveCellCollector) Next() error {
+	return c.NextCtx(context.Background())
+}
+
+func (c *LiveCellCollector) NextCtx(ctx context.Context) error {
 	c.itemIndex++
 	if c.itemIndex >= len(c.result) && c.LastCursor != "" {
 		c.itemIndex = 0
 		var err error
-		c.result, c.LastCursor, err = c.collect()
+		c.result, c.LastCursor, err = c.collect(ctx)
 		if err != nil {
 			return err
 		}
@@ -62,7 +66,11 @@ func (c *LiveCellCollector) CurrentItem() (*indexer.LiveCell, error) {
 }
 
 func (c *LiveCellCollector) Iterator() (CellCollectionIterator, error) {
-	result, lastCursor, err := c.collect()
+	return c.IteratorCtx(context.Background())
+}
+
+func (c *LiveCellCollector) IteratorCtx(ctx context.Context) (CellCollectionIterator, error) {
+	result, lastCursor, err := c.collect(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +80,7 @@ func (c *LiveCellCollector) Iterator() (CellCollectionIterator, error) {
 	return c, nil
 }
 
-func (c *LiveCellCollector) collect() ([]*indexer.LiveCell, string, error) {
+func (c *LiveCellCollector) collect(ctx context.Context) ([]*indexer.LiveCell, string, error) {
 	if c.SearchKey == nil {
 		return nil, "", errors.New("missing SearchKey error")
 	}
@@ -80,7 +88,7 @@ func (c *LiveCellCollector) collect() ([]*indexer.LiveCell, string, error) {
 		return nil, "", errors.New("missing SearchOrder error")
 	}
 	var result []*indexer.LiveCell
-	liveCells, err := c.Client.GetCells(context.Background(), c.SearchKey, c.SearchOrder, c.Limit, c.LastCursor)
+	liveCells, err := c.Client.GetCells(ctx, c.SearchKey, c.SearchOrder, c.Limit, c.LastCursor)
 	if err != nil {
 		return nil, "", err
 	}
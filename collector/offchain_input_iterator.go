@@ -2,6 +2,7 @@ package collector
 
 import (
 	"container/list"
+	"context"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/indexer"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/rpc"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
@@ -41,6 +42,20 @@ func (r *OffChainInputIterator) HasNext() bool {
 	return r.Iterator.HasNext()
 }
 
+func (r *OffChainInputIterator) Context() context.Context {
+	return r.Iterator.Context()
+}
+
+// SetContext binds ctx to later queries of Iterator
+func (r *OffChainInputIterator) SetContext(ctx context.Context) {
+	r.Iterator.SetContext(ctx)
+}
+
+// Err returns the error of the last query of Iterator
+func (r *OffChainInputIterator) Err() error {
+	return r.Iterator.Err()
+}
+
 func (r *OffChainInputIterator) Next() *types.TransactionInput {
 	r.update()
 	if r.current != nil {
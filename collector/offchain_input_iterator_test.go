@@ -0,0 +1,39 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/indexer"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type ctxLiveCellsGetter struct{}
+
+func (g *ctxLiveCellsGetter) GetCells(searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.LiveCells, error) {
+	return &indexer.LiveCells{}, nil
+}
+
+func (g *ctxLiveCellsGetter) GetCellsCtx(ctx context.Context, searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.LiveCells, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g.GetCells(searchKey, order, limit, afterCursor)
+}
+
+func TestOffChainInputIteratorContext(t *testing.T) {
+	iterator := NewOffChainInputIterator(newLiveCellIterator(&ctxLiveCellsGetter{}, &indexer.SearchKey{}), nil, false)
+	var _ CellIteratorCtx = iterator
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	iterator.SetContext(ctx)
+	assert.Equal(t, ctx, iterator.Context())
+	assert.Equal(t, ctx, iterator.Iterator.Context())
+	assert.False(t, iterator.HasNext())
+	assert.True(t, errors.Is(iterator.Err(), context.Canceled))
+
+	iterator.SetContext(nil)
+	assert.False(t, iterator.HasNext())
+	assert.NoError(t, iterator.Err())
+}
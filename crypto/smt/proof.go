@@ -0,0 +1,154 @@
+package smt
+
+import (
+	"fmt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+)
+
+type Leaf struct {
+	Key   types.Hash
+	Value types.Hash
+}
+
+// CompiledMerkleProof is the merkle proof in the format accepted by CKB scripts.
+type CompiledMerkleProof []byte
+
+type stackItem struct {
+	height int
+	key    types.Hash
+	value  mergeValue
+}
+
+// ComputeRoot computes the root from the proof and the leaves it proves.
+func (p CompiledMerkleProof) ComputeRoot(leaves []Leaf) (types.Hash, error) {
+	leaves = append([]Leaf{}, leaves...)
+	keys := make([]types.Hash, len(leaves))
+	for i, leaf := range leaves {
+		keys[i] = leaf.Key
+	}
+	values := make(map[types.Hash]types.Hash)
+	for _, leaf := range leaves {
+		values[leaf.Key] = leaf.Value
+	}
+	sortKeys(keys)
+
+	var stack []stackItem
+	leafIndex := 0
+	readBytes := func(index *int, n int) ([]byte, error) {
+		if *index+n > len(p) {
+			return nil, fmt.Errorf("corrupted proof")
+		}
+		b := p[*index : *index+n]
+		*index += n
+		return b, nil
+	}
+	for i := 0; i < len(p); {
+		code := p[i]
+		i++
+		switch code {
+		case opLeaf:
+			if leafIndex >= len(keys) {
+				return types.Hash{}, fmt.Errorf("not enough leaves")
+			}
+			key := keys[leafIndex]
+			stack = append(stack, stackItem{height: 0, key: key, value: mergeValue{value: values[key]}})
+			leafIndex++
+		case opProof, opProofMergeWithZero:
+			if len(stack) == 0 {
+				return types.Hash{}, fmt.Errorf("corrupted proof")
+			}
+			var sibling mergeValue
+			if code == opProof {
+				b, err := readBytes(&i, 32)
+				if err != nil {
+					return types.Hash{}, err
+				}
+				sibling.value = types.BytesToHash(b)
+			} else {
+				b, err := readBytes(&i, 65)
+				if err != nil {
+					return types.Hash{}, err
+				}
+				baseNode := types.BytesToHash(b[1:33])
+				sibling.zeroCount = b[0]
+				sibling.baseNode = &baseNode
+				sibling.zeroBits = types.BytesToHash(b[33:65])
+			}
+			item := stack[len(stack)-1]
+			if item.height > 255 {
+				return types.Hash{}, fmt.Errorf("corrupted proof")
+			}
+			parentKey := parentPath(item.key, item.height)
+			var parent mergeValue
+			if getBit(item.key, item.height) {
+				parent = merge(item.height, parentKey, sibling, item.value)
+			} else {
+				parent = merge(item.height, parentKey, item.value, sibling)
+			}
+			stack[len(stack)-1] = stackItem{height: item.height + 1, key: parentKey, value: parent}
+		case opHash:
+			if len(stack) < 2 {
+				return types.Hash{}, fmt.Errorf("corrupted proof")
+			}
+			a := stack[len(stack)-2]
+			b := stack[len(stack)-1]
+			stack = stack[:len(stack)-2]
+			if a.height != b.height || a.height > 255 {
+				return types.Hash{}, fmt.Errorf("corrupted proof")
+			}
+			parentKey := parentPath(a.key, a.height)
+			if parentKey != parentPath(b.key, b.height) {
+				return types.Hash{}, fmt.Errorf("corrupted proof")
+			}
+			var parent mergeValue
+			if getBit(a.key, a.height) {
+				parent = merge(a.height, parentKey, b.value, a.value)
+			} else {
+				parent = merge(a.height, parentKey, a.value, b.value)
+			}
+			stack = append(stack, stackItem{height: a.height + 1, key: parentKey, value: parent})
+		case opZeros:
+			if len(stack) == 0 {
+				return types.Hash{}, fmt.Errorf("corrupted proof")
+			}
+			b, err := readBytes(&i, 1)
+			if err != nil {
+				return types.Hash{}, err
+			}
+			zeroCount := int(b[0])
+			if zeroCount == 0 {
+				zeroCount = 256
+			}
+			item := stack[len(stack)-1]
+			if item.height+zeroCount > 256 {
+				return types.Hash{}, fmt.Errorf("corrupted proof")
+			}
+			value := item.value
+			for height := item.height; height < item.height+zeroCount; height++ {
+				parentKey := parentPath(item.key, height)
+				if getBit(item.key, height) {
+					value = merge(height, parentKey, mergeValue{}, value)
+				} else {
+					value = merge(height, parentKey, value, mergeValue{})
+				}
+			}
+			height := item.height + zeroCount
+			stack[len(stack)-1] = stackItem{height: height, key: parentPath(item.key, height-1), value: value}
+		default:
+			return types.Hash{}, fmt.Errorf("invalid op code %#x", code)
+		}
+	}
+	if len(stack) != 1 || stack[0].height != 256 || leafIndex != len(keys) {
+		return types.Hash{}, fmt.Errorf("corrupted proof")
+	}
+	return stack[0].value.hash(), nil
+}
+
+// Verify checks whether the proof of leaves matches root.
+func (p CompiledMerkleProof) Verify(root types.Hash, leaves []Leaf) (bool, error) {
+	computed, err := p.ComputeRoot(leaves)
+	if err != nil {
+		return false, err
+	}
+	return computed == root, nil
+}
@@ -0,0 +1,275 @@
+// Package smt implements the sparse merkle tree used by CKB scripts, such as the RC rules of Omnilock and xUDT.
+//
+// Hashing and proof encoding are compatible with https://github.com/nervosnetwork/sparse-merkle-tree (v0.6),
+// the tree has 256 levels and uses blake2b with CKB personalization.
+package smt
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/blake2b"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"sort"
+)
+
+const (
+	mergeNormal byte = 1
+	mergeZeros  byte = 2
+)
+
+// Compiled proof op codes
+const (
+	opLeaf               byte = 0x4C
+	opProof              byte = 0x50
+	opProofMergeWithZero byte = 0x51
+	opHash               byte = 0x48
+	opZeros              byte = 0x4F
+)
+
+type SparseMerkleTree struct {
+	leaves map[types.Hash]types.Hash
+}
+
+func NewSparseMerkleTree() *SparseMerkleTree {
+	return &SparseMerkleTree{leaves: make(map[types.Hash]types.Hash)}
+}
+
+// Update sets the value of key. Setting a zero value removes the key from tree.
+func (t *SparseMerkleTree) Update(key types.Hash, value types.Hash) {
+	if value == (types.Hash{}) {
+		delete(t.leaves, key)
+	} else {
+		t.leaves[key] = value
+	}
+}
+
+// Get returns the value of key, or zero value if key doesn't exist.
+func (t *SparseMerkleTree) Get(key types.Hash) types.Hash {
+	return t.leaves[key]
+}
+
+func (t *SparseMerkleTree) Root() types.Hash {
+	return t.branch(255, t.sortedKeys()).hash()
+}
+
+// MerkleProof generates the compiled merkle proof of keys, which can be verified by CKB scripts.
+func (t *SparseMerkleTree) MerkleProof(keys ...types.Hash) (CompiledMerkleProof, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("keys is empty")
+	}
+	keys = append([]types.Hash{}, keys...)
+	sortKeys(keys)
+	for i := 1; i < len(keys); i++ {
+		if keys[i] == keys[i-1] {
+			return nil, fmt.Errorf("duplicated key %s", keys[i])
+		}
+	}
+	all := t.sortedKeys()
+
+	var proof []byte
+	var stackForkHeight []int
+	for i, key := range keys {
+		forkHeight := 255
+		last := i+1 == len(keys)
+		if !last {
+			forkHeight = forkHeightOf(key, keys[i+1])
+		}
+		proof = append(proof, opLeaf)
+		zeroCount := 0
+		for height := 0; height <= forkHeight; height++ {
+			if height == forkHeight && !last {
+				break
+			}
+			var op byte
+			var data []byte
+			if n := len(stackForkHeight); n > 0 && stackForkHeight[n-1] == height {
+				stackForkHeight = stackForkHeight[:n-1]
+				op = opHash
+			} else {
+				sibling := t.child(height, siblingKeys(all, key, height))
+				if sibling.isZero() {
+					zeroCount++
+					continue
+				}
+				if sibling.baseNode == nil {
+					op = opProof
+					data = sibling.value[:]
+				} else {
+					op = opProofMergeWithZero
+					data = append([]byte{sibling.zeroCount}, sibling.baseNode[:]...)
+					data = append(data, sibling.zeroBits[:]...)
+				}
+			}
+			if zeroCount > 0 {
+				proof = append(proof, opZeros, byte(zeroCount))
+				zeroCount = 0
+			}
+			proof = append(proof, op)
+			proof = append(proof, data...)
+		}
+		if zeroCount > 0 {
+			// 256 is encoded as 0
+			proof = append(proof, opZeros, byte(zeroCount))
+		}
+		stackForkHeight = append(stackForkHeight, forkHeight)
+	}
+	return proof, nil
+}
+
+// branch returns the merged value at height, whose children are made of keys.
+func (t *SparseMerkleTree) branch(height int, keys []types.Hash) mergeValue {
+	if len(keys) == 0 {
+		return mergeValue{}
+	}
+	var left, right []types.Hash
+	for _, key := range keys {
+		if getBit(key, height) {
+			right = append(right, key)
+		} else {
+			left = append(left, key)
+		}
+	}
+	return merge(height, parentPath(keys[0], height), t.child(height, left), t.child(height, right))
+}
+
+// child returns the value of a child node of the branch at height.
+func (t *SparseMerkleTree) child(height int, keys []types.Hash) mergeValue {
+	if len(keys) == 0 {
+		return mergeValue{}
+	}
+	if height == 0 {
+		return mergeValue{value: t.leaves[keys[0]]}
+	}
+	return t.branch(height-1, keys)
+}
+
+func (t *SparseMerkleTree) sortedKeys() []types.Hash {
+	keys := make([]types.Hash, 0, len(t.leaves))
+	for key := range t.leaves {
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+	return keys
+}
+
+// siblingKeys filters keys in the sibling subtree of key at height.
+func siblingKeys(keys []types.Hash, key types.Hash, height int) []types.Hash {
+	var out []types.Hash
+	parent := parentPath(key, height)
+	for _, k := range keys {
+		if getBit(k, height) != getBit(key, height) && parentPath(k, height) == parent {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// mergeValue is either a plain value, or a node merged with zero siblings when baseNode is set.
+type mergeValue struct {
+	value     types.Hash
+	baseNode  *types.Hash
+	zeroBits  types.Hash
+	zeroCount byte
+}
+
+func (v mergeValue) isZero() bool {
+	return v.baseNode == nil && v.value == types.Hash{}
+}
+
+func (v mergeValue) hash() types.Hash {
+	if v.baseNode == nil {
+		return v.value
+	}
+	data := []byte{mergeZeros}
+	data = append(data, v.baseNode[:]...)
+	data = append(data, v.zeroBits[:]...)
+	data = append(data, v.zeroCount)
+	return types.BytesToHash(blake2b.Blake256(data))
+}
+
+func merge(height int, nodeKey types.Hash, lhs, rhs mergeValue) mergeValue {
+	if lhs.isZero() && rhs.isZero() {
+		return mergeValue{}
+	}
+	if lhs.isZero() {
+		return mergeWithZero(height, nodeKey, rhs, true)
+	}
+	if rhs.isZero() {
+		return mergeWithZero(height, nodeKey, lhs, false)
+	}
+	lhsHash := lhs.hash()
+	rhsHash := rhs.hash()
+	data := []byte{mergeNormal, byte(height)}
+	data = append(data, nodeKey[:]...)
+	data = append(data, lhsHash[:]...)
+	data = append(data, rhsHash[:]...)
+	return mergeValue{value: types.BytesToHash(blake2b.Blake256(data))}
+}
+
+func mergeWithZero(height int, nodeKey types.Hash, v mergeValue, setBit bool) mergeValue {
+	if v.baseNode == nil {
+		data := []byte{byte(height)}
+		data = append(data, nodeKey[:]...)
+		data = append(data, v.value[:]...)
+		baseNode := types.BytesToHash(blake2b.Blake256(data))
+		out := mergeValue{baseNode: &baseNode, zeroCount: 1}
+		if setBit {
+			setBitOf(&out.zeroBits, height)
+		}
+		return out
+	}
+	out := mergeValue{baseNode: v.baseNode, zeroBits: v.zeroBits, zeroCount: v.zeroCount + 1}
+	if setBit {
+		setBitOf(&out.zeroBits, height)
+	}
+	return out
+}
+
+func getBit(key types.Hash, height int) bool {
+	return (key[height/8]>>(height%8))&1 != 0
+}
+
+func setBitOf(key *types.Hash, height int) {
+	key[height/8] |= 1 << (height % 8)
+}
+
+// parentPath clears the bits of key at and below height
+func parentPath(key types.Hash, height int) types.Hash {
+	if height == 255 {
+		return types.Hash{}
+	}
+	start := height + 1
+	var out types.Hash
+	startByte := start / 8
+	copy(out[startByte:], key[startByte:])
+	if remain := start % 8; remain > 0 {
+		out[startByte] &= 0xFF << remain
+	}
+	return out
+}
+
+// forkHeightOf returns the highest height where bits of a and b differ.
+func forkHeightOf(a, b types.Hash) int {
+	for height := 255; height >= 0; height-- {
+		if getBit(a, height) != getBit(b, height) {
+			return height
+		}
+	}
+	return 0
+}
+
+// sortKeys sorts keys from the left-most leaf to the right-most leaf, in which bits are compared from height 255 to 0.
+func sortKeys(keys []types.Hash) {
+	sort.Slice(keys, func(i, j int) bool {
+		return compareKeys(keys[i], keys[j]) < 0
+	})
+}
+
+func compareKeys(a, b types.Hash) int {
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i] != b[i] {
+			return bytes.Compare(a[i:i+1], b[i:i+1])
+		}
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+package smt
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var existing = types.Hash{1}
+
+func key(hex string) types.Hash {
+	var h types.Hash
+	copy(h[:], hexutil.MustDecode(hex))
+	return h
+}
+
+func newTestTree() (*SparseMerkleTree, []types.Hash) {
+	keys := []types.Hash{
+		key("0x00" + "1111111111111111111111111111111111111111"),
+		key("0x6f01"),
+		key("0xde02"),
+		key("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+	}
+	tree := NewSparseMerkleTree()
+	for _, k := range keys {
+		tree.Update(k, existing)
+	}
+	return tree, keys
+}
+
+// The roots and compiled proofs below are computed by this implementation, so they only guard against regressions.
+// Compatibility with nervosnetwork/sparse-merkle-tree v0.6, which administrator mode of Omnilock depends on, needs a
+// root and proof taken from it or from an RC-rule cell on chain.
+func TestRoot(t *testing.T) {
+	tree := NewSparseMerkleTree()
+	assert.Equal(t, types.Hash{}, tree.Root())
+
+	tree, keys := newTestTree()
+	assert.Equal(t, "0xe7a739a981f978c0e9c0969e5594f07c5e0f94f0bc45050b8c1db88523b1b834", tree.Root().String())
+
+	for _, k := range keys[1:] {
+		tree.Update(k, types.Hash{})
+	}
+	assert.Equal(t, "0x82c695ef192cb50f8acdc6acbedb13a2a91a8f4fb9714d809035f482e71756c6", tree.Root().String())
+}
+
+func TestMerkleProof(t *testing.T) {
+	tree, keys := newTestTree()
+	root := tree.Root()
+
+	proof, err := tree.MerkleProof(keys[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "0x4c4fa4519aab740719aad6cebf207a73893b3c0304ab862a227fc62a68e53dd9743b0bef2a00000000000000000000000000000000000000000000000000000000000000004f5a51ff6eec8add5a8001967184bacbf2876f28f5d418943b5bb55903833400ab26f5adffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f", hexutil.Encode(proof))
+	ok, err := proof.Verify(root, []Leaf{{keys[0], existing}})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	// proof of non-membership doesn't match
+	ok, err = proof.Verify(root, []Leaf{{keys[0], types.Hash{}}})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	proof, err = tree.MerkleProof(keys[3], keys[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "0x4c4fa4519aab740719aad6cebf207a73893b3c0304ab862a227fc62a68e53dd9743b0bef2a00000000000000000000000000000000000000000000000000000000000000004f5a4c4fff48", hexutil.Encode(proof))
+	ok, err = proof.Verify(root, []Leaf{{keys[3], existing}, {keys[0], existing}})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	proof, err = tree.MerkleProof(keys...)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x4c4f094c4f09484f9a4c4fa4484f5a4c4fff48", hexutil.Encode(proof))
+	var leaves []Leaf
+	for _, k := range keys {
+		leaves = append(leaves, Leaf{k, existing})
+	}
+	ok, err = proof.Verify(root, leaves)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// proof of a key not in tree
+	absent := key("0x0102")
+	proof, err = tree.MerkleProof(absent)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x4c4f07510703e10b9c4d84bfd21c0ea3283ee58d190e7b5fc2e38916b8913d4694e5aad9405e000000000000000000000000000000000000000000000000000000000000004f0151091e2ec991176f4dd5c27aadfe478c9f5c9e8b7c3ca24c568df3caa9339e3d634a6f010000000000000000000000000000000000000000000000000000000000004f9a51a4d0206f696ecb897384661ca6e9ad1fa203982b35c8683420fef8951bf1c4910800111111111111111111111111111111111111110100000000000000000000004f5a51ff6eec8add5a8001967184bacbf2876f28f5d418943b5bb55903833400ab26f5adffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f", hexutil.Encode(proof))
+	ok, err = proof.Verify(root, []Leaf{{absent, types.Hash{}}})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	single := NewSparseMerkleTree()
+	single.Update(keys[0], existing)
+	proof, err = single.MerkleProof(keys[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "0x4c4f00", hexutil.Encode(proof))
+}
+
+func TestCorruptedProof(t *testing.T) {
+	_, err := CompiledMerkleProof{opLeaf, opProof, 0x01}.ComputeRoot([]Leaf{{}})
+	assert.Error(t, err)
+	_, err = CompiledMerkleProof{opLeaf}.ComputeRoot([]Leaf{{}})
+	assert.Error(t, err)
+}
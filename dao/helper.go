@@ -24,38 +24,53 @@ type DaoDepositCellInfo struct {
 
 // GetDaoDepositCellInfo Get information for DAO cell deposited as outpoint and withdrawn in block of withdrawBlockHash
 func (c *DaoHelper) GetDaoDepositCellInfo(outpoint *types.OutPoint, withdrawBlockHash types.Hash) (DaoDepositCellInfo, error) {
-	blockHeader, err := c.Client.GetHeader(context.Background(), withdrawBlockHash)
+	return c.GetDaoDepositCellInfoCtx(context.Background(), outpoint, withdrawBlockHash)
+}
+
+// GetDaoDepositCellInfoCtx is GetDaoDepositCellInfo with ctx for RPC calls
+func (c *DaoHelper) GetDaoDepositCellInfoCtx(ctx context.Context, outpoint *types.OutPoint, withdrawBlockHash types.Hash) (DaoDepositCellInfo, error) {
+	blockHeader, err := c.Client.GetHeader(ctx, withdrawBlockHash)
 	if err != nil {
 		return DaoDepositCellInfo{}, err
 	}
-	return c.getDaoDepositCellInfo(outpoint, blockHeader)
+	return c.getDaoDepositCellInfo(ctx, outpoint, blockHeader)
 }
 
 // GetDaoDepositCellInfoWithWithdrawOutpoint Get information for DAO cell deposited as outpoint and withdrawn in block where the withdrawCellOutPoint is
 func (c *DaoHelper) GetDaoDepositCellInfoWithWithdrawOutpoint(outpoint *types.OutPoint, withdrawCellOutPoint *types.OutPoint) (DaoDepositCellInfo, error) {
-	withdrawTransaction, err := c.Client.GetTransaction(context.Background(), withdrawCellOutPoint.TxHash)
+	return c.GetDaoDepositCellInfoWithWithdrawOutpointCtx(context.Background(), outpoint, withdrawCellOutPoint)
+}
+
+// GetDaoDepositCellInfoWithWithdrawOutpointCtx is GetDaoDepositCellInfoWithWithdrawOutpoint with ctx for RPC calls
+func (c *DaoHelper) GetDaoDepositCellInfoWithWithdrawOutpointCtx(ctx context.Context, outpoint *types.OutPoint, withdrawCellOutPoint *types.OutPoint) (DaoDepositCellInfo, error) {
+	withdrawTransaction, err := c.Client.GetTransaction(ctx, withdrawCellOutPoint.TxHash)
 	if err != nil {
 		return DaoDepositCellInfo{}, err
 	}
-	return c.GetDaoDepositCellInfo(outpoint, *withdrawTransaction.TxStatus.BlockHash)
+	return c.GetDaoDepositCellInfoCtx(ctx, outpoint, *withdrawTransaction.TxStatus.BlockHash)
 }
 
 // GetDaoDepositCellInfoByNow DAO information for DAO cell deposited as outpoint and withdrawn in tip block
 func (c *DaoHelper) GetDaoDepositCellInfoByNow(outpoint *types.OutPoint) (DaoDepositCellInfo, error) {
-	tipBlockHeader, err := c.Client.GetTipHeader(context.Background())
+	return c.GetDaoDepositCellInfoByNowCtx(context.Background(), outpoint)
+}
+
+// GetDaoDepositCellInfoByNowCtx is GetDaoDepositCellInfoByNow with ctx for RPC calls
+func (c *DaoHelper) GetDaoDepositCellInfoByNowCtx(ctx context.Context, outpoint *types.OutPoint) (DaoDepositCellInfo, error) {
+	tipBlockHeader, err := c.Client.GetTipHeader(ctx)
 	if err != nil {
 		return DaoDepositCellInfo{}, err
 	}
-	return c.getDaoDepositCellInfo(outpoint, tipBlockHeader)
+	return c.getDaoDepositCellInfo(ctx, outpoint, tipBlockHeader)
 }
 
 // getDaoDepositCellInfo Get information for DAO cell deposited as outpoint and withdrawn in withdrawBlock
-func (c *DaoHelper) getDaoDepositCellInfo(outpoint *types.OutPoint, withdrawBlockHeader *types.Header) (DaoDepositCellInfo, error) {
-	depositTransactionWithStatus, err := c.Client.GetTransaction(context.Background(), outpoint.TxHash)
+func (c *DaoHelper) getDaoDepositCellInfo(ctx context.Context, outpoint *types.OutPoint, withdrawBlockHeader *types.Header) (DaoDepositCellInfo, error) {
+	depositTransactionWithStatus, err := c.Client.GetTransaction(ctx, outpoint.TxHash)
 	if err != nil {
 		return DaoDepositCellInfo{}, err
 	}
-	depositBlockHeader, err := c.Client.GetHeader(context.Background(), *depositTransactionWithStatus.TxStatus.BlockHash)
+	depositBlockHeader, err := c.Client.GetHeader(ctx, *depositTransactionWithStatus.TxStatus.BlockHash)
 	if err != nil {
 		return DaoDepositCellInfo{}, err
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/rpc/rpcerror"
 )
 
 const SearchLimit uint64 = 1000
@@ -52,13 +53,18 @@ func (cli *client) Close() {
 	cli.c.Close()
 }
 
+// callContext calls method and maps JSON-RPC errors into typed errors of package rpcerror
+func (cli *client) callContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return rpcerror.FromError(cli.c.CallContext(ctx, result, method, args...))
+}
+
 func (cli *client) GetCells(ctx context.Context, searchKey *SearchKey, order SearchOrder, limit uint32, afterCursor string) (*LiveCells, error) {
 	var result LiveCells
 	var err error
 	if afterCursor == "" {
-		err = cli.c.CallContext(ctx, &result, "get_cells", searchKey, order, hexutil.Uint(limit))
+		err = cli.callContext(ctx, &result, "get_cells", searchKey, order, hexutil.Uint(limit))
 	} else {
-		err = cli.c.CallContext(ctx, &result, "get_cells", searchKey, order, hexutil.Uint(limit), afterCursor)
+		err = cli.callContext(ctx, &result, "get_cells", searchKey, order, hexutil.Uint(limit), afterCursor)
 	}
 	if err != nil {
 		return nil, err
@@ -70,9 +76,9 @@ func (cli *client) GetTransactions(ctx context.Context, searchKey *SearchKey, or
 	var result TxsWithCell
 	var err error
 	if afterCursor == "" {
-		err = cli.c.CallContext(ctx, &result, "get_transactions", searchKey, order, hexutil.Uint(limit))
+		err = cli.callContext(ctx, &result, "get_transactions", searchKey, order, hexutil.Uint(limit))
 	} else {
-		err = cli.c.CallContext(ctx, &result, "get_transactions", searchKey, order, hexutil.Uint(limit), afterCursor)
+		err = cli.callContext(ctx, &result, "get_transactions", searchKey, order, hexutil.Uint(limit), afterCursor)
 	}
 	if err != nil {
 		return nil, err
@@ -91,9 +97,9 @@ func (cli *client) GetTransactionsGrouped(ctx context.Context, searchKey *Search
 	var result TxsWithCells
 	var err error
 	if afterCursor == "" {
-		err = cli.c.CallContext(ctx, &result, "get_transactions", payload, order, hexutil.Uint(limit))
+		err = cli.callContext(ctx, &result, "get_transactions", payload, order, hexutil.Uint(limit))
 	} else {
-		err = cli.c.CallContext(ctx, &result, "get_transactions", payload, order, hexutil.Uint(limit), afterCursor)
+		err = cli.callContext(ctx, &result, "get_transactions", payload, order, hexutil.Uint(limit), afterCursor)
 	}
 	if err != nil {
 		return nil, err
@@ -103,7 +109,7 @@ func (cli *client) GetTransactionsGrouped(ctx context.Context, searchKey *Search
 
 func (cli *client) GetTip(ctx context.Context) (*TipHeader, error) {
 	var result TipHeader
-	err := cli.c.CallContext(ctx, &result, "get_tip")
+	err := cli.callContext(ctx, &result, "get_tip")
 	if err != nil {
 		return nil, err
 	}
@@ -112,7 +118,7 @@ func (cli *client) GetTip(ctx context.Context) (*TipHeader, error) {
 
 func (cli *client) GetCellsCapacity(ctx context.Context, searchKey *SearchKey) (*Capacity, error) {
 	var result Capacity
-	err := cli.c.CallContext(ctx, &result, "get_cells_capacity", searchKey)
+	err := cli.callContext(ctx, &result, "get_cells_capacity", searchKey)
 	if err != nil {
 		return nil, err
 	}
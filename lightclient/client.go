@@ -6,6 +6,7 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/indexer"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/mocking"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/rpc/rpcerror"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
 )
 
@@ -39,13 +40,13 @@ func (cli *client) GetRawClient() types.GenericRPCClient {
 }
 
 func (cli *client) SetScripts(ctx context.Context, scriptDetails []*ScriptDetail) error {
-	err := cli.GetRawClient().CallContext(ctx, nil, "set_scripts", scriptDetails)
+	err := cli.callContext(ctx, nil, "set_scripts", scriptDetails)
 	return err
 }
 
 func (cli *client) GetScripts(ctx context.Context) ([]*ScriptDetail, error) {
 	var result []*ScriptDetail
-	err := cli.GetRawClient().CallContext(ctx, &result, "get_scripts")
+	err := cli.callContext(ctx, &result, "get_scripts")
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +55,7 @@ func (cli *client) GetScripts(ctx context.Context) ([]*ScriptDetail, error) {
 
 func (cli *client) SendTransaction(ctx context.Context, tx *types.Transaction) (*types.Hash, error) {
 	var result types.Hash
-	err := cli.GetRawClient().CallContext(ctx, &result, "send_transaction", *tx)
+	err := cli.callContext(ctx, &result, "send_transaction", *tx)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +64,7 @@ func (cli *client) SendTransaction(ctx context.Context, tx *types.Transaction) (
 
 func (cli *client) GetTipHeader(ctx context.Context) (*types.Header, error) {
 	var result types.Header
-	err := cli.GetRawClient().CallContext(ctx, &result, "get_tip_header")
+	err := cli.callContext(ctx, &result, "get_tip_header")
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +73,7 @@ func (cli *client) GetTipHeader(ctx context.Context) (*types.Header, error) {
 
 func (cli *client) GetGenesisBlock(ctx context.Context) (*types.Block, error) {
 	var result types.Block
-	err := cli.GetRawClient().CallContext(ctx, &result, "get_genesis_block")
+	err := cli.callContext(ctx, &result, "get_genesis_block")
 	if err != nil {
 		return nil, err
 	}
@@ -81,7 +82,7 @@ func (cli *client) GetGenesisBlock(ctx context.Context) (*types.Block, error) {
 
 func (cli *client) GetHeader(ctx context.Context, hash types.Hash) (*types.Header, error) {
 	var result types.Header
-	err := cli.GetRawClient().CallContext(ctx, &result, "get_header", hash)
+	err := cli.callContext(ctx, &result, "get_header", hash)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +91,7 @@ func (cli *client) GetHeader(ctx context.Context, hash types.Hash) (*types.Heade
 
 func (cli *client) GetTransaction(ctx context.Context, hash types.Hash) (*TransactionStatus, error) {
 	var result TransactionStatus
-	err := cli.GetRawClient().CallContext(ctx, &result, "get_transaction", hash)
+	err := cli.callContext(ctx, &result, "get_transaction", hash)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +100,7 @@ func (cli *client) GetTransaction(ctx context.Context, hash types.Hash) (*Transa
 
 func (cli *client) FetchHeader(ctx context.Context, hash types.Hash) (*FetchedHeader, error) {
 	var result FetchedHeader
-	err := cli.GetRawClient().CallContext(ctx, &result, "fetch_header", hash)
+	err := cli.callContext(ctx, &result, "fetch_header", hash)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +109,7 @@ func (cli *client) FetchHeader(ctx context.Context, hash types.Hash) (*FetchedHe
 
 func (cli *client) FetchTransaction(ctx context.Context, hash types.Hash) (*FetchedTransaction, error) {
 	var result FetchedTransaction
-	err := cli.GetRawClient().CallContext(ctx, &result, "fetch_transaction", hash)
+	err := cli.callContext(ctx, &result, "fetch_transaction", hash)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +119,7 @@ func (cli *client) FetchTransaction(ctx context.Context, hash types.Hash) (*Fetc
 func (cli *client) GetPeers(ctx context.Context) ([]*types.RemoteNode, error) {
 	var result []*types.RemoteNode
 
-	err := cli.GetRawClient().CallContext(ctx, &result, "get_peers")
+	err := cli.callContext(ctx, &result, "get_peers")
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +130,7 @@ func (cli *client) GetPeers(ctx context.Context) ([]*types.RemoteNode, error) {
 func (cli *client) LocalNodeInfo(ctx context.Context) (*types.LocalNode, error) {
 	var result types.LocalNode
 
-	err := cli.GetRawClient().CallContext(ctx, &result, "local_node_info")
+	err := cli.callContext(ctx, &result, "local_node_info")
 	if err != nil {
 		return nil, err
 	}
@@ -143,9 +144,9 @@ func (cli *client) GetCells(ctx context.Context, searchKey *indexer.SearchKey, o
 		err    error
 	)
 	if afterCursor == "" {
-		err = cli.GetRawClient().CallContext(ctx, &result, "get_cells", searchKey, order, hexutil.Uint64(limit))
+		err = cli.callContext(ctx, &result, "get_cells", searchKey, order, hexutil.Uint64(limit))
 	} else {
-		err = cli.GetRawClient().CallContext(ctx, &result, "get_cells", searchKey, order, hexutil.Uint64(limit), afterCursor)
+		err = cli.callContext(ctx, &result, "get_cells", searchKey, order, hexutil.Uint64(limit), afterCursor)
 	}
 	if err != nil {
 		return nil, err
@@ -160,9 +161,9 @@ func (cli *client) GetTransactions(ctx context.Context, searchKey *indexer.Searc
 		err    error
 	)
 	if afterCursor == "" {
-		err = cli.GetRawClient().CallContext(ctx, &result, "get_transactions", searchKey, order, hexutil.Uint64(limit))
+		err = cli.callContext(ctx, &result, "get_transactions", searchKey, order, hexutil.Uint64(limit))
 	} else {
-		err = cli.GetRawClient().CallContext(ctx, &result, "get_transactions", searchKey, order, hexutil.Uint64(limit), afterCursor)
+		err = cli.callContext(ctx, &result, "get_transactions", searchKey, order, hexutil.Uint64(limit), afterCursor)
 	}
 	if err != nil {
 		return nil, err
@@ -181,9 +182,9 @@ func (cli *client) GetTransactionsGrouped(ctx context.Context, searchKey *indexe
 	var result TxsWithCells
 	var err error
 	if afterCursor == "" {
-		err = cli.GetRawClient().CallContext(ctx, &result, "get_transactions", payload, order, hexutil.Uint64(limit))
+		err = cli.callContext(ctx, &result, "get_transactions", payload, order, hexutil.Uint64(limit))
 	} else {
-		err = cli.GetRawClient().CallContext(ctx, &result, "get_transactions", payload, order, hexutil.Uint64(limit), afterCursor)
+		err = cli.callContext(ctx, &result, "get_transactions", payload, order, hexutil.Uint64(limit), afterCursor)
 	}
 	if err != nil {
 		return nil, err
@@ -193,7 +194,7 @@ func (cli *client) GetTransactionsGrouped(ctx context.Context, searchKey *indexe
 
 func (cli *client) GetCellsCapacity(ctx context.Context, searchKey *indexer.SearchKey) (*indexer.Capacity, error) {
 	var result indexer.Capacity
-	err := cli.GetRawClient().CallContext(ctx, &result, "get_cells_capacity", searchKey)
+	err := cli.callContext(ctx, &result, "get_cells_capacity", searchKey)
 	if err != nil {
 		return nil, err
 	}
@@ -201,13 +202,18 @@ func (cli *client) GetCellsCapacity(ctx context.Context, searchKey *indexer.Sear
 }
 
 func (cli *client) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
-	err := cli.GetRawClient().CallContext(ctx, result, method, args...)
+	err := cli.callContext(ctx, result, method, args...)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// callContext calls method and maps JSON-RPC errors into typed errors of package rpcerror
+func (cli *client) callContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return rpcerror.FromError(cli.GetRawClient().CallContext(ctx, result, method, args...))
+}
+
 func (cli *client) Close() {
 	cli.GetRawClient().Close()
 }
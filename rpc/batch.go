@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+)
+
+// BatchRequest queues calls to be sent in one round trip by Client.BatchCall. Each queuing method returns an item,
+// whose Result and Error are set after the batch is sent. Error of an item is NotFound if node returns null for it.
+type BatchRequest struct {
+	elems   []rpc.BatchElem
+	setters []func(err error)
+}
+
+func NewBatchRequest() *BatchRequest {
+	return &BatchRequest{}
+}
+
+// Len returns the number of queued calls
+func (b *BatchRequest) Len() int {
+	return len(b.elems)
+}
+
+func (b *BatchRequest) add(setter func(err error), result interface{}, method string, args ...interface{}) {
+	b.elems = append(b.elems, rpc.BatchElem{
+		Method: method,
+		Args:   args,
+		Result: result,
+	})
+	b.setters = append(b.setters, setter)
+}
+
+// batchError returns NotFound for a successful call with null result
+func batchError(err error, found bool) error {
+	if err == nil && !found {
+		return NotFound
+	}
+	return err
+}
+
+func (b *BatchRequest) GetBlockHash(number uint64) *types.BatchHashItem {
+	item := &types.BatchHashItem{}
+	b.add(func(err error) {
+		item.Error = batchError(err, item.Result != nil)
+	}, &item.Result, "get_block_hash", hexutil.Uint64(number))
+	return item
+}
+
+func (b *BatchRequest) GetBlock(hash types.Hash) *types.BatchBlockItem {
+	item := &types.BatchBlockItem{}
+	b.add(func(err error) {
+		item.Error = batchError(err, item.Result != nil)
+	}, &item.Result, "get_block", hash)
+	return item
+}
+
+func (b *BatchRequest) GetBlockByNumber(number uint64) *types.BatchBlockItem {
+	item := &types.BatchBlockItem{}
+	b.add(func(err error) {
+		item.Error = batchError(err, item.Result != nil)
+	}, &item.Result, "get_block_by_number", hexutil.Uint64(number))
+	return item
+}
+
+func (b *BatchRequest) GetHeader(hash types.Hash) *types.BatchHeaderItem {
+	item := &types.BatchHeaderItem{}
+	b.add(func(err error) {
+		item.Error = batchError(err, item.Result != nil)
+	}, &item.Result, "get_header", hash)
+	return item
+}
+
+func (b *BatchRequest) GetHeaderByNumber(number uint64) *types.BatchHeaderItem {
+	item := &types.BatchHeaderItem{}
+	b.add(func(err error) {
+		item.Error = batchError(err, item.Result != nil)
+	}, &item.Result, "get_header_by_number", hexutil.Uint64(number))
+	return item
+}
+
+func (b *BatchRequest) GetEpochByNumber(number uint64) *types.BatchEpochItem {
+	item := &types.BatchEpochItem{}
+	b.add(func(err error) {
+		item.Error = batchError(err, item.Result != nil)
+	}, &item.Result, "get_epoch_by_number", hexutil.Uint64(number))
+	return item
+}
+
+func (b *BatchRequest) GetBlockEconomicState(hash types.Hash) *types.BatchBlockEconomicStateItem {
+	item := &types.BatchBlockEconomicStateItem{}
+	b.add(func(err error) {
+		item.Error = batchError(err, item.Result != nil)
+	}, &item.Result, "get_block_economic_state", hash)
+	return item
+}
+
+func (b *BatchRequest) GetTransactionProof(txHashes []string, blockHash *types.Hash) *types.BatchTransactionProofItem {
+	item := &types.BatchTransactionProofItem{}
+	b.add(func(err error) {
+		item.Error = batchError(err, item.Result != nil)
+	}, &item.Result, "get_transaction_proof", txHashes, blockHash)
+	return item
+}
+
+func (b *BatchRequest) GetTransaction(hash types.Hash) *types.BatchTransactionItem {
+	item := &types.BatchTransactionItem{Hash: hash}
+	b.add(func(err error) {
+		item.Error = batchError(err, item.Result != nil)
+	}, &item.Result, "get_transaction", hash)
+	return item
+}
+
+func (b *BatchRequest) GetLiveCell(outPoint types.OutPoint, withData bool) *types.BatchLiveCellItem {
+	item := &types.BatchLiveCellItem{OutPoint: outPoint, WithData: withData}
+	b.add(func(err error) {
+		item.Error = batchError(err, item.Result != nil)
+	}, &item.Result, "get_live_cell", outPoint, withData)
+	return item
+}
+
+// Call queues a call of any method, result should be a pointer to be unmarshalled into
+func (b *BatchRequest) Call(result interface{}, method string, args ...interface{}) *types.BatchCallItem {
+	item := &types.BatchCallItem{
+		Method: method,
+		Args:   args,
+		Result: result,
+	}
+	b.add(func(err error) {
+		item.Error = err
+	}, result, method, args...)
+	return item
+}
@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type jsonRpcMessage struct {
+	Version string            `json:"jsonrpc"`
+	Id      json.RawMessage   `json:"id"`
+	Method  string            `json:"method,omitempty"`
+	Params  []json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage   `json:"result,omitempty"`
+	Error   *jsonRpcError     `json:"error,omitempty"`
+}
+
+type jsonRpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// newBatchTestClient serves each call of a batch by handle
+func newBatchTestClient(t *testing.T, handle func(method string, params []json.RawMessage) (interface{}, *jsonRpcError)) (Client, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests += 1
+		var batch []*jsonRpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Fatal(err)
+		}
+		var resp []*jsonRpcMessage
+		for _, msg := range batch {
+			result, rpcErr := handle(msg.Method, msg.Params)
+			raw, _ := json.Marshal(result)
+			out := &jsonRpcMessage{Version: "2.0", Id: msg.Id, Error: rpcErr}
+			if rpcErr == nil {
+				out.Result = raw
+			}
+			resp = append(resp, out)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	c, err := rpc.DialHTTP(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewClient(c), &requests
+}
+
+func TestClient_BatchCall(t *testing.T) {
+	client, requests := newBatchTestClient(t, func(method string, params []json.RawMessage) (interface{}, *jsonRpcError) {
+		switch method {
+		case "get_header_by_number":
+			if string(params[0]) == `"0x3"` {
+				return nil, nil
+			}
+			return map[string]interface{}{"number": params[0], "hash": "0x0000000000000000000000000000000000000000000000000000000000000001"}, nil
+		case "get_block_hash":
+			return "0x0000000000000000000000000000000000000000000000000000000000000002", nil
+		case "get_tip_block_number":
+			return "0x10", nil
+		default:
+			return nil, &jsonRpcError{Code: -32601, Message: "method not found"}
+		}
+	})
+
+	batch := NewBatchRequest()
+	var headers []*types.BatchHeaderItem
+	for i := uint64(1); i <= 3; i++ {
+		headers = append(headers, batch.GetHeaderByNumber(i))
+	}
+	hash := batch.GetBlockHash(1)
+	var tip string
+	call := batch.Call(&tip, "get_tip_block_number")
+	state := batch.GetBlockEconomicState(types.Hash{})
+	assert.Equal(t, 6, batch.Len())
+	assert.NoError(t, client.BatchCall(ctx, batch))
+	assert.Equal(t, 1, *requests)
+
+	assert.NoError(t, headers[0].Error)
+	assert.Equal(t, uint64(1), headers[0].Result.Number)
+	assert.Equal(t, uint64(2), headers[1].Result.Number)
+	// null result
+	assert.Nil(t, headers[2].Result)
+	assert.Equal(t, NotFound, headers[2].Error)
+	assert.Equal(t, types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002"), *hash.Result)
+	assert.NoError(t, call.Error)
+	assert.Equal(t, "0x10", tip)
+	assert.Nil(t, state.Result)
+	assert.Error(t, state.Error)
+	assert.NotEqual(t, NotFound, state.Error)
+
+	// empty batch is not sent
+	assert.NoError(t, client.BatchCall(ctx, NewBatchRequest()))
+	assert.Equal(t, 1, *requests)
+}
@@ -6,6 +6,7 @@ import (
 	"reflect"
 
 	"github.com/nervosnetwork/ckb-sdk-go/v2/indexer"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/rpc/rpcerror"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/types/molecule"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -168,6 +169,10 @@ type Client interface {
 	// Batch Live cells
 	BatchLiveCells(ctx context.Context, batch []types.BatchLiveCellItem) error
 
+	// BatchCall sends calls queued in batch in one round trip. The returned error is about the whole batch, while
+	// errors of each call are set in their items.
+	BatchCall(ctx context.Context, batch *BatchRequest) error
+
 	// GetCells returns the live cells collection by the lock or type script.
 	GetCells(ctx context.Context, searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.LiveCells, error)
 
@@ -194,13 +199,18 @@ type client struct {
 }
 
 func (cli *client) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
-	err := cli.c.CallContext(ctx, result, method, args...)
+	err := cli.callContext(ctx, result, method, args...)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// callContext calls method and maps JSON-RPC errors into typed errors of package rpcerror
+func (cli *client) callContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return rpcerror.FromError(cli.c.CallContext(ctx, result, method, args...))
+}
+
 func Dial(url string) (Client, error) {
 	return DialContext(context.Background(), url)
 }
@@ -225,7 +235,7 @@ func (cli *client) Close() {
 
 func (cli *client) GetTipBlockNumber(ctx context.Context) (uint64, error) {
 	var num hexutil.Uint64
-	err := cli.c.CallContext(ctx, &num, "get_tip_block_number")
+	err := cli.callContext(ctx, &num, "get_tip_block_number")
 	if err != nil {
 		return 0, err
 	}
@@ -234,7 +244,7 @@ func (cli *client) GetTipBlockNumber(ctx context.Context) (uint64, error) {
 
 func (cli *client) GetTipHeader(ctx context.Context) (*types.Header, error) {
 	var result types.Header
-	err := cli.c.CallContext(ctx, &result, "get_tip_header")
+	err := cli.callContext(ctx, &result, "get_tip_header")
 	if err != nil {
 		return nil, err
 	}
@@ -243,7 +253,7 @@ func (cli *client) GetTipHeader(ctx context.Context) (*types.Header, error) {
 
 func (cli *client) GetCurrentEpoch(ctx context.Context) (*types.Epoch, error) {
 	var result types.Epoch
-	err := cli.c.CallContext(ctx, &result, "get_current_epoch")
+	err := cli.callContext(ctx, &result, "get_current_epoch")
 	if err != nil {
 		return nil, err
 	}
@@ -252,7 +262,7 @@ func (cli *client) GetCurrentEpoch(ctx context.Context) (*types.Epoch, error) {
 
 func (cli *client) GetEpochByNumber(ctx context.Context, number uint64) (*types.Epoch, error) {
 	var result types.Epoch
-	err := cli.c.CallContext(ctx, &result, "get_epoch_by_number", hexutil.Uint64(number))
+	err := cli.callContext(ctx, &result, "get_epoch_by_number", hexutil.Uint64(number))
 	if err != nil {
 		return nil, err
 	}
@@ -262,7 +272,7 @@ func (cli *client) GetEpochByNumber(ctx context.Context, number uint64) (*types.
 func (cli *client) GetBlockHash(ctx context.Context, number uint64) (*types.Hash, error) {
 	var result types.Hash
 
-	err := cli.c.CallContext(ctx, &result, "get_block_hash", hexutil.Uint64(number))
+	err := cli.callContext(ctx, &result, "get_block_hash", hexutil.Uint64(number))
 	if err != nil {
 		return nil, err
 	}
@@ -272,7 +282,7 @@ func (cli *client) GetBlockHash(ctx context.Context, number uint64) (*types.Hash
 
 func (cli *client) GetBlock(ctx context.Context, hash types.Hash) (*types.Block, error) {
 	var result types.Block
-	err := cli.c.CallContext(ctx, &result, "get_block", hash)
+	err := cli.callContext(ctx, &result, "get_block", hash)
 	if err != nil {
 		return nil, err
 	}
@@ -284,7 +294,7 @@ func (cli *client) GetBlock(ctx context.Context, hash types.Hash) (*types.Block,
 
 func (cli *client) GetPackedBlock(ctx context.Context, hash types.Hash) (*types.Block, error) {
 	var jsonResult types.PackedBlock
-	err := cli.c.CallContext(ctx, &jsonResult, "get_block", hash, hexutil.Uint64(0))
+	err := cli.callContext(ctx, &jsonResult, "get_block", hash, hexutil.Uint64(0))
 	if err != nil {
 		return nil, err
 	}
@@ -305,7 +315,7 @@ func (cli *client) GetPackedBlock(ctx context.Context, hash types.Hash) (*types.
 
 func (cli *client) GetBlockWithCycles(ctx context.Context, hash types.Hash) (*types.BlockWithCycles, error) {
 	var result types.BlockWithCycles
-	err := cli.c.CallContext(ctx, &result, "get_block", hash, nil, true)
+	err := cli.callContext(ctx, &result, "get_block", hash, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -317,7 +327,7 @@ func (cli *client) GetBlockWithCycles(ctx context.Context, hash types.Hash) (*ty
 
 func (cli *client) GetPackedBlockWithCycles(ctx context.Context, hash types.Hash) (*types.BlockWithCycles, error) {
 	var jsonResult types.PackedBlockWithCycles
-	err := cli.c.CallContext(ctx, &jsonResult, "get_block", hash, hexutil.Uint64(0), true)
+	err := cli.callContext(ctx, &jsonResult, "get_block", hash, hexutil.Uint64(0), true)
 	if err != nil {
 		return nil, err
 	}
@@ -341,7 +351,7 @@ func (cli *client) GetPackedBlockWithCycles(ctx context.Context, hash types.Hash
 
 func (cli *client) GetHeader(ctx context.Context, hash types.Hash) (*types.Header, error) {
 	var result types.Header
-	err := cli.c.CallContext(ctx, &result, "get_header", hash)
+	err := cli.callContext(ctx, &result, "get_header", hash)
 	if err != nil {
 		return nil, err
 	}
@@ -350,7 +360,7 @@ func (cli *client) GetHeader(ctx context.Context, hash types.Hash) (*types.Heade
 
 func (cli *client) GetPackedHeader(ctx context.Context, hash types.Hash) (*types.Header, error) {
 	var headerHash string
-	err := cli.c.CallContext(ctx, &headerHash, "get_header", hash, hexutil.Uint64(0))
+	err := cli.callContext(ctx, &headerHash, "get_header", hash, hexutil.Uint64(0))
 	if err != nil {
 		return nil, err
 	}
@@ -367,7 +377,7 @@ func (cli *client) GetPackedHeader(ctx context.Context, hash types.Hash) (*types
 
 func (cli *client) GetHeaderByNumber(ctx context.Context, number uint64) (*types.Header, error) {
 	var result types.Header
-	err := cli.c.CallContext(ctx, &result, "get_header_by_number", hexutil.Uint64(number))
+	err := cli.callContext(ctx, &result, "get_header_by_number", hexutil.Uint64(number))
 	if err != nil {
 		return nil, err
 	}
@@ -376,7 +386,7 @@ func (cli *client) GetHeaderByNumber(ctx context.Context, number uint64) (*types
 
 func (cli *client) GetPackedHeaderByNumber(ctx context.Context, number uint64) (*types.Header, error) {
 	var headerHash string
-	err := cli.c.CallContext(ctx, &headerHash, "get_header_by_number", hexutil.Uint64(number), hexutil.Uint64(0))
+	err := cli.callContext(ctx, &headerHash, "get_header_by_number", hexutil.Uint64(number), hexutil.Uint64(0))
 	if err != nil {
 		return nil, err
 	}
@@ -393,7 +403,7 @@ func (cli *client) GetPackedHeaderByNumber(ctx context.Context, number uint64) (
 
 func (cli *client) GetTransactionProof(ctx context.Context, txHashes []string, blockHash *types.Hash) (*types.TransactionProof, error) {
 	var transactionProof types.TransactionProof
-	err := cli.c.CallContext(ctx, &transactionProof, "get_transaction_proof", txHashes, blockHash)
+	err := cli.callContext(ctx, &transactionProof, "get_transaction_proof", txHashes, blockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -403,7 +413,7 @@ func (cli *client) GetTransactionProof(ctx context.Context, txHashes []string, b
 
 func (cli *client) VerifyTransactionProof(ctx context.Context, proof *types.TransactionProof) ([]*types.Hash, error) {
 	var result []*types.Hash
-	err := cli.c.CallContext(ctx, &result, "verify_transaction_proof", *proof)
+	err := cli.callContext(ctx, &result, "verify_transaction_proof", *proof)
 	if err != nil {
 		return nil, err
 	}
@@ -414,7 +424,7 @@ func (cli *client) VerifyTransactionProof(ctx context.Context, proof *types.Tran
 // GetTransactionAndWitnessProof implements Client
 func (cli *client) GetTransactionAndWitnessProof(ctx context.Context, txHashes []string, blockHash *types.Hash) (*types.TransactionAndWitnessProof, error) {
 	var transactionAndWitnessProof types.TransactionAndWitnessProof
-	err := cli.c.CallContext(ctx, &transactionAndWitnessProof, "get_transaction_and_witness_proof", txHashes, blockHash)
+	err := cli.callContext(ctx, &transactionAndWitnessProof, "get_transaction_and_witness_proof", txHashes, blockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -424,7 +434,7 @@ func (cli *client) GetTransactionAndWitnessProof(ctx context.Context, txHashes [
 // VerifyTransactionAndWitnessProof implements Client
 func (cli *client) VerifyTransactionAndWitnessProof(ctx context.Context, proof *types.TransactionAndWitnessProof) ([]*types.Hash, error) {
 	var result []*types.Hash
-	err := cli.c.CallContext(ctx, &result, "verify_transaction_and_witness_proof", *proof)
+	err := cli.callContext(ctx, &result, "verify_transaction_and_witness_proof", *proof)
 	if err != nil {
 		return nil, err
 	}
@@ -433,7 +443,7 @@ func (cli *client) VerifyTransactionAndWitnessProof(ctx context.Context, proof *
 
 func (cli *client) GetLiveCell(ctx context.Context, point *types.OutPoint, withData bool) (*types.CellWithStatus, error) {
 	var result types.CellWithStatus
-	err := cli.c.CallContext(ctx, &result, "get_live_cell", *point, withData)
+	err := cli.callContext(ctx, &result, "get_live_cell", *point, withData)
 	if err != nil {
 		return nil, err
 	}
@@ -442,7 +452,7 @@ func (cli *client) GetLiveCell(ctx context.Context, point *types.OutPoint, withD
 
 func (cli *client) GetTransaction(ctx context.Context, hash types.Hash) (*types.TransactionWithStatus, error) {
 	var result types.TransactionWithStatus
-	err := cli.c.CallContext(ctx, &result, "get_transaction", hash)
+	err := cli.callContext(ctx, &result, "get_transaction", hash)
 	if err != nil {
 		return nil, err
 	}
@@ -451,7 +461,7 @@ func (cli *client) GetTransaction(ctx context.Context, hash types.Hash) (*types.
 
 func (cli *client) GetBlockByNumber(ctx context.Context, number uint64) (*types.Block, error) {
 	var result types.Block
-	err := cli.c.CallContext(ctx, &result, "get_block_by_number", hexutil.Uint64(number))
+	err := cli.callContext(ctx, &result, "get_block_by_number", hexutil.Uint64(number))
 	if err != nil {
 		return nil, err
 	}
@@ -463,7 +473,7 @@ func (cli *client) GetBlockByNumber(ctx context.Context, number uint64) (*types.
 
 func (cli *client) GetBlockByNumberWithCycles(ctx context.Context, number uint64) (*types.BlockWithCycles, error) {
 	var result types.BlockWithCycles
-	err := cli.c.CallContext(ctx, &result, "get_block_by_number", hexutil.Uint64(number), nil, true)
+	err := cli.callContext(ctx, &result, "get_block_by_number", hexutil.Uint64(number), nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -475,7 +485,7 @@ func (cli *client) GetBlockByNumberWithCycles(ctx context.Context, number uint64
 
 func (cli *client) GetForkBlock(ctx context.Context, blockHash types.Hash) (*types.Block, error) {
 	var block types.Block
-	err := cli.c.CallContext(ctx, &block, "get_fork_block", blockHash)
+	err := cli.callContext(ctx, &block, "get_fork_block", blockHash)
 	if err != nil {
 		return nil, nil
 	}
@@ -488,7 +498,7 @@ func (cli *client) GetForkBlock(ctx context.Context, blockHash types.Hash) (*typ
 
 func (cli *client) DryRunTransaction(ctx context.Context, transaction *types.Transaction) (*types.DryRunTransactionResult, error) {
 	var result types.DryRunTransactionResult
-	err := cli.c.CallContext(ctx, &result, "dry_run_transaction", *transaction)
+	err := cli.callContext(ctx, &result, "dry_run_transaction", *transaction)
 	if err != nil {
 		return nil, err
 	}
@@ -498,7 +508,7 @@ func (cli *client) DryRunTransaction(ctx context.Context, transaction *types.Tra
 
 func (cli *client) EstimateCycles(ctx context.Context, transaction *types.Transaction) (*types.EstimateCycles, error) {
 	var result types.EstimateCycles
-	err := cli.c.CallContext(ctx, &result, "estimate_cycles", *transaction)
+	err := cli.callContext(ctx, &result, "estimate_cycles", *transaction)
 	if err != nil {
 		return nil, err
 	}
@@ -508,7 +518,7 @@ func (cli *client) EstimateCycles(ctx context.Context, transaction *types.Transa
 
 func (cli *client) CalculateDaoMaximumWithdraw(ctx context.Context, point *types.OutPoint, hash types.Hash) (uint64, error) {
 	var result hexutil.Uint64
-	err := cli.c.CallContext(ctx, &result, "calculate_dao_maximum_withdraw", *point, hash)
+	err := cli.callContext(ctx, &result, "calculate_dao_maximum_withdraw", *point, hash)
 	if err != nil {
 		return 0, err
 	}
@@ -518,7 +528,7 @@ func (cli *client) CalculateDaoMaximumWithdraw(ctx context.Context, point *types
 
 func (cli *client) GetConsensus(ctx context.Context) (*types.Consensus, error) {
 	var result types.Consensus
-	err := cli.c.CallContext(ctx, &result, "get_consensus")
+	err := cli.callContext(ctx, &result, "get_consensus")
 	if err != nil {
 		return nil, nil
 	}
@@ -527,7 +537,7 @@ func (cli *client) GetConsensus(ctx context.Context) (*types.Consensus, error) {
 
 func (cli *client) GetBlockMedianTime(ctx context.Context, blockHash types.Hash) (uint64, error) {
 	var result hexutil.Uint64
-	err := cli.c.CallContext(ctx, &result, "get_block_median_time", blockHash)
+	err := cli.callContext(ctx, &result, "get_block_median_time", blockHash)
 	if err != nil {
 		return uint64(result), nil
 	}
@@ -538,12 +548,12 @@ func (cli *client) GetFeeRateStatics(ctx context.Context, target interface{}) (*
 	var result types.FeeRateStatics
 	switch target := target.(type) {
 	case nil:
-		if err := cli.c.CallContext(ctx, &result, "get_fee_rate_statics", nil); err != nil {
+		if err := cli.callContext(ctx, &result, "get_fee_rate_statics", nil); err != nil {
 			return nil, err
 		}
 		break
 	case uint64:
-		if err := cli.c.CallContext(ctx, &result, "get_fee_rate_statics", hexutil.Uint64(target)); err != nil {
+		if err := cli.callContext(ctx, &result, "get_fee_rate_statics", hexutil.Uint64(target)); err != nil {
 			return nil, err
 		}
 		break
@@ -551,7 +561,7 @@ func (cli *client) GetFeeRateStatics(ctx context.Context, target interface{}) (*
 	case int:
 	case int32:
 	case int64:
-		if err := cli.c.CallContext(ctx, &result, "get_fee_rate_statics", hexutil.Uint64(uint64(target))); err != nil {
+		if err := cli.callContext(ctx, &result, "get_fee_rate_statics", hexutil.Uint64(uint64(target))); err != nil {
 			return nil, err
 		}
 		break
@@ -562,7 +572,7 @@ func (cli *client) GetFeeRateStatics(ctx context.Context, target interface{}) (*
 func (cli *client) LocalNodeInfo(ctx context.Context) (*types.LocalNode, error) {
 	var result types.LocalNode
 
-	err := cli.c.CallContext(ctx, &result, "local_node_info")
+	err := cli.callContext(ctx, &result, "local_node_info")
 	if err != nil {
 		return nil, err
 	}
@@ -573,7 +583,7 @@ func (cli *client) LocalNodeInfo(ctx context.Context) (*types.LocalNode, error)
 func (cli *client) GetPeers(ctx context.Context) ([]*types.RemoteNode, error) {
 	var result []*types.RemoteNode
 
-	err := cli.c.CallContext(ctx, &result, "get_peers")
+	err := cli.callContext(ctx, &result, "get_peers")
 	if err != nil {
 		return nil, err
 	}
@@ -583,7 +593,7 @@ func (cli *client) GetPeers(ctx context.Context) ([]*types.RemoteNode, error) {
 
 func (cli *client) GetBannedAddresses(ctx context.Context) ([]*types.BannedAddress, error) {
 	var result []*types.BannedAddress
-	err := cli.c.CallContext(ctx, &result, "get_banned_addresses")
+	err := cli.callContext(ctx, &result, "get_banned_addresses")
 	if err != nil {
 		return nil, err
 	}
@@ -592,16 +602,16 @@ func (cli *client) GetBannedAddresses(ctx context.Context) ([]*types.BannedAddre
 }
 
 func (cli *client) ClearBannedAddresses(ctx context.Context) error {
-	return cli.c.CallContext(ctx, nil, "clear_banned_addresses")
+	return cli.callContext(ctx, nil, "clear_banned_addresses")
 }
 
 func (cli *client) SetBan(ctx context.Context, address string, command string, banTime uint64, absolute bool, reason string) error {
-	return cli.c.CallContext(ctx, nil, "set_ban", address, command, hexutil.Uint64(banTime), absolute, reason)
+	return cli.callContext(ctx, nil, "set_ban", address, command, hexutil.Uint64(banTime), absolute, reason)
 }
 
 func (cli *client) SyncState(ctx context.Context) (*types.SyncState, error) {
 	var result types.SyncState
-	err := cli.c.CallContext(ctx, &result, "sync_state")
+	err := cli.callContext(ctx, &result, "sync_state")
 	if err != nil {
 		return nil, err
 	}
@@ -609,7 +619,7 @@ func (cli *client) SyncState(ctx context.Context) (*types.SyncState, error) {
 }
 
 func (cli *client) SetNetworkActive(ctx context.Context, state bool) error {
-	err := cli.c.CallContext(ctx, nil, "set_network_active", state)
+	err := cli.callContext(ctx, nil, "set_network_active", state)
 	if err != nil {
 		return err
 	}
@@ -617,7 +627,7 @@ func (cli *client) SetNetworkActive(ctx context.Context, state bool) error {
 }
 
 func (cli *client) AddNode(ctx context.Context, peerId, address string) error {
-	err := cli.c.CallContext(ctx, nil, "add_node", peerId, address)
+	err := cli.callContext(ctx, nil, "add_node", peerId, address)
 	if err != nil {
 		return err
 	}
@@ -625,7 +635,7 @@ func (cli *client) AddNode(ctx context.Context, peerId, address string) error {
 }
 
 func (cli *client) RemoveNode(ctx context.Context, peerId string) error {
-	err := cli.c.CallContext(ctx, nil, "remove_node", peerId)
+	err := cli.callContext(ctx, nil, "remove_node", peerId)
 	if err != nil {
 		return err
 	}
@@ -633,7 +643,7 @@ func (cli *client) RemoveNode(ctx context.Context, peerId string) error {
 }
 
 func (cli *client) PingPeers(ctx context.Context) error {
-	err := cli.c.CallContext(ctx, nil, "ping_peers")
+	err := cli.callContext(ctx, nil, "ping_peers")
 	if err != nil {
 		return err
 	}
@@ -643,7 +653,7 @@ func (cli *client) PingPeers(ctx context.Context) error {
 func (cli *client) SendTransaction(ctx context.Context, tx *types.Transaction) (*types.Hash, error) {
 	var result types.Hash
 
-	err := cli.c.CallContext(ctx, &result, "send_transaction", *tx, "passthrough")
+	err := cli.callContext(ctx, &result, "send_transaction", *tx, "passthrough")
 	if err != nil {
 		return nil, err
 	}
@@ -653,7 +663,7 @@ func (cli *client) SendTransaction(ctx context.Context, tx *types.Transaction) (
 
 func (cli *client) TxPoolInfo(ctx context.Context) (*types.TxPoolInfo, error) {
 	var result types.TxPoolInfo
-	err := cli.c.CallContext(ctx, &result, "tx_pool_info")
+	err := cli.callContext(ctx, &result, "tx_pool_info")
 	if err != nil {
 		return nil, err
 	}
@@ -662,7 +672,7 @@ func (cli *client) TxPoolInfo(ctx context.Context) (*types.TxPoolInfo, error) {
 
 func (cli *client) GetRawTxPool(ctx context.Context) (*types.RawTxPool, error) {
 	var txPool types.RawTxPool
-	err := cli.c.CallContext(ctx, &txPool, "get_raw_tx_pool")
+	err := cli.callContext(ctx, &txPool, "get_raw_tx_pool")
 	if err != nil {
 		return nil, err
 	}
@@ -671,12 +681,12 @@ func (cli *client) GetRawTxPool(ctx context.Context) (*types.RawTxPool, error) {
 }
 
 func (cli *client) ClearTxPool(ctx context.Context) error {
-	return cli.c.CallContext(ctx, nil, "clear_tx_pool")
+	return cli.callContext(ctx, nil, "clear_tx_pool")
 }
 
 func (cli *client) GetBlockchainInfo(ctx context.Context) (*types.BlockchainInfo, error) {
 	var result types.BlockchainInfo
-	err := cli.c.CallContext(ctx, &result, "get_blockchain_info")
+	err := cli.callContext(ctx, &result, "get_blockchain_info")
 	if err != nil {
 		return nil, err
 	}
@@ -698,11 +708,11 @@ func (cli *client) BatchTransactions(ctx context.Context, batch []types.BatchTra
 
 	err := cli.c.BatchCallContext(ctx, req)
 	if err != nil {
-		return err
+		return rpcerror.FromError(err)
 	}
 
 	for i, item := range req {
-		batch[i].Error = item.Error
+		batch[i].Error = rpcerror.FromError(item.Error)
 		if batch[i].Error == nil {
 			batch[i].Result = item.Result.(*types.TransactionWithStatus)
 		}
@@ -727,11 +737,11 @@ func (cli *client) BatchLiveCells(ctx context.Context, batch []types.BatchLiveCe
 
 	err := cli.c.BatchCallContext(ctx, req)
 	if err != nil {
-		return err
+		return rpcerror.FromError(err)
 	}
 
 	for i, item := range req {
-		batch[i].Error = item.Error
+		batch[i].Error = rpcerror.FromError(item.Error)
 		if batch[i].Error == nil {
 			batch[i].Result = item.Result.(*types.CellWithStatus)
 		}
@@ -739,9 +749,22 @@ func (cli *client) BatchLiveCells(ctx context.Context, batch []types.BatchLiveCe
 	return nil
 }
 
+func (cli *client) BatchCall(ctx context.Context, batch *BatchRequest) error {
+	if batch.Len() == 0 {
+		return nil
+	}
+	if err := cli.c.BatchCallContext(ctx, batch.elems); err != nil {
+		return rpcerror.FromError(err)
+	}
+	for i, elem := range batch.elems {
+		batch.setters[i](rpcerror.FromError(elem.Error))
+	}
+	return nil
+}
+
 func (cli *client) GetIndexerTip(ctx context.Context) (*indexer.TipHeader, error) {
 	var result indexer.TipHeader
-	err := cli.c.CallContext(ctx, &result, "get_indexer_tip")
+	err := cli.callContext(ctx, &result, "get_indexer_tip")
 	if err != nil {
 		return nil, err
 	}
@@ -750,7 +773,7 @@ func (cli *client) GetIndexerTip(ctx context.Context) (*indexer.TipHeader, error
 
 func (cli *client) GetCellsCapacity(ctx context.Context, searchKey *indexer.SearchKey) (*indexer.Capacity, error) {
 	var result indexer.Capacity
-	err := cli.c.CallContext(ctx, &result, "get_cells_capacity", searchKey)
+	err := cli.callContext(ctx, &result, "get_cells_capacity", searchKey)
 	if err != nil {
 		return nil, err
 	}
@@ -763,9 +786,9 @@ func (cli *client) GetCells(ctx context.Context, searchKey *indexer.SearchKey, o
 		err    error
 	)
 	if afterCursor == "" {
-		err = cli.c.CallContext(ctx, &result, "get_cells", searchKey, order, hexutil.Uint64(limit))
+		err = cli.callContext(ctx, &result, "get_cells", searchKey, order, hexutil.Uint64(limit))
 	} else {
-		err = cli.c.CallContext(ctx, &result, "get_cells", searchKey, order, hexutil.Uint64(limit), afterCursor)
+		err = cli.callContext(ctx, &result, "get_cells", searchKey, order, hexutil.Uint64(limit), afterCursor)
 	}
 	if err != nil {
 		return nil, err
@@ -779,9 +802,9 @@ func (cli *client) GetTransactions(ctx context.Context, searchKey *indexer.Searc
 		err    error
 	)
 	if afterCursor == "" {
-		err = cli.c.CallContext(ctx, &result, "get_transactions", searchKey, order, hexutil.Uint64(limit))
+		err = cli.callContext(ctx, &result, "get_transactions", searchKey, order, hexutil.Uint64(limit))
 	} else {
-		err = cli.c.CallContext(ctx, &result, "get_transactions", searchKey, order, hexutil.Uint64(limit), afterCursor)
+		err = cli.callContext(ctx, &result, "get_transactions", searchKey, order, hexutil.Uint64(limit), afterCursor)
 	}
 	if err != nil {
 		return nil, err
@@ -800,9 +823,9 @@ func (cli *client) GetTransactionsGrouped(ctx context.Context, searchKey *indexe
 	var result indexer.TxsWithCells
 	var err error
 	if afterCursor == "" {
-		err = cli.c.CallContext(ctx, &result, "get_transactions", payload, order, hexutil.Uint64(limit))
+		err = cli.callContext(ctx, &result, "get_transactions", payload, order, hexutil.Uint64(limit))
 	} else {
-		err = cli.c.CallContext(ctx, &result, "get_transactions", payload, order, hexutil.Uint64(limit), afterCursor)
+		err = cli.callContext(ctx, &result, "get_transactions", payload, order, hexutil.Uint64(limit), afterCursor)
 	}
 	if err != nil {
 		return nil, err
@@ -812,7 +835,7 @@ func (cli *client) GetTransactionsGrouped(ctx context.Context, searchKey *indexe
 
 func (cli *client) GetBlockEconomicState(ctx context.Context, blockHash types.Hash) (*types.BlockEconomicState, error) {
 	var result types.BlockEconomicState
-	err := cli.c.CallContext(ctx, &result, "get_block_economic_state", blockHash)
+	err := cli.callContext(ctx, &result, "get_block_economic_state", blockHash)
 	if err != nil {
 		return nil, err
 	}
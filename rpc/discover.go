@@ -0,0 +1,29 @@
+package rpc
+
+import (
+	"context"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+)
+
+// DiscoverNetwork fetches the genesis block and consensus from node, and registers the system scripts derived by
+// systemscript.NewInfosFromGenesis for network. Other scripts of network, e.g. SUDT, should be registered by
+// systemscript.RegisterInfo.
+func DiscoverNetwork(ctx context.Context, client Client, network types.Network) error {
+	genesis, err := client.GetBlockByNumber(ctx, 0)
+	if err != nil {
+		return err
+	}
+	consensus, err := client.GetConsensus(ctx)
+	if err != nil {
+		return err
+	}
+	contracts, err := systemscript.NewInfosFromGenesis(genesis, consensus)
+	if err != nil {
+		return err
+	}
+	for script, info := range contracts {
+		systemscript.RegisterInfo(network, script, info)
+	}
+	return nil
+}
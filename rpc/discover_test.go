@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type genesisMockClient struct {
+	Client
+	block     *types.Block
+	consensus *types.Consensus
+}
+
+func (c *genesisMockClient) GetBlockByNumber(ctx context.Context, number uint64) (*types.Block, error) {
+	return c.block, nil
+}
+
+func (c *genesisMockClient) GetConsensus(ctx context.Context) (*types.Consensus, error) {
+	return c.consensus, nil
+}
+
+// getGenesisBlock returns a genesis block with type id code cells and secp256k1 dep groups at the system positions
+func getGenesisBlock() (*types.Block, *types.Consensus) {
+	cellbase := &types.Transaction{}
+	for i := 0; i < 5; i++ {
+		cellbase.Outputs = append(cellbase.Outputs, &types.CellOutput{
+			Capacity: 100000000000,
+			Lock:     &types.Script{CodeHash: types.Hash{}, HashType: types.HashTypeData, Args: []byte{}},
+			Type:     systemscript.TypeIdScript(&types.CellInput{PreviousOutput: &types.OutPoint{}}, uint64(i)),
+		})
+		cellbase.OutputsData = append(cellbase.OutputsData, []byte{byte(i)})
+	}
+	cellbaseHash := cellbase.ComputeHash()
+	depGroup := func(indices ...uint32) []byte {
+		data := make([]byte, 4)
+		binary.LittleEndian.PutUint32(data, uint32(len(indices)))
+		for _, i := range indices {
+			data = append(data, (&types.OutPoint{TxHash: cellbaseHash, Index: i}).Serialize()...)
+		}
+		return data
+	}
+	depGroupTx := &types.Transaction{
+		Outputs: []*types.CellOutput{
+			{Capacity: 100000000000, Lock: cellbase.Outputs[0].Lock},
+			{Capacity: 100000000000, Lock: cellbase.Outputs[0].Lock},
+		},
+		OutputsData: [][]byte{depGroup(3, 1), depGroup(3, 4)},
+	}
+	sighashAll := cellbase.Outputs[1].Type.Hash()
+	dao := cellbase.Outputs[2].Type.Hash()
+	multisigAll := cellbase.Outputs[4].Type.Hash()
+	block := &types.Block{
+		Header:       &types.Header{Hash: types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001")},
+		Transactions: []*types.Transaction{cellbase, depGroupTx},
+	}
+	consensus := &types.Consensus{
+		GenesisHash:                          block.Header.Hash,
+		DaoTypeHash:                          &dao,
+		Secp256k1Blake160SighashAllTypeHash:  &sighashAll,
+		Secp256k1Blake160MultisigAllTypeHash: &multisigAll,
+	}
+	return block, consensus
+}
+
+func TestDiscoverNetwork(t *testing.T) {
+	devnet := types.Network(101)
+	defer systemscript.UnregisterNetwork(devnet)
+	block, consensus := getGenesisBlock()
+	assert.NoError(t, DiscoverNetwork(context.Background(), &genesisMockClient{block: block, consensus: consensus}, devnet))
+	assert.True(t, systemscript.IsKnownNetwork(devnet))
+	assert.Equal(t, *consensus.DaoTypeHash, systemscript.GetCodeHash(devnet, systemscript.Dao))
+	assert.Nil(t, systemscript.GetInfo(devnet, systemscript.Sudt))
+
+	consensus.GenesisHash = types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002")
+	assert.Error(t, DiscoverNetwork(context.Background(), &genesisMockClient{block: block, consensus: consensus}, types.Network(102)))
+	assert.False(t, systemscript.IsKnownNetwork(types.Network(102)))
+}
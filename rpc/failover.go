@@ -0,0 +1,713 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/indexer"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/rpc/rpcerror"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultHealthCheckInterval = 10 * time.Second
+	DefaultMaxLag              = 3
+	DefaultMaxRetries          = 2
+	DefaultRetryBackoff        = 200 * time.Millisecond
+)
+
+// EndpointStatus is the result of the last health check of an endpoint
+type EndpointStatus struct {
+	// Index is the position of endpoint in the arguments of NewFailoverClient
+	Index int
+	// Healthy is false if the last check or call failed, or the endpoint hasn't been checked
+	Healthy bool
+	// Lagging is true if the endpoint is in initial block download or falls behind by more than MaxLag blocks
+	Lagging   bool
+	TipNumber uint64
+	// Err is the error of the last check or call, if it failed
+	Err error
+}
+
+type endpoint struct {
+	client Client
+	status EndpointStatus
+}
+
+// FailoverClient is a Client over a pool of nodes. Calls go to healthy endpoints which are in sync with the highest
+// tip, and fall back to the others in order.
+//
+// Reads are retried on other endpoints with backoff when the transport fails. Errors returned by node are not retried.
+// Writes, e.g. SendTransaction and CallContext, are sent once and only go to the next endpoint if the connection
+// can't be established, so that a transaction is never broadcast twice blindly.
+type FailoverClient struct {
+	// MaxLag is the number of blocks an endpoint can fall behind the highest tip before calls are routed away from it
+	MaxLag uint64
+	// MaxRetries is the number of extra rounds over endpoints when all of them fail on a read
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, which doubles for each following retry
+	RetryBackoff time.Duration
+
+	endpoints []*endpoint
+	mutex     sync.RWMutex
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewFailoverClient creates a FailoverClient over clients, which are preferred in order when they are equally healthy.
+// Health checks are not started until StartHealthCheck is called.
+func NewFailoverClient(clients ...Client) *FailoverClient {
+	endpoints := make([]*endpoint, len(clients))
+	for i, cli := range clients {
+		endpoints[i] = &endpoint{client: cli, status: EndpointStatus{Index: i}}
+	}
+	return &FailoverClient{
+		MaxLag:       DefaultMaxLag,
+		MaxRetries:   DefaultMaxRetries,
+		RetryBackoff: DefaultRetryBackoff,
+		endpoints:    endpoints,
+		stop:         make(chan struct{}),
+	}
+}
+
+// DialFailover is DialFailoverContext with background context
+func DialFailover(urls ...string) (*FailoverClient, error) {
+	return DialFailoverContext(context.Background(), urls...)
+}
+
+// DialFailoverContext dials urls, checks their health once and starts health checks every DefaultHealthCheckInterval.
+func DialFailoverContext(ctx context.Context, urls ...string) (*FailoverClient, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no endpoint")
+	}
+	clients := make([]Client, len(urls))
+	for i, url := range urls {
+		cli, err := DialContext(ctx, url)
+		if err != nil {
+			for _, c := range clients[:i] {
+				c.Close()
+			}
+			return nil, err
+		}
+		clients[i] = cli
+	}
+	c := NewFailoverClient(clients...)
+	c.CheckHealth(ctx)
+	c.StartHealthCheck(DefaultHealthCheckInterval)
+	return c, nil
+}
+
+// StartHealthCheck checks health of endpoints every interval in background, until Close is called.
+func (c *FailoverClient) StartHealthCheck(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				c.CheckHealth(ctx)
+				cancel()
+			}
+		}
+	}()
+}
+
+// CheckHealth queries tip and sync state of all endpoints, and updates their status.
+func (c *FailoverClient) CheckHealth(ctx context.Context) {
+	type result struct {
+		tip       uint64
+		syncState *types.SyncState
+		err       error
+	}
+	results := make([]result, len(c.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range c.endpoints {
+		wg.Add(1)
+		go func(i int, cli Client) {
+			defer wg.Done()
+			r := &results[i]
+			if r.tip, r.err = cli.GetTipBlockNumber(ctx); r.err != nil {
+				return
+			}
+			r.syncState, r.err = cli.SyncState(ctx)
+		}(i, e.client)
+	}
+	wg.Wait()
+
+	// the highest tip is what the healthy endpoints have reached or known from their peers
+	var highest uint64
+	for _, r := range results {
+		if r.err != nil {
+			continue
+		}
+		if r.tip > highest {
+			highest = r.tip
+		}
+		if r.syncState != nil && r.syncState.BestKnownBlockNumber > highest {
+			highest = r.syncState.BestKnownBlockNumber
+		}
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, e := range c.endpoints {
+		r := results[i]
+		e.status.Err = r.err
+		e.status.Healthy = r.err == nil
+		if r.err != nil {
+			continue
+		}
+		e.status.TipNumber = r.tip
+		e.status.Lagging = (r.syncState != nil && r.syncState.Ibd) || r.tip+c.MaxLag < highest
+	}
+}
+
+// Endpoints returns status of endpoints in the order of NewFailoverClient
+func (c *FailoverClient) Endpoints() []EndpointStatus {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	statuses := make([]EndpointStatus, len(c.endpoints))
+	for i, e := range c.endpoints {
+		statuses[i] = e.status
+	}
+	return statuses
+}
+
+// candidates returns healthy endpoints in sync, followed by lagging and unhealthy ones
+func (c *FailoverClient) candidates() []*endpoint {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	var preferred, lagging, unhealthy []*endpoint
+	for _, e := range c.endpoints {
+		switch {
+		case !e.status.Healthy:
+			unhealthy = append(unhealthy, e)
+		case e.status.Lagging:
+			lagging = append(lagging, e)
+		default:
+			preferred = append(preferred, e)
+		}
+	}
+	return append(append(preferred, lagging...), unhealthy...)
+}
+
+func (c *FailoverClient) markUnhealthy(e *endpoint, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	e.status.Healthy = false
+	e.status.Err = err
+}
+
+// read calls endpoints in turn until one succeeds, and retries with backoff after all of them fail
+func (c *FailoverClient) read(ctx context.Context, call func(cli Client) error) error {
+	if len(c.endpoints) == 0 {
+		return errors.New("no endpoint")
+	}
+	var err error
+	backoff := c.RetryBackoff
+	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		for _, e := range c.candidates() {
+			if err = call(e.client); err == nil || !isRetryable(ctx, err) {
+				return err
+			}
+			c.markUnhealthy(e, err)
+		}
+	}
+	return err
+}
+
+// send calls endpoints in turn only until the request is delivered, whatever its result is
+func (c *FailoverClient) send(ctx context.Context, call func(cli Client) error) error {
+	if len(c.endpoints) == 0 {
+		return errors.New("no endpoint")
+	}
+	var err error
+	for _, e := range c.candidates() {
+		if err = call(e.client); err == nil || !isDialError(err) {
+			return err
+		}
+		c.markUnhealthy(e, err)
+	}
+	return err
+}
+
+// isRetryable returns false for errors returned by node, which would be the same on other nodes
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, NotFound) {
+		return false
+	}
+	var rpcErr *rpcerror.RPCError
+	return !errors.As(err, &rpcErr)
+}
+
+// isDialError returns true if the connection to endpoint can't be established, so the request is not sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// Close stops health checks and closes all endpoints
+func (c *FailoverClient) Close() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+		for _, e := range c.endpoints {
+			e.client.Close()
+		}
+	})
+}
+
+func (c *FailoverClient) GetTipBlockNumber(ctx context.Context) (uint64, error) {
+	var result uint64
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetTipBlockNumber(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetTipHeader(ctx context.Context) (*types.Header, error) {
+	var result *types.Header
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetTipHeader(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetCurrentEpoch(ctx context.Context) (*types.Epoch, error) {
+	var result *types.Epoch
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetCurrentEpoch(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetEpochByNumber(ctx context.Context, number uint64) (*types.Epoch, error) {
+	var result *types.Epoch
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetEpochByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetBlockHash(ctx context.Context, number uint64) (*types.Hash, error) {
+	var result *types.Hash
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetBlockHash(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetBlock(ctx context.Context, hash types.Hash) (*types.Block, error) {
+	var result *types.Block
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetBlock(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetPackedBlock(ctx context.Context, hash types.Hash) (*types.Block, error) {
+	var result *types.Block
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetPackedBlock(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetBlockWithCycles(ctx context.Context, hash types.Hash) (*types.BlockWithCycles, error) {
+	var result *types.BlockWithCycles
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetBlockWithCycles(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetPackedBlockWithCycles(ctx context.Context, hash types.Hash) (*types.BlockWithCycles, error) {
+	var result *types.BlockWithCycles
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetPackedBlockWithCycles(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetHeader(ctx context.Context, hash types.Hash) (*types.Header, error) {
+	var result *types.Header
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetHeader(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetPackedHeader(ctx context.Context, hash types.Hash) (*types.Header, error) {
+	var result *types.Header
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetPackedHeader(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetHeaderByNumber(ctx context.Context, number uint64) (*types.Header, error) {
+	var result *types.Header
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetHeaderByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetPackedHeaderByNumber(ctx context.Context, number uint64) (*types.Header, error) {
+	var result *types.Header
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetPackedHeaderByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetLiveCell(ctx context.Context, outPoint *types.OutPoint, withData bool) (*types.CellWithStatus, error) {
+	var result *types.CellWithStatus
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetLiveCell(ctx, outPoint, withData)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetTransaction(ctx context.Context, hash types.Hash) (*types.TransactionWithStatus, error) {
+	var result *types.TransactionWithStatus
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetTransaction(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetBlockEconomicState(ctx context.Context, hash types.Hash) (*types.BlockEconomicState, error) {
+	var result *types.BlockEconomicState
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetBlockEconomicState(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetTransactionProof(ctx context.Context, txHashes []string, blockHash *types.Hash) (*types.TransactionProof, error) {
+	var result *types.TransactionProof
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetTransactionProof(ctx, txHashes, blockHash)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) VerifyTransactionProof(ctx context.Context, proof *types.TransactionProof) ([]*types.Hash, error) {
+	var result []*types.Hash
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.VerifyTransactionProof(ctx, proof)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetTransactionAndWitnessProof(ctx context.Context, txHashes []string, blockHash *types.Hash) (*types.TransactionAndWitnessProof, error) {
+	var result *types.TransactionAndWitnessProof
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetTransactionAndWitnessProof(ctx, txHashes, blockHash)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) VerifyTransactionAndWitnessProof(ctx context.Context, proof *types.TransactionAndWitnessProof) ([]*types.Hash, error) {
+	var result []*types.Hash
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.VerifyTransactionAndWitnessProof(ctx, proof)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetBlockByNumber(ctx context.Context, number uint64) (*types.Block, error) {
+	var result *types.Block
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetBlockByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetBlockByNumberWithCycles(ctx context.Context, number uint64) (*types.BlockWithCycles, error) {
+	var result *types.BlockWithCycles
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetBlockByNumberWithCycles(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetForkBlock(ctx context.Context, blockHash types.Hash) (*types.Block, error) {
+	var result *types.Block
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetForkBlock(ctx, blockHash)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetConsensus(ctx context.Context) (*types.Consensus, error) {
+	var result *types.Consensus
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetConsensus(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetBlockMedianTime(ctx context.Context, blockHash types.Hash) (uint64, error) {
+	var result uint64
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetBlockMedianTime(ctx, blockHash)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetFeeRateStatics(ctx context.Context, target interface{}) (*types.FeeRateStatics, error) {
+	var result *types.FeeRateStatics
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetFeeRateStatics(ctx, target)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) DryRunTransaction(ctx context.Context, transaction *types.Transaction) (*types.DryRunTransactionResult, error) {
+	var result *types.DryRunTransactionResult
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.DryRunTransaction(ctx, transaction)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) EstimateCycles(ctx context.Context, transaction *types.Transaction) (*types.EstimateCycles, error) {
+	var result *types.EstimateCycles
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.EstimateCycles(ctx, transaction)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) CalculateDaoMaximumWithdraw(ctx context.Context, point *types.OutPoint, hash types.Hash) (uint64, error) {
+	var result uint64
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.CalculateDaoMaximumWithdraw(ctx, point, hash)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) LocalNodeInfo(ctx context.Context) (*types.LocalNode, error) {
+	var result *types.LocalNode
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.LocalNodeInfo(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetPeers(ctx context.Context) ([]*types.RemoteNode, error) {
+	var result []*types.RemoteNode
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetPeers(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetBannedAddresses(ctx context.Context) ([]*types.BannedAddress, error) {
+	var result []*types.BannedAddress
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetBannedAddresses(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) ClearBannedAddresses(ctx context.Context) error {
+	return c.send(ctx, func(cli Client) error {
+		return cli.ClearBannedAddresses(ctx)
+	})
+}
+
+func (c *FailoverClient) SetBan(ctx context.Context, address string, command string, banTime uint64, absolute bool, reason string) error {
+	return c.send(ctx, func(cli Client) error {
+		return cli.SetBan(ctx, address, command, banTime, absolute, reason)
+	})
+}
+
+func (c *FailoverClient) SyncState(ctx context.Context) (*types.SyncState, error) {
+	var result *types.SyncState
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.SyncState(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) SetNetworkActive(ctx context.Context, state bool) error {
+	return c.send(ctx, func(cli Client) error {
+		return cli.SetNetworkActive(ctx, state)
+	})
+}
+
+func (c *FailoverClient) AddNode(ctx context.Context, peerId, address string) error {
+	return c.send(ctx, func(cli Client) error {
+		return cli.AddNode(ctx, peerId, address)
+	})
+}
+
+func (c *FailoverClient) RemoveNode(ctx context.Context, peerId string) error {
+	return c.send(ctx, func(cli Client) error {
+		return cli.RemoveNode(ctx, peerId)
+	})
+}
+
+func (c *FailoverClient) PingPeers(ctx context.Context) error {
+	return c.send(ctx, func(cli Client) error {
+		return cli.PingPeers(ctx)
+	})
+}
+
+func (c *FailoverClient) SendTransaction(ctx context.Context, tx *types.Transaction) (*types.Hash, error) {
+	var result *types.Hash
+	err := c.send(ctx, func(cli Client) (err error) {
+		result, err = cli.SendTransaction(ctx, tx)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) TxPoolInfo(ctx context.Context) (*types.TxPoolInfo, error) {
+	var result *types.TxPoolInfo
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.TxPoolInfo(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetRawTxPool(ctx context.Context) (*types.RawTxPool, error) {
+	var result *types.RawTxPool
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetRawTxPool(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) ClearTxPool(ctx context.Context) error {
+	return c.send(ctx, func(cli Client) error {
+		return cli.ClearTxPool(ctx)
+	})
+}
+
+func (c *FailoverClient) GetBlockchainInfo(ctx context.Context) (*types.BlockchainInfo, error) {
+	var result *types.BlockchainInfo
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetBlockchainInfo(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) BatchTransactions(ctx context.Context, batch []types.BatchTransactionItem) error {
+	return c.read(ctx, func(cli Client) error {
+		return cli.BatchTransactions(ctx, batch)
+	})
+}
+
+func (c *FailoverClient) BatchLiveCells(ctx context.Context, batch []types.BatchLiveCellItem) error {
+	return c.read(ctx, func(cli Client) error {
+		return cli.BatchLiveCells(ctx, batch)
+	})
+}
+
+func (c *FailoverClient) BatchCall(ctx context.Context, batch *BatchRequest) error {
+	return c.read(ctx, func(cli Client) error {
+		return cli.BatchCall(ctx, batch)
+	})
+}
+
+func (c *FailoverClient) GetCells(ctx context.Context, searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.LiveCells, error) {
+	var result *indexer.LiveCells
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetCells(ctx, searchKey, order, limit, afterCursor)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetTransactions(ctx context.Context, searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.TxsWithCell, error) {
+	var result *indexer.TxsWithCell
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetTransactions(ctx, searchKey, order, limit, afterCursor)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetTransactionsGrouped(ctx context.Context, searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.TxsWithCells, error) {
+	var result *indexer.TxsWithCells
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetTransactionsGrouped(ctx, searchKey, order, limit, afterCursor)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetIndexerTip(ctx context.Context) (*indexer.TipHeader, error) {
+	var result *indexer.TipHeader
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetIndexerTip(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) GetCellsCapacity(ctx context.Context, searchKey *indexer.SearchKey) (*indexer.Capacity, error) {
+	var result *indexer.Capacity
+	err := c.read(ctx, func(cli Client) (err error) {
+		result, err = cli.GetCellsCapacity(ctx, searchKey)
+		return err
+	})
+	return result, err
+}
+
+func (c *FailoverClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return c.send(ctx, func(cli Client) error {
+		return cli.CallContext(ctx, result, method, args...)
+	})
+}
@@ -0,0 +1,193 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/rpc/rpcerror"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// failoverTestNode is a node answering single JSON-RPC calls
+type failoverTestNode struct {
+	mutex  sync.Mutex
+	tip    uint64
+	ibd    bool
+	calls  map[string]int
+	failed map[string]int // number of following calls of method answered by HTTP 500
+	errors map[string]*jsonRpcError
+	server *httptest.Server
+}
+
+func newFailoverTestNode(t *testing.T, tip uint64) *failoverTestNode {
+	n := &failoverTestNode{
+		tip:    tip,
+		calls:  make(map[string]int),
+		failed: make(map[string]int),
+		errors: make(map[string]*jsonRpcError),
+	}
+	n.server = httptest.NewServer(http.HandlerFunc(n.serve))
+	t.Cleanup(n.server.Close)
+	return n
+}
+
+func (n *failoverTestNode) serve(w http.ResponseWriter, r *http.Request) {
+	var msg jsonRpcMessage
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.calls[msg.Method] += 1
+	if n.failed[msg.Method] > 0 {
+		n.failed[msg.Method] -= 1
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+		return
+	}
+	out := &jsonRpcMessage{Version: "2.0", Id: msg.Id, Error: n.errors[msg.Method]}
+	if out.Error == nil {
+		var result interface{}
+		switch msg.Method {
+		case "get_tip_block_number":
+			result = hexutil.Uint64(n.tip)
+		case "sync_state":
+			result = map[string]interface{}{
+				"ibd":                        n.ibd,
+				"best_known_block_number":    hexutil.Uint64(n.tip),
+				"best_known_block_timestamp": "0x0",
+				"orphan_blocks_count":        "0x0",
+				"inflight_blocks_count":      "0x0",
+				"fast_time":                  "0x0",
+				"low_time":                   "0x0",
+				"normal_time":                "0x0",
+			}
+		case "send_transaction":
+			result = types.Hash{1}
+		}
+		out.Result, _ = json.Marshal(result)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (n *failoverTestNode) callCount(method string) int {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.calls[method]
+}
+
+func newFailoverTestClient(t *testing.T, nodes ...*failoverTestNode) *FailoverClient {
+	clients := make([]Client, len(nodes))
+	for i, n := range nodes {
+		cli, err := Dial(n.server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clients[i] = cli
+	}
+	c := NewFailoverClient(clients...)
+	c.RetryBackoff = time.Millisecond
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestFailoverClient_RouteAwayFromLaggingNode(t *testing.T) {
+	lagging := newFailoverTestNode(t, 90)
+	syncing := newFailoverTestNode(t, 100)
+	syncing.ibd = true
+	synced := newFailoverTestNode(t, 100)
+	c := newFailoverTestClient(t, lagging, syncing, synced)
+
+	c.CheckHealth(context.Background())
+	statuses := c.Endpoints()
+	assert.True(t, statuses[0].Lagging)
+	assert.True(t, statuses[1].Lagging)
+	assert.False(t, statuses[2].Lagging)
+	assert.True(t, statuses[2].Healthy)
+
+	tip, err := c.GetTipBlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), tip)
+	assert.Equal(t, 1, lagging.callCount("get_tip_block_number"))
+	assert.Equal(t, 2, synced.callCount("get_tip_block_number"))
+}
+
+func TestFailoverClient_RetryRead(t *testing.T) {
+	down := newFailoverTestNode(t, 100)
+	down.server.Close()
+	flaky := newFailoverTestNode(t, 100)
+	flaky.failed["get_tip_block_number"] = 2
+	c := newFailoverTestClient(t, down, flaky)
+
+	tip, err := c.GetTipBlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), tip)
+	assert.Equal(t, 3, flaky.callCount("get_tip_block_number"))
+	assert.False(t, c.Endpoints()[0].Healthy)
+	assert.Error(t, c.Endpoints()[0].Err)
+
+	flaky.failed["get_tip_block_number"] = 3
+	_, err = c.GetTipBlockNumber(context.Background())
+	assert.Error(t, err)
+}
+
+func TestFailoverClient_NodeErrorNotRetried(t *testing.T) {
+	first := newFailoverTestNode(t, 100)
+	first.errors["get_tip_block_number"] = &jsonRpcError{Code: int(rpcerror.CodeCKBInternalError), Message: "internal"}
+	second := newFailoverTestNode(t, 100)
+	c := newFailoverTestClient(t, first, second)
+
+	_, err := c.GetTipBlockNumber(context.Background())
+	assert.True(t, rpcerror.HasCode(err, rpcerror.CodeCKBInternalError))
+	assert.Equal(t, 0, second.callCount("get_tip_block_number"))
+}
+
+func TestFailoverClient_SendTransaction(t *testing.T) {
+	tx := &types.Transaction{
+		CellDeps:    []*types.CellDep{},
+		HeaderDeps:  []types.Hash{},
+		Inputs:      []*types.CellInput{},
+		Outputs:     []*types.CellOutput{},
+		OutputsData: [][]byte{},
+		Witnesses:   [][]byte{},
+	}
+
+	// the request may have reached node, so it isn't sent again
+	failed := newFailoverTestNode(t, 100)
+	failed.failed["send_transaction"] = 1
+	second := newFailoverTestNode(t, 100)
+	c := newFailoverTestClient(t, failed, second)
+	_, err := c.SendTransaction(context.Background(), tx)
+	assert.Error(t, err)
+	assert.Equal(t, 1, failed.callCount("send_transaction"))
+	assert.Equal(t, 0, second.callCount("send_transaction"))
+
+	// the connection is refused, so the transaction is sent to the next node
+	down := newFailoverTestNode(t, 100)
+	down.server.Close()
+	c = newFailoverTestClient(t, down, second)
+	hash, err := c.SendTransaction(context.Background(), tx)
+	assert.NoError(t, err)
+	assert.Equal(t, types.Hash{1}, *hash)
+	assert.Equal(t, 1, second.callCount("send_transaction"))
+}
+
+func TestFailoverClient_ContextCancelled(t *testing.T) {
+	flaky := newFailoverTestNode(t, 100)
+	flaky.failed["get_tip_block_number"] = 10
+	c := newFailoverTestClient(t, flaky)
+	c.RetryBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.GetTipBlockNumber(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
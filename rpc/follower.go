@@ -0,0 +1,233 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"sync"
+	"time"
+)
+
+const DefaultFollowerWindowSize = 100
+
+// ErrReorgTooDeep is returned when the fork point of a reorg is older than the window of BlockFollower
+var ErrReorgTooDeep = errors.New("reorg is deeper than the window of recent blocks")
+
+// BlockRef identifies an applied block
+type BlockRef struct {
+	Number uint64     `json:"number"`
+	Hash   types.Hash `json:"hash"`
+}
+
+// CheckpointStore persists the recent blocks applied by BlockFollower, so that it resumes and still detects reorgs
+// after restart.
+type CheckpointStore interface {
+	// LoadCheckpoint returns the recent applied blocks in ascending order, or nothing if no checkpoint is saved
+	LoadCheckpoint(ctx context.Context) ([]*BlockRef, error)
+	// SaveCheckpoint saves the recent applied blocks in ascending order
+	SaveCheckpoint(ctx context.Context, blocks []*BlockRef) error
+}
+
+// MemoryCheckpointStore keeps checkpoint in memory, which is lost after restart
+type MemoryCheckpointStore struct {
+	mutex  sync.Mutex
+	blocks []*BlockRef
+}
+
+func (s *MemoryCheckpointStore) LoadCheckpoint(ctx context.Context) ([]*BlockRef, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*BlockRef(nil), s.blocks...), nil
+}
+
+func (s *MemoryCheckpointStore) SaveCheckpoint(ctx context.Context, blocks []*BlockRef) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.blocks = append([]*BlockRef(nil), blocks...)
+	return nil
+}
+
+// BlockHandler handles an applied or orphaned block. Following stops if it returns an error, and the block is handled
+// again when following resumes.
+type BlockHandler func(ctx context.Context, block *types.Block) error
+
+// BlockFollower walks canonical blocks from a start number and follows the tip. When the parent of a new block
+// mismatches the last applied one, it rolls back the orphaned blocks in descending order down to the fork point, and
+// then applies the blocks of the new branch.
+type BlockFollower struct {
+	PollInterval time.Duration
+	// WindowSize is the number of recent blocks kept to find the fork point of reorg
+	WindowSize int
+
+	client     Client
+	store      CheckpointStore
+	start      uint64
+	window     []*BlockRef
+	loaded     bool
+	onApply    []BlockHandler
+	onRollback []BlockHandler
+}
+
+// NewBlockFollower creates a follower starting from block of number start, unless store has a checkpoint to resume
+// from. A MemoryCheckpointStore is used if store is nil.
+func NewBlockFollower(client Client, store CheckpointStore, start uint64) *BlockFollower {
+	if store == nil {
+		store = &MemoryCheckpointStore{}
+	}
+	return &BlockFollower{
+		PollInterval: DefaultPollInterval,
+		WindowSize:   DefaultFollowerWindowSize,
+		client:       client,
+		store:        store,
+		start:        start,
+	}
+}
+
+// OnApply adds a handler called for each canonical block in ascending order
+func (f *BlockFollower) OnApply(handler BlockHandler) {
+	f.onApply = append(f.onApply, handler)
+}
+
+// OnRollback adds a handler called for each orphaned block in descending order
+func (f *BlockFollower) OnRollback(handler BlockHandler) {
+	f.onRollback = append(f.onRollback, handler)
+}
+
+// Checkpoint returns the last applied block, or nil if no block is applied
+func (f *BlockFollower) Checkpoint() *BlockRef {
+	if len(f.window) == 0 {
+		return nil
+	}
+	last := *f.window[len(f.window)-1]
+	return &last
+}
+
+// Run follows the tip every PollInterval until ctx is done or an error occurs. It resumes from the checkpoint when it's
+// called again after an error.
+func (f *BlockFollower) Run(ctx context.Context) error {
+	ticker := time.NewTicker(f.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := f.Follow(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Follow applies blocks up to the current tip, and handles reorgs on the way
+func (f *BlockFollower) Follow(ctx context.Context) error {
+	if !f.loaded {
+		window, err := f.store.LoadCheckpoint(ctx)
+		if err != nil {
+			return err
+		}
+		f.window = window
+		f.loaded = true
+	}
+	tip, err := f.client.GetTipBlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		next := f.start
+		if last := f.Checkpoint(); last != nil {
+			next = last.Number + 1
+		}
+		if next > tip {
+			return nil
+		}
+		block, err := f.client.GetBlockByNumber(ctx, next)
+		if errors.Is(err, NotFound) {
+			// tip has been rolled back
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if last := f.Checkpoint(); last != nil && block.Header.ParentHash != last.Hash {
+			if err := f.rollback(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := f.apply(ctx, block); err != nil {
+			return err
+		}
+	}
+}
+
+func (f *BlockFollower) apply(ctx context.Context, block *types.Block) error {
+	for _, handler := range f.onApply {
+		if err := handler(ctx, block); err != nil {
+			return err
+		}
+	}
+	window := append(f.window, &BlockRef{Number: block.Header.Number, Hash: block.Header.Hash})
+	if f.WindowSize > 0 && len(window) > f.WindowSize {
+		window = window[len(window)-f.WindowSize:]
+	}
+	if err := f.store.SaveCheckpoint(ctx, window); err != nil {
+		return err
+	}
+	f.window = window
+	return nil
+}
+
+// rollback rolls back orphaned blocks until the last applied block is canonical
+func (f *BlockFollower) rollback(ctx context.Context) error {
+	// find the fork point before rolling back anything, so that a too deep reorg leaves checkpoint untouched
+	fork := -1
+	for i := len(f.window) - 1; i >= 0; i-- {
+		hash, err := f.client.GetBlockHash(ctx, f.window[i].Number)
+		if err != nil && !errors.Is(err, NotFound) {
+			return err
+		}
+		if hash != nil && *hash == f.window[i].Hash {
+			fork = i
+			break
+		}
+	}
+	if fork == -1 {
+		return fmt.Errorf("%w: no canonical block in %d recent blocks", ErrReorgTooDeep, len(f.window))
+	}
+	for len(f.window) > fork+1 {
+		orphan := f.window[len(f.window)-1]
+		block, err := f.getOrphanedBlock(ctx, orphan.Hash)
+		if err != nil {
+			return err
+		}
+		for _, handler := range f.onRollback {
+			if err := handler(ctx, block); err != nil {
+				return err
+			}
+		}
+		window := f.window[:len(f.window)-1]
+		if err := f.store.SaveCheckpoint(ctx, window); err != nil {
+			return err
+		}
+		f.window = window
+	}
+	return nil
+}
+
+// getOrphanedBlock gets a block off the canonical chain, which is found by GetBlock if node hasn't switched the chain
+func (f *BlockFollower) getOrphanedBlock(ctx context.Context, hash types.Hash) (*types.Block, error) {
+	block, err := f.client.GetForkBlock(ctx, hash)
+	if err == nil && block != nil {
+		return block, nil
+	}
+	if err != nil && !errors.Is(err, NotFound) {
+		return nil, err
+	}
+	block, err = f.client.GetBlock(ctx, hash)
+	if err == nil && block == nil {
+		return nil, fmt.Errorf("orphaned block %s: %w", hash, NotFound)
+	}
+	return block, err
+}
@@ -0,0 +1,161 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// followerMockClient serves blocks of an in-memory chain, which can switch to another branch
+type followerMockClient struct {
+	Client
+	blocks    map[types.Hash]*types.Block
+	canonical []types.Hash
+}
+
+func newFollowerMockClient() *followerMockClient {
+	c := &followerMockClient{blocks: make(map[types.Hash]*types.Block)}
+	c.extend(0, 0)
+	return c
+}
+
+// extend appends count blocks tagged by branch onto the canonical block at number
+func (c *followerMockClient) extend(number uint64, count int, branch ...byte) {
+	if number == 0 {
+		genesis := &types.Block{Header: &types.Header{Number: 0, Hash: types.Hash{0xff}}}
+		c.blocks[genesis.Header.Hash] = genesis
+		c.canonical = []types.Hash{genesis.Header.Hash}
+		return
+	}
+	c.canonical = c.canonical[:number]
+	parent := c.canonical[number-1]
+	for i := 0; i < count; i++ {
+		n := number + uint64(i)
+		hash := types.Hash{byte(n)}
+		if len(branch) > 0 {
+			hash[1] = branch[0]
+		}
+		block := &types.Block{Header: &types.Header{Number: n, Hash: hash, ParentHash: parent}}
+		c.blocks[hash] = block
+		c.canonical = append(c.canonical, hash)
+		parent = hash
+	}
+}
+
+func (c *followerMockClient) GetTipBlockNumber(ctx context.Context) (uint64, error) {
+	return uint64(len(c.canonical) - 1), nil
+}
+
+func (c *followerMockClient) GetBlockByNumber(ctx context.Context, number uint64) (*types.Block, error) {
+	if number >= uint64(len(c.canonical)) {
+		return nil, NotFound
+	}
+	return c.blocks[c.canonical[number]], nil
+}
+
+func (c *followerMockClient) GetBlockHash(ctx context.Context, number uint64) (*types.Hash, error) {
+	if number >= uint64(len(c.canonical)) {
+		return nil, NotFound
+	}
+	hash := c.canonical[number]
+	return &hash, nil
+}
+
+func (c *followerMockClient) GetForkBlock(ctx context.Context, hash types.Hash) (*types.Block, error) {
+	for _, h := range c.canonical {
+		if h == hash {
+			return nil, nil
+		}
+	}
+	return c.blocks[hash], nil
+}
+
+func (c *followerMockClient) GetBlock(ctx context.Context, hash types.Hash) (*types.Block, error) {
+	return c.blocks[hash], nil
+}
+
+type followerRecorder struct {
+	applied    []types.Hash
+	rolledBack []types.Hash
+}
+
+func (r *followerRecorder) register(f *BlockFollower) {
+	f.OnApply(func(ctx context.Context, block *types.Block) error {
+		r.applied = append(r.applied, block.Header.Hash)
+		return nil
+	})
+	f.OnRollback(func(ctx context.Context, block *types.Block) error {
+		r.rolledBack = append(r.rolledBack, block.Header.Hash)
+		return nil
+	})
+}
+
+func TestBlockFollower(t *testing.T) {
+	client := newFollowerMockClient()
+	client.extend(1, 5)
+	store := &MemoryCheckpointStore{}
+	follower := NewBlockFollower(client, store, 2)
+	recorder := &followerRecorder{}
+	recorder.register(follower)
+	ctx := context.Background()
+
+	assert.NoError(t, follower.Follow(ctx))
+	assert.Equal(t, []types.Hash{{2}, {3}, {4}, {5}}, recorder.applied)
+	assert.Equal(t, &BlockRef{Number: 5, Hash: types.Hash{5}}, follower.Checkpoint())
+
+	// blocks 4 and 5 are replaced by a longer branch
+	client.extend(4, 3, 1)
+	recorder.applied = nil
+	assert.NoError(t, follower.Follow(ctx))
+	assert.Equal(t, []types.Hash{{5}, {4}}, recorder.rolledBack)
+	assert.Equal(t, []types.Hash{{4, 1}, {5, 1}, {6, 1}}, recorder.applied)
+
+	// a new follower resumes from the checkpoint and detects the reorg happened meanwhile
+	client.extend(6, 2, 2)
+	resumed := NewBlockFollower(client, store, 2)
+	recorder = &followerRecorder{}
+	recorder.register(resumed)
+	assert.NoError(t, resumed.Follow(ctx))
+	assert.Equal(t, []types.Hash{{6, 1}}, recorder.rolledBack)
+	assert.Equal(t, []types.Hash{{6, 2}, {7, 2}}, recorder.applied)
+	blocks, err := store.LoadCheckpoint(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), blocks[len(blocks)-1].Number)
+}
+
+func TestBlockFollower_ReorgTooDeep(t *testing.T) {
+	client := newFollowerMockClient()
+	client.extend(1, 5)
+	follower := NewBlockFollower(client, nil, 1)
+	follower.WindowSize = 2
+	assert.NoError(t, follower.Follow(context.Background()))
+
+	client.extend(3, 4, 1)
+	err := follower.Follow(context.Background())
+	assert.True(t, errors.Is(err, ErrReorgTooDeep))
+	assert.Equal(t, &BlockRef{Number: 5, Hash: types.Hash{5}}, follower.Checkpoint())
+}
+
+func TestBlockFollower_HandlerError(t *testing.T) {
+	client := newFollowerMockClient()
+	client.extend(1, 3)
+	follower := NewBlockFollower(client, nil, 1)
+	handlerErr := errors.New("handler error")
+	fail := true
+	var applied []uint64
+	follower.OnApply(func(ctx context.Context, block *types.Block) error {
+		if block.Header.Number == 2 && fail {
+			fail = false
+			return handlerErr
+		}
+		applied = append(applied, block.Header.Number)
+		return nil
+	})
+	assert.Equal(t, handlerErr, follower.Follow(context.Background()))
+	assert.Equal(t, uint64(1), follower.Checkpoint().Number)
+	// the failed block is applied again
+	assert.NoError(t, follower.Follow(context.Background()))
+	assert.Equal(t, []uint64{1, 2, 3}, applied)
+}
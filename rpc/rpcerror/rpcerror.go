@@ -0,0 +1,247 @@
+// Package rpcerror maps errors returned by CKB JSON-RPC into typed errors. All of them unwrap to *RPCError, so that
+// both errors.As(err, &*RPCError) and errors.As(err, &*TransactionFailedToResolveError) work.
+package rpcerror
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"reflect"
+	"regexp"
+	"strconv"
+)
+
+type Code int
+
+// Error codes of CKB JSON-RPC
+const (
+	CodeCKBInternalError                                Code = -1
+	CodeDeprecated                                      Code = -2
+	CodeInvalid                                         Code = -3
+	CodeRPCModuleIsDisabled                             Code = -4
+	CodeDaoError                                        Code = -5
+	CodeIntegerOverflow                                 Code = -6
+	CodeConfigError                                     Code = -7
+	CodeP2PFailedToBroadcast                            Code = -101
+	CodeDatabaseError                                   Code = -200
+	CodeChainIndexIsInconsistent                        Code = -201
+	CodeDatabaseIsCorrupt                               Code = -202
+	CodeTransactionFailedToResolve                      Code = -301
+	CodeTransactionFailedToVerify                       Code = -302
+	CodeAlertFailedToVerifySignatures                   Code = -1000
+	CodePoolRejectedTransactionByOutputsValidator       Code = -1102
+	CodePoolRejectedTransactionByIllTransactionChecker  Code = -1103
+	CodePoolRejectedTransactionByMinFeeRate             Code = -1104
+	CodePoolRejectedTransactionByMaxAncestorsCountLimit Code = -1105
+	CodePoolIsFull                                      Code = -1106
+	CodePoolRejectedDuplicatedTransaction               Code = -1107
+	CodePoolRejectedMalformedTransaction                Code = -1108
+	CodeTransactionExpired                              Code = -1109
+	CodePoolRejectedTransactionBySizeLimit              Code = -1110
+	CodePoolRejectedRBF                                 Code = -1111
+	CodePoolRejectedInvalidated                         Code = -1112
+	CodeIndexer                                         Code = -1200
+)
+
+// RPCError is an error returned by node. Data is the debug representation of the error, if node returns it.
+type RPCError struct {
+	Code    Code
+	Message string
+	Data    string
+}
+
+func (e *RPCError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("json-rpc error %d", e.Code)
+	}
+	return e.Message
+}
+
+// text is searched for details of the error
+func (e *RPCError) text() string {
+	return e.Message + " " + e.Data
+}
+
+// TransactionFailedToResolveError is returned when an input or cell dep is dead or unknown
+type TransactionFailedToResolveError struct {
+	*RPCError
+	// Reason is Dead or Unknown
+	Reason   string
+	OutPoint *types.OutPoint
+}
+
+func (e *TransactionFailedToResolveError) Unwrap() error {
+	return e.RPCError
+}
+
+// TransactionFailedToVerifyError is returned when scripts fail or exceed the cycles limit
+type TransactionFailedToVerifyError struct {
+	*RPCError
+	// ScriptType is lock or type of the failing script group, or empty if it's unknown
+	ScriptType types.ScriptType
+	// Outputs is true if the failing script group is located by outputs, otherwise it's by inputs
+	Outputs bool
+	// Index is the first input or output index of the failing script group, or -1 if it's unknown
+	Index int
+	// ExitCode is the error code returned by script
+	ExitCode int
+	// Cycles is the cycles limit exceeded by scripts, or 0 if the limit is not exceeded
+	Cycles uint64
+}
+
+func (e *TransactionFailedToVerifyError) Unwrap() error {
+	return e.RPCError
+}
+
+// PoolRejectedDuplicatedTransactionError is returned when transaction is already in pool
+type PoolRejectedDuplicatedTransactionError struct {
+	*RPCError
+	TxHash types.Hash
+}
+
+func (e *PoolRejectedDuplicatedTransactionError) Unwrap() error {
+	return e.RPCError
+}
+
+// PoolRejectedTransactionByMinFeeRateError is returned when transaction fee is less than the minimum of pool
+type PoolRejectedTransactionByMinFeeRateError struct {
+	*RPCError
+	MinFee uint64
+	Fee    uint64
+}
+
+func (e *PoolRejectedTransactionByMinFeeRateError) Unwrap() error {
+	return e.RPCError
+}
+
+// PoolRejectedRBFError is returned when transaction fee isn't enough to replace conflicting transactions in pool
+type PoolRejectedRBFError struct {
+	*RPCError
+	Fee           uint64
+	MinReplaceFee uint64
+}
+
+func (e *PoolRejectedRBFError) Unwrap() error {
+	return e.RPCError
+}
+
+var (
+	outPointRegexp      = regexp.MustCompile(`(Dead|Unknown)\(OutPoint\((0x[0-9a-fA-F]{72})\)`)
+	txHashRegexp        = regexp.MustCompile(`Byte32\((0x[0-9a-fA-F]{64})\)`)
+	minFeeRegexp        = regexp.MustCompile(`should be (\d+) shannons at least, but only got (\d+)`)
+	rbfFeeRegexp        = regexp.MustCompile(`current fee is (\d+), expect it to >= (\d+)`)
+	scriptSourceRegexp  = regexp.MustCompile(`source: (Inputs|Outputs)\[(\d+)\]\.(Lock|Type)`)
+	scriptExitRegexp    = regexp.MustCompile(`ValidationFailure(?:: see error code |\()(-?\d+)`)
+	exceededCycleRegexp = regexp.MustCompile(`ExceededMaximumCycles: expect cycles <= (\d+)`)
+)
+
+// FromError maps JSON-RPC error into a typed error. Other errors, e.g. transport errors, are returned as they are.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var coded interface {
+		ErrorCode() int
+	}
+	if !errors.As(err, &coded) {
+		return err
+	}
+	e := &RPCError{
+		Code:    Code(coded.ErrorCode()),
+		Message: err.Error(),
+		Data:    errorData(coded),
+	}
+	switch e.Code {
+	case CodeTransactionFailedToResolve:
+		r := &TransactionFailedToResolveError{RPCError: e}
+		if m := outPointRegexp.FindStringSubmatch(e.text()); m != nil {
+			r.Reason = m[1]
+			r.OutPoint = parseOutPoint(m[2])
+		}
+		return r
+	case CodeTransactionFailedToVerify:
+		r := &TransactionFailedToVerifyError{RPCError: e, Index: -1}
+		text := e.text()
+		if m := scriptSourceRegexp.FindStringSubmatch(text); m != nil {
+			r.Outputs = m[1] == "Outputs"
+			r.Index, _ = strconv.Atoi(m[2])
+			if m[3] == "Lock" {
+				r.ScriptType = types.ScriptTypeLock
+			} else {
+				r.ScriptType = types.ScriptTypeType
+			}
+		}
+		if m := scriptExitRegexp.FindStringSubmatch(text); m != nil {
+			r.ExitCode, _ = strconv.Atoi(m[1])
+		}
+		if m := exceededCycleRegexp.FindStringSubmatch(text); m != nil {
+			r.Cycles, _ = strconv.ParseUint(m[1], 10, 64)
+		}
+		return r
+	case CodePoolRejectedDuplicatedTransaction:
+		r := &PoolRejectedDuplicatedTransactionError{RPCError: e}
+		if m := txHashRegexp.FindStringSubmatch(e.text()); m != nil {
+			r.TxHash = types.HexToHash(m[1])
+		}
+		return r
+	case CodePoolRejectedTransactionByMinFeeRate:
+		r := &PoolRejectedTransactionByMinFeeRateError{RPCError: e}
+		if m := minFeeRegexp.FindStringSubmatch(e.text()); m != nil {
+			r.MinFee, _ = strconv.ParseUint(m[1], 10, 64)
+			r.Fee, _ = strconv.ParseUint(m[2], 10, 64)
+		}
+		return r
+	case CodePoolRejectedRBF:
+		r := &PoolRejectedRBFError{RPCError: e}
+		if m := rbfFeeRegexp.FindStringSubmatch(e.text()); m != nil {
+			r.Fee, _ = strconv.ParseUint(m[1], 10, 64)
+			r.MinReplaceFee, _ = strconv.ParseUint(m[2], 10, 64)
+		}
+		return r
+	default:
+		return e
+	}
+}
+
+// HasCode returns true if err is an RPCError of code
+func HasCode(err error, code Code) bool {
+	var e *RPCError
+	return errors.As(err, &e) && e.Code == code
+}
+
+// errorData reads the data of JSON-RPC error, which go-ethereum doesn't expose
+func errorData(err interface{}) string {
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	field := v.FieldByName("Data")
+	if !field.IsValid() || !field.CanInterface() {
+		return ""
+	}
+	switch data := field.Interface().(type) {
+	case nil:
+		return ""
+	case string:
+		return data
+	default:
+		return fmt.Sprint(data)
+	}
+}
+
+// parseOutPoint parses the debug representation of out point, which is tx hash followed by index in little endian
+func parseOutPoint(s string) *types.OutPoint {
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil
+	}
+	return &types.OutPoint{
+		TxHash: types.BytesToHash(b[:32]),
+		Index:  binary.LittleEndian.Uint32(b[32:]),
+	}
+}
@@ -0,0 +1,119 @@
+package rpcerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type codedError struct {
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+func (e *codedError) Error() string {
+	return e.Message
+}
+
+func (e *codedError) ErrorCode() int {
+	return e.Code
+}
+
+func TestFromError(t *testing.T) {
+	assert.Nil(t, FromError(nil))
+	plain := errors.New("connection refused")
+	assert.Equal(t, plain, FromError(plain))
+
+	err := FromError(&codedError{
+		Code:    -301,
+		Message: "TransactionFailedToResolve: Resolve failed Dead(OutPoint(0xf8de3bb47d055cdf460d93a2a6e1b05f7432f9777c8c474abf4eec1d4aee5d3701000000))",
+		Data:    "Resolve(Dead(OutPoint(0xf8de3bb47d055cdf460d93a2a6e1b05f7432f9777c8c474abf4eec1d4aee5d3701000000)))",
+	})
+	var resolveErr *TransactionFailedToResolveError
+	assert.True(t, errors.As(err, &resolveErr))
+	assert.Equal(t, "Dead", resolveErr.Reason)
+	assert.Equal(t, &types.OutPoint{
+		TxHash: types.HexToHash("0xf8de3bb47d055cdf460d93a2a6e1b05f7432f9777c8c474abf4eec1d4aee5d37"),
+		Index:  1,
+	}, resolveErr.OutPoint)
+	var rpcErr *RPCError
+	assert.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, CodeTransactionFailedToResolve, rpcErr.Code)
+	assert.True(t, HasCode(fmt.Errorf("send: %w", err), CodeTransactionFailedToResolve))
+
+	err = FromError(&codedError{
+		Code:    -302,
+		Message: "TransactionFailedToVerify: Verification failed Script(TransactionScriptError { source: Outputs[2].Type, cause: ValidationFailure: see error code -31 on page https://nervosnetwork.github.io/ckb-script-error-codes/by-type-hash/0x00.html#-31 })",
+	})
+	var verifyErr *TransactionFailedToVerifyError
+	assert.True(t, errors.As(err, &verifyErr))
+	assert.True(t, verifyErr.Outputs)
+	assert.Equal(t, 2, verifyErr.Index)
+	assert.Equal(t, types.ScriptTypeType, verifyErr.ScriptType)
+	assert.Equal(t, -31, verifyErr.ExitCode)
+	assert.Equal(t, uint64(0), verifyErr.Cycles)
+
+	err = FromError(&codedError{
+		Code:    -302,
+		Message: "TransactionFailedToVerify: Verification failed Script(TransactionScriptError { source: Inputs[0].Lock, cause: ExceededMaximumCycles: expect cycles <= 70000000 })",
+	})
+	assert.True(t, errors.As(err, &verifyErr))
+	assert.False(t, verifyErr.Outputs)
+	assert.Equal(t, types.ScriptTypeLock, verifyErr.ScriptType)
+	assert.Equal(t, uint64(70000000), verifyErr.Cycles)
+
+	err = FromError(&codedError{
+		Code:    -1107,
+		Message: "PoolRejectedDuplicatedTransaction: Transaction(Byte32(0xa0ef4eb5f4ceeb08a4c8524d84c5da95dce2f608e0ca2ec8091191b0f330c6e3)) already exists in transaction_pool",
+	})
+	var duplicatedErr *PoolRejectedDuplicatedTransactionError
+	assert.True(t, errors.As(err, &duplicatedErr))
+	assert.Equal(t, types.HexToHash("0xa0ef4eb5f4ceeb08a4c8524d84c5da95dce2f608e0ca2ec8091191b0f330c6e3"), duplicatedErr.TxHash)
+
+	err = FromError(&codedError{
+		Code:    -1104,
+		Message: "PoolRejectedTransactionByMinFeeRate: The min fee rate is 1000 shannons/KW, so the transaction fee should be 242 shannons at least, but only got 0",
+	})
+	var feeErr *PoolRejectedTransactionByMinFeeRateError
+	assert.True(t, errors.As(err, &feeErr))
+	assert.Equal(t, uint64(242), feeErr.MinFee)
+	assert.Equal(t, uint64(0), feeErr.Fee)
+
+	err = FromError(&codedError{
+		Code:    -1111,
+		Message: "PoolRejectedRBF: RBF rejected: Tx's current fee is 1000, expect it to >= 2000 to replace old txs",
+	})
+	var rbfErr *PoolRejectedRBFError
+	assert.True(t, errors.As(err, &rbfErr))
+	assert.Equal(t, uint64(1000), rbfErr.Fee)
+	assert.Equal(t, uint64(2000), rbfErr.MinReplaceFee)
+
+	err = FromError(&codedError{Code: -1106, Message: "PoolIsFull: Transaction pool exceeded maximum size limit"})
+	assert.True(t, HasCode(err, CodePoolIsFull))
+	assert.False(t, errors.As(err, &rbfErr))
+}
+
+func TestFromErrorOfClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-301,"message":"TransactionFailedToResolve: Resolve failed Unknown","data":"Resolve(Unknown(OutPoint(0xf8de3bb47d055cdf460d93a2a6e1b05f7432f9777c8c474abf4eec1d4aee5d3702000000)))"}}`))
+	}))
+	defer server.Close()
+	c, err := rpc.DialHTTP(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = FromError(c.CallContext(context.Background(), nil, "send_transaction"))
+	var resolveErr *TransactionFailedToResolveError
+	assert.True(t, errors.As(err, &resolveErr))
+	// data is read from go-ethereum error
+	assert.Equal(t, "Unknown", resolveErr.Reason)
+	assert.Equal(t, uint32(2), resolveErr.OutPoint.Index)
+}
@@ -0,0 +1,539 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Topics of subscribe RPC
+const (
+	TopicNewTipHeader        = "new_tip_header"
+	TopicNewTipBlock         = "new_tip_block"
+	TopicNewTransaction      = "new_transaction"
+	TopicProposedTransaction = "proposed_transaction"
+	TopicRejectedTransaction = "rejected_transaction"
+)
+
+const (
+	DefaultReconnectInterval   = 3 * time.Second
+	subscriptionBufferSize     = 64
+	subscriptionNotifyMethod   = "subscribe"
+	subscriptionCallTimeout    = 10 * time.Second
+	subscriptionJsonRpcVersion = "2.0"
+)
+
+var ErrSubscriptionClosed = errors.New("subscription client is closed")
+var ErrConnectionLost = errors.New("connection to node is lost")
+
+// subscriptionConn is a connection sending and receiving JSON-RPC messages, over WebSocket or TCP
+type subscriptionConn interface {
+	WriteJSON(v interface{}) error
+	ReadJSON(v interface{}) error
+	Close() error
+}
+
+// tcpConn sends and receives newline delimited JSON messages
+type tcpConn struct {
+	conn    net.Conn
+	encoder *json.Encoder
+	decoder *json.Decoder
+}
+
+func (c *tcpConn) WriteJSON(v interface{}) error {
+	return c.encoder.Encode(v)
+}
+
+func (c *tcpConn) ReadJSON(v interface{}) error {
+	return c.decoder.Decode(v)
+}
+
+func (c *tcpConn) Close() error {
+	return c.conn.Close()
+}
+
+func dialSubscriptionConn(ctx context.Context, rawUrl string) (subscriptionConn, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "ws", "wss":
+		conn, _, err := websocket.DefaultDialer.DialContext(ctx, rawUrl, nil)
+		if err != nil {
+			return nil, err
+		}
+		return conn, nil
+	case "tcp":
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", u.Host)
+		if err != nil {
+			return nil, err
+		}
+		return &tcpConn{conn: conn, encoder: json.NewEncoder(conn), decoder: json.NewDecoder(conn)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported subscription url scheme %s", u.Scheme)
+	}
+}
+
+type jsonRpcRequest struct {
+	Version string        `json:"jsonrpc"`
+	Id      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type jsonRpcResponse struct {
+	Id     *uint64         `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type subscriptionNotification struct {
+	Result       json.RawMessage `json:"result"`
+	Subscription json.RawMessage `json:"subscription"`
+}
+
+type pendingCall struct {
+	done chan error
+	// onResult runs in the read loop before following messages are read
+	onResult func(result json.RawMessage) error
+}
+
+// SubscriptionClient subscribes topics of CKB node over WebSocket (ws:// or wss://) or TCP (tcp://). When the
+// connection is lost, it reconnects every ReconnectInterval and subscribes the topics again. Messages published during
+// reconnection are missed, which is signaled by Subscription.Resubscribed. Channels of subscriptions are buffered, and
+// a consumer falling behind blocks the others.
+type SubscriptionClient struct {
+	ReconnectInterval time.Duration
+
+	url    string
+	mutex  sync.Mutex
+	conn   subscriptionConn
+	nextId uint64
+	// pending calls by request id
+	pending map[uint64]*pendingCall
+	// subscriptions by the id returned by node
+	subscriptions map[string]*Subscription
+	// all active subscriptions, including those being subscribed, which are subscribed again after reconnection once
+	// node has returned their ids
+	active map[*Subscription]struct{}
+	closed chan struct{}
+}
+
+func DialSubscription(url string) (*SubscriptionClient, error) {
+	return DialSubscriptionContext(context.Background(), url)
+}
+
+func DialSubscriptionContext(ctx context.Context, url string) (*SubscriptionClient, error) {
+	conn, err := dialSubscriptionConn(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	c := &SubscriptionClient{
+		ReconnectInterval: DefaultReconnectInterval,
+		url:               url,
+		conn:              conn,
+		pending:           make(map[uint64]*pendingCall),
+		subscriptions:     make(map[string]*Subscription),
+		active:            make(map[*Subscription]struct{}),
+		closed:            make(chan struct{}),
+	}
+	go c.run(conn)
+	return c, nil
+}
+
+// Subscription is a subscribed topic. Its channel is closed after Unsubscribe or the client is closed.
+type Subscription struct {
+	topic   string
+	client  *SubscriptionClient
+	deliver func(data []byte, quit <-chan struct{})
+	onClose func()
+
+	mutex        sync.Mutex
+	id           string
+	isClosed     bool
+	quit         chan struct{}
+	once         sync.Once
+	resubscribed chan struct{}
+}
+
+func (s *Subscription) Topic() string {
+	return s.topic
+}
+
+// Resubscribed receives a signal each time the topic is subscribed again after reconnection. Messages published while
+// the connection was lost are missed, e.g. consumers of new tip headers should fetch the skipped blocks. Signals not
+// received yet are merged into one.
+func (s *Subscription) Resubscribed() <-chan struct{} {
+	return s.resubscribed
+}
+
+// Unsubscribe stops the subscription and closes its channel
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		close(s.quit)
+		c := s.client
+		c.mutex.Lock()
+		delete(c.active, s)
+		id := s.getId()
+		if id != "" {
+			delete(c.subscriptions, id)
+		}
+		c.mutex.Unlock()
+		if id != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), subscriptionCallTimeout)
+			// it's fine to fail as the subscription is dropped with the connection
+			_ = c.call(ctx, nil, "unsubscribe", json.RawMessage(id))
+			cancel()
+		}
+		s.mutex.Lock()
+		s.isClosed = true
+		s.onClose()
+		s.mutex.Unlock()
+	})
+}
+
+func (s *Subscription) getId() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.id
+}
+
+func (s *Subscription) setId(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.id = id
+}
+
+func (s *Subscription) notify(data []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.isClosed {
+		s.deliver(data, s.quit)
+	}
+}
+
+// SubscribeNewTipHeader subscribes headers of new tip blocks
+func (c *SubscriptionClient) SubscribeNewTipHeader(ctx context.Context) (<-chan *types.Header, *Subscription, error) {
+	ch := make(chan *types.Header, subscriptionBufferSize)
+	sub, err := c.subscribe(ctx, TopicNewTipHeader, func(data []byte, quit <-chan struct{}) {
+		var header types.Header
+		if err := json.Unmarshal(data, &header); err == nil {
+			select {
+			case ch <- &header:
+			case <-quit:
+			}
+		}
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, sub, nil
+}
+
+// SubscribeNewTipBlock subscribes new tip blocks
+func (c *SubscriptionClient) SubscribeNewTipBlock(ctx context.Context) (<-chan *types.Block, *Subscription, error) {
+	ch := make(chan *types.Block, subscriptionBufferSize)
+	sub, err := c.subscribe(ctx, TopicNewTipBlock, func(data []byte, quit <-chan struct{}) {
+		var block types.Block
+		if err := json.Unmarshal(data, &block); err == nil {
+			select {
+			case ch <- &block:
+			case <-quit:
+			}
+		}
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, sub, nil
+}
+
+// SubscribeNewTransaction subscribes transactions entering the pool
+func (c *SubscriptionClient) SubscribeNewTransaction(ctx context.Context) (<-chan *types.PoolTransactionEntry, *Subscription, error) {
+	return c.subscribePoolTransaction(ctx, TopicNewTransaction)
+}
+
+// SubscribeProposedTransaction subscribes transactions proposed in the pool
+func (c *SubscriptionClient) SubscribeProposedTransaction(ctx context.Context) (<-chan *types.PoolTransactionEntry, *Subscription, error) {
+	return c.subscribePoolTransaction(ctx, TopicProposedTransaction)
+}
+
+// SubscribeRejectedTransaction subscribes transactions rejected by the pool
+func (c *SubscriptionClient) SubscribeRejectedTransaction(ctx context.Context) (<-chan *types.PoolTransactionRejection, *Subscription, error) {
+	ch := make(chan *types.PoolTransactionRejection, subscriptionBufferSize)
+	sub, err := c.subscribe(ctx, TopicRejectedTransaction, func(data []byte, quit <-chan struct{}) {
+		var rejection types.PoolTransactionRejection
+		if err := json.Unmarshal(data, &rejection); err == nil {
+			select {
+			case ch <- &rejection:
+			case <-quit:
+			}
+		}
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, sub, nil
+}
+
+func (c *SubscriptionClient) subscribePoolTransaction(ctx context.Context, topic string) (<-chan *types.PoolTransactionEntry, *Subscription, error) {
+	ch := make(chan *types.PoolTransactionEntry, subscriptionBufferSize)
+	sub, err := c.subscribe(ctx, topic, func(data []byte, quit <-chan struct{}) {
+		var entry types.PoolTransactionEntry
+		if err := json.Unmarshal(data, &entry); err == nil {
+			select {
+			case ch <- &entry:
+			case <-quit:
+			}
+		}
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, sub, nil
+}
+
+func (c *SubscriptionClient) subscribe(ctx context.Context, topic string, deliver func(data []byte, quit <-chan struct{}), onClose func()) (*Subscription, error) {
+	sub := &Subscription{
+		topic:        topic,
+		client:       c,
+		deliver:      deliver,
+		onClose:      onClose,
+		quit:         make(chan struct{}),
+		resubscribed: make(chan struct{}, 1),
+	}
+	// sub is active before the call, so that it's subscribed again if connection is lost right after node replies
+	c.mutex.Lock()
+	select {
+	case <-c.closed:
+		c.mutex.Unlock()
+		return nil, ErrSubscriptionClosed
+	default:
+	}
+	c.active[sub] = struct{}{}
+	c.mutex.Unlock()
+	if err := c.call(ctx, c.registerSubscription(sub), "subscribe", topic); err != nil {
+		sub.Unsubscribe()
+		return nil, err
+	}
+	select {
+	case <-c.closed:
+		return nil, ErrSubscriptionClosed
+	default:
+	}
+	return sub, nil
+}
+
+// registerSubscription returns the callback to map subscription id to sub, so that following notifications are
+// delivered to it
+func (c *SubscriptionClient) registerSubscription(sub *Subscription) func(result json.RawMessage) error {
+	return func(result json.RawMessage) error {
+		id := string(result)
+		sub.setId(id)
+		c.mutex.Lock()
+		if _, ok := c.active[sub]; ok {
+			c.subscriptions[id] = sub
+		}
+		c.mutex.Unlock()
+		return nil
+	}
+}
+
+func (c *SubscriptionClient) call(ctx context.Context, onResult func(result json.RawMessage) error, method string, params ...interface{}) error {
+	c.mutex.Lock()
+	if c.conn == nil {
+		c.mutex.Unlock()
+		select {
+		case <-c.closed:
+			return ErrSubscriptionClosed
+		default:
+			return ErrConnectionLost
+		}
+	}
+	id := c.nextId
+	c.nextId += 1
+	call := &pendingCall{done: make(chan error, 1), onResult: onResult}
+	c.pending[id] = call
+	err := c.conn.WriteJSON(&jsonRpcRequest{
+		Version: subscriptionJsonRpcVersion,
+		Id:      id,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		delete(c.pending, id)
+	}
+	c.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case err := <-call.done:
+		return err
+	case <-ctx.Done():
+		c.mutex.Lock()
+		delete(c.pending, id)
+		c.mutex.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (c *SubscriptionClient) run(conn subscriptionConn) {
+	for {
+		c.read(conn)
+		c.mutex.Lock()
+		c.conn = nil
+		for id, call := range c.pending {
+			call.done <- ErrConnectionLost
+			delete(c.pending, id)
+		}
+		c.subscriptions = make(map[string]*Subscription)
+		c.mutex.Unlock()
+
+		if conn = c.reconnect(); conn == nil {
+			return
+		}
+		go c.resubscribe()
+	}
+}
+
+func (c *SubscriptionClient) read(conn subscriptionConn) {
+	for {
+		var msg jsonRpcResponse
+		if err := conn.ReadJSON(&msg); err != nil {
+			conn.Close()
+			return
+		}
+		if msg.Id == nil {
+			if msg.Method == subscriptionNotifyMethod {
+				c.dispatch(msg.Params)
+			}
+			continue
+		}
+		c.mutex.Lock()
+		call := c.pending[*msg.Id]
+		delete(c.pending, *msg.Id)
+		c.mutex.Unlock()
+		if call == nil {
+			continue
+		}
+		if msg.Error != nil {
+			call.done <- fmt.Errorf("%s (code %d)", msg.Error.Message, msg.Error.Code)
+		} else if call.onResult != nil {
+			call.done <- call.onResult(msg.Result)
+		} else {
+			call.done <- nil
+		}
+	}
+}
+
+func (c *SubscriptionClient) dispatch(params json.RawMessage) {
+	var notification subscriptionNotification
+	if err := json.Unmarshal(params, &notification); err != nil {
+		return
+	}
+	c.mutex.Lock()
+	sub := c.subscriptions[string(notification.Subscription)]
+	c.mutex.Unlock()
+	if sub == nil {
+		return
+	}
+	// node publishes result as a JSON string
+	data := []byte(notification.Result)
+	var s string
+	if err := json.Unmarshal(notification.Result, &s); err == nil {
+		data = []byte(s)
+	}
+	sub.notify(data)
+}
+
+// reconnect dials node until it succeeds, or returns nil if the client is closed
+func (c *SubscriptionClient) reconnect() subscriptionConn {
+	for {
+		select {
+		case <-c.closed:
+			return nil
+		case <-time.After(c.ReconnectInterval):
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), subscriptionCallTimeout)
+		conn, err := dialSubscriptionConn(ctx, c.url)
+		cancel()
+		if err != nil {
+			continue
+		}
+		c.mutex.Lock()
+		select {
+		case <-c.closed:
+			c.mutex.Unlock()
+			conn.Close()
+			return nil
+		default:
+		}
+		c.conn = conn
+		c.mutex.Unlock()
+		return conn
+	}
+}
+
+func (c *SubscriptionClient) resubscribe() {
+	c.mutex.Lock()
+	subs := make([]*Subscription, 0, len(c.active))
+	for sub := range c.active {
+		// subscriptions without id are still being subscribed, and their calls fail with the lost connection
+		if sub.getId() != "" {
+			subs = append(subs, sub)
+		}
+	}
+	c.mutex.Unlock()
+	for _, sub := range subs {
+		ctx, cancel := context.WithTimeout(context.Background(), subscriptionCallTimeout)
+		// failures are retried after the next reconnection
+		err := c.call(ctx, c.registerSubscription(sub), "subscribe", sub.topic)
+		cancel()
+		if err == nil {
+			select {
+			case sub.resubscribed <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Close closes the connection and all subscriptions
+func (c *SubscriptionClient) Close() {
+	c.mutex.Lock()
+	select {
+	case <-c.closed:
+		c.mutex.Unlock()
+		return
+	default:
+	}
+	close(c.closed)
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+	subs := make([]*Subscription, 0, len(c.active))
+	for sub := range c.active {
+		subs = append(subs, sub)
+	}
+	c.mutex.Unlock()
+	for _, sub := range subs {
+		sub.Unsubscribe()
+	}
+}
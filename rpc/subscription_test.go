@@ -0,0 +1,218 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSubscriptionServer accepts subscribe calls, and publishes messages to subscribers of topics
+type fakeSubscriptionServer struct {
+	mutex  sync.Mutex
+	nextId int
+	conns  []subscriptionConn
+	// topic of subscription id, by connection
+	topics map[subscriptionConn]map[string]string
+	// subscribed is notified after each subscribe call
+	subscribed chan string
+	// dropAfterSubscribe closes the connection right after replying the next subscribe call
+	dropAfterSubscribe bool
+}
+
+func newFakeSubscriptionServer() *fakeSubscriptionServer {
+	return &fakeSubscriptionServer{
+		topics:     make(map[subscriptionConn]map[string]string),
+		subscribed: make(chan string, 16),
+	}
+}
+
+func (s *fakeSubscriptionServer) serve(conn subscriptionConn) {
+	s.mutex.Lock()
+	s.conns = append(s.conns, conn)
+	s.topics[conn] = make(map[string]string)
+	s.mutex.Unlock()
+	for {
+		var req struct {
+			Id     uint64   `json:"id"`
+			Method string   `json:"method"`
+			Params []string `json:"params"`
+		}
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		s.mutex.Lock()
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.Id}
+		switch req.Method {
+		case "subscribe":
+			id := fmt.Sprintf("0x%x", s.nextId)
+			s.nextId += 1
+			s.topics[conn][id] = req.Params[0]
+			resp["result"] = id
+		case "unsubscribe":
+			delete(s.topics[conn], req.Params[0])
+			resp["result"] = true
+		}
+		_ = conn.WriteJSON(resp)
+		drop := req.Method == "subscribe" && s.dropAfterSubscribe
+		if drop {
+			s.dropAfterSubscribe = false
+			delete(s.topics, conn)
+		}
+		s.mutex.Unlock()
+		if req.Method == "subscribe" {
+			s.subscribed <- req.Params[0]
+		}
+		if drop {
+			conn.Close()
+			return
+		}
+	}
+}
+
+func (s *fakeSubscriptionServer) publish(topic string, result string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for conn, topics := range s.topics {
+		for id, t := range topics {
+			if t == topic {
+				_ = conn.WriteJSON(map[string]interface{}{
+					"jsonrpc": "2.0",
+					"method":  "subscribe",
+					"params":  map[string]interface{}{"result": result, "subscription": id},
+				})
+			}
+		}
+	}
+}
+
+// dropConnections closes connections to make clients reconnect
+func (s *fakeSubscriptionServer) dropConnections() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+		delete(s.topics, conn)
+	}
+	s.conns = nil
+}
+
+func (s *fakeSubscriptionServer) waitSubscribed(t *testing.T, topics ...string) {
+	var got []string
+	for range topics {
+		select {
+		case topic := <-s.subscribed:
+			got = append(got, topic)
+		case <-time.After(5 * time.Second):
+			t.Fatal("subscription timeout")
+		}
+	}
+	assert.ElementsMatch(t, topics, got)
+}
+
+func startTcpSubscriptionServer(t *testing.T, server *fakeSubscriptionServer) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(&tcpConn{conn: conn, encoder: json.NewEncoder(conn), decoder: json.NewDecoder(conn)})
+		}
+	}()
+	return "tcp://" + listener.Addr().String()
+}
+
+func startWebSocketSubscriptionServer(t *testing.T, server *fakeSubscriptionServer) string {
+	upgrader := websocket.Upgrader{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		server.serve(conn)
+	}))
+	t.Cleanup(s.Close)
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+const testHeaderJson = `{"compact_target":"0x1e083126","dao":"0xb5a3e047474401001bc476b9ee573000c0c387962a38000000febffacf030107","epoch":"0x7080018000001","extra_hash":"0x0000000000000000000000000000000000000000000000000000000000000000","hash":"0xa5f5c85987a15de25661e5a214f2c1449cd803f071acc7999820f25246471f40","nonce":"0x78b105de64fc38a200000004139b0200","number":"0x400","parent_hash":"0xae003585fa15309b30b31aed3dcf385e9472c3c3e93746a6c4540629a6a1ed2d","proposals_hash":"0x0000000000000000000000000000000000000000000000000000000000000000","timestamp":"0x5cd2b117","transactions_root":"0xc47d5b78b3c4c4c853e2a32810818940d0ee403423bea9ec7b8e566d9595206c","version":"0x0"}`
+
+func TestSubscriptionClient(t *testing.T) {
+	for _, start := range []func(*testing.T, *fakeSubscriptionServer) string{startTcpSubscriptionServer, startWebSocketSubscriptionServer} {
+		server := newFakeSubscriptionServer()
+		client, err := DialSubscription(start(t, server))
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.ReconnectInterval = 10 * time.Millisecond
+
+		headers, sub, err := client.SubscribeNewTipHeader(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, TopicNewTipHeader, sub.Topic())
+		server.waitSubscribed(t, TopicNewTipHeader)
+		rejections, _, err := client.SubscribeRejectedTransaction(context.Background())
+		assert.NoError(t, err)
+		server.waitSubscribed(t, TopicRejectedTransaction)
+
+		server.publish(TopicNewTipHeader, testHeaderJson)
+		header := <-headers
+		assert.Equal(t, uint64(0x400), header.Number)
+
+		server.publish(TopicRejectedTransaction, `[{"transaction":null,"cycles":"0x10","size":"0x20","fee":"0x30","timestamp":"0x40"},{"type":"LowFeeRate","description":"fee rate too low"}]`)
+		rejection := <-rejections
+		assert.Equal(t, uint64(0x30), rejection.Entry.Fee)
+		assert.Equal(t, "LowFeeRate", rejection.Reject.Type)
+
+		// topics are subscribed again after reconnection
+		server.dropConnections()
+		server.waitSubscribed(t, TopicNewTipHeader, TopicRejectedTransaction)
+		server.publish(TopicNewTipHeader, testHeaderJson)
+		header = <-headers
+		assert.Equal(t, uint64(0x400), header.Number)
+		waitResubscribed(t, sub)
+
+		// a subscription is subscribed again if connection is lost right after node replies
+		server.mutex.Lock()
+		server.dropAfterSubscribe = true
+		server.mutex.Unlock()
+		blocks, blockSub, err := client.SubscribeNewTipBlock(context.Background())
+		assert.NoError(t, err)
+		server.waitSubscribed(t, TopicNewTipBlock)
+		server.waitSubscribed(t, TopicNewTipHeader, TopicRejectedTransaction, TopicNewTipBlock)
+		waitResubscribed(t, blockSub)
+		blockSub.Unsubscribe()
+		_, ok := <-blocks
+		assert.False(t, ok)
+
+		sub.Unsubscribe()
+		_, ok = <-headers
+		assert.False(t, ok)
+		client.Close()
+		_, ok = <-rejections
+		assert.False(t, ok)
+		_, _, err = client.SubscribeNewTipBlock(context.Background())
+		assert.Equal(t, ErrSubscriptionClosed, err)
+	}
+}
+
+func waitResubscribed(t *testing.T, sub *Subscription) {
+	select {
+	case <-sub.Resubscribed():
+	case <-time.After(5 * time.Second):
+		t.Fatal("resubscription timeout")
+	}
+}
@@ -0,0 +1,314 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPollInterval      = 3 * time.Second
+	DefaultConfirmationDepth = 24
+
+	trackerBufferSize = 16
+)
+
+// TransactionEvent reports a change of a tracked transaction
+type TransactionEvent struct {
+	TxHash types.Hash
+	Status types.TransactionStatus
+	// BlockHash and BlockNumber are the committing block, or the orphaned block if Reorged is true
+	BlockHash   *types.Hash
+	BlockNumber uint64
+	// Confirmations is the number of blocks from the committing block to tip, both inclusive
+	Confirmations uint64
+	// Confirmed is true in the event in which Confirmations reaches ConfirmationDepth
+	Confirmed bool
+	// Reorged is true if the committing block has left the canonical chain
+	Reorged bool
+	// Reason is the reason of rejection
+	Reason *string
+}
+
+type trackedTransaction struct {
+	hash   types.Hash
+	events chan *TransactionEvent
+	quit   chan struct{}
+	once   sync.Once
+
+	mutex    sync.Mutex
+	isClosed bool
+
+	// state reported by the last event, which is only accessed by the polling goroutine
+	status      types.TransactionStatus
+	blockHash   *types.Hash
+	blockNumber uint64
+	confirmed   bool
+}
+
+// TransactionTracker polls status of transactions, e.g. after SendTransaction, and reports their changes. All tracked
+// transactions are polled together, with a few batch requests per round.
+type TransactionTracker struct {
+	PollInterval time.Duration
+	// ConfirmationDepth is the number of confirmations at which a committed transaction is reported as confirmed
+	ConfirmationDepth uint64
+
+	client Client
+	mutex  sync.Mutex
+	txs    map[types.Hash]*trackedTransaction
+}
+
+func NewTransactionTracker(client Client) *TransactionTracker {
+	return &TransactionTracker{
+		PollInterval:      DefaultPollInterval,
+		ConfirmationDepth: DefaultConfirmationDepth,
+		client:            client,
+		txs:               make(map[types.Hash]*trackedTransaction),
+	}
+}
+
+// Track starts tracking the transaction of hash. Events are reported over the returned channel, which is closed
+// after Untrack or Run returns. The first event reports the status found by the next poll.
+func (t *TransactionTracker) Track(hash types.Hash) (<-chan *TransactionEvent, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if _, ok := t.txs[hash]; ok {
+		return nil, fmt.Errorf("transaction %s is already tracked", hash)
+	}
+	tx := &trackedTransaction{
+		hash:   hash,
+		events: make(chan *TransactionEvent, trackerBufferSize),
+		quit:   make(chan struct{}),
+	}
+	t.txs[hash] = tx
+	return tx.events, nil
+}
+
+// Untrack stops tracking the transaction of hash and closes its channel
+func (t *TransactionTracker) Untrack(hash types.Hash) {
+	t.mutex.Lock()
+	tx, ok := t.txs[hash]
+	delete(t.txs, hash)
+	t.mutex.Unlock()
+	if ok {
+		tx.close()
+	}
+}
+
+// Run polls every PollInterval until ctx is done, then untracks all transactions. Errors of a round are ignored, and
+// the transactions are polled again in the next round.
+func (t *TransactionTracker) Run(ctx context.Context) error {
+	defer t.untrackAll()
+	ticker := time.NewTicker(t.PollInterval)
+	defer ticker.Stop()
+	for {
+		_ = t.Poll(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll queries all tracked transactions once and reports their changes. It blocks while a channel is full.
+func (t *TransactionTracker) Poll(ctx context.Context) error {
+	t.mutex.Lock()
+	txs := make([]*trackedTransaction, 0, len(t.txs))
+	for _, tx := range t.txs {
+		txs = append(txs, tx)
+	}
+	t.mutex.Unlock()
+	if len(txs) == 0 {
+		return nil
+	}
+
+	tip, err := t.client.GetTipBlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	batch := NewBatchRequest()
+	items := make([]*types.BatchTransactionItem, len(txs))
+	for i, tx := range txs {
+		items[i] = batch.GetTransaction(tx.hash)
+	}
+	if err := t.client.BatchCall(ctx, batch); err != nil {
+		return err
+	}
+
+	// block numbers of new committing blocks are looked up by header, and all committing blocks are checked against
+	// the canonical chain
+	blocks := make(map[types.Hash]*types.BatchHeaderItem)
+	batch = NewBatchRequest()
+	for i, tx := range txs {
+		blockHash := committedBlock(items[i].Result)
+		if blockHash == nil || (tx.blockHash != nil && *tx.blockHash == *blockHash) {
+			continue
+		}
+		if _, ok := blocks[*blockHash]; !ok {
+			blocks[*blockHash] = batch.GetHeader(*blockHash)
+		}
+	}
+	if err := t.client.BatchCall(ctx, batch); err != nil {
+		return err
+	}
+	numbers := make([]uint64, len(txs))
+	canonical := make([]*types.BatchHashItem, len(txs))
+	batch = NewBatchRequest()
+	for i, tx := range txs {
+		blockHash := committedBlock(items[i].Result)
+		if blockHash == nil {
+			continue
+		}
+		if header, ok := blocks[*blockHash]; ok {
+			if header.Error != nil {
+				continue
+			}
+			numbers[i] = header.Result.Number
+		} else {
+			numbers[i] = tx.blockNumber
+		}
+		canonical[i] = batch.GetBlockHash(numbers[i])
+	}
+	if err := t.client.BatchCall(ctx, batch); err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, tx := range txs {
+		item := items[i]
+		if item.Error != nil && !errors.Is(item.Error, NotFound) {
+			errs = append(errs, item.Error)
+			continue
+		}
+		var (
+			status    = types.TransactionStatusUnknown
+			blockHash *types.Hash
+			reason    *string
+		)
+		if item.Result != nil && item.Result.TxStatus != nil {
+			status = item.Result.TxStatus.Status
+			reason = item.Result.TxStatus.Reason
+		}
+		if status == types.TransactionStatusCommitted {
+			blockHash = committedBlock(item.Result)
+			if canonical[i] == nil {
+				// header of the committing block isn't found yet
+				continue
+			}
+			if canonical[i].Error != nil && !errors.Is(canonical[i].Error, NotFound) {
+				errs = append(errs, canonical[i].Error)
+				continue
+			}
+			if canonical[i].Result == nil || *canonical[i].Result != *blockHash {
+				// node hasn't caught up with the switch of chain, the transaction will leave the block soon
+				status = types.TransactionStatusUnknown
+				blockHash = nil
+			}
+		}
+		if !t.update(ctx, tx, tip, status, blockHash, numbers[i], reason) {
+			return ctx.Err()
+		}
+	}
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// update compares the polled state with the last reported one, and reports the changes. It returns false if ctx is
+// done while reporting.
+func (t *TransactionTracker) update(ctx context.Context, tx *trackedTransaction, tip uint64, status types.TransactionStatus,
+	blockHash *types.Hash, blockNumber uint64, reason *string) bool {
+	if tx.status == types.TransactionStatusCommitted && (blockHash == nil || *blockHash != *tx.blockHash) {
+		event := &TransactionEvent{
+			TxHash:      tx.hash,
+			Status:      status,
+			BlockHash:   tx.blockHash,
+			BlockNumber: tx.blockNumber,
+			Reorged:     true,
+			Reason:      reason,
+		}
+		tx.status, tx.blockHash, tx.blockNumber, tx.confirmed = status, nil, 0, false
+		if blockHash == nil {
+			return tx.deliver(ctx, event)
+		}
+		if !tx.deliver(ctx, event) {
+			return false
+		}
+		// committed again in another block
+		tx.status = ""
+	}
+
+	event := &TransactionEvent{
+		TxHash: tx.hash,
+		Status: status,
+		Reason: reason,
+	}
+	changed := status != tx.status
+	if status == types.TransactionStatusCommitted {
+		event.BlockHash = blockHash
+		event.BlockNumber = blockNumber
+		if tip >= blockNumber {
+			event.Confirmations = tip - blockNumber + 1
+		}
+		if !tx.confirmed && event.Confirmations >= t.ConfirmationDepth {
+			event.Confirmed = true
+			changed = true
+		}
+	}
+	if !changed {
+		return true
+	}
+	tx.status, tx.blockHash, tx.blockNumber = status, blockHash, blockNumber
+	tx.confirmed = tx.confirmed || event.Confirmed
+	return tx.deliver(ctx, event)
+}
+
+// committedBlock returns the committing block of tx, or nil if tx isn't committed
+func committedBlock(tx *types.TransactionWithStatus) *types.Hash {
+	if tx == nil || tx.TxStatus == nil || tx.TxStatus.Status != types.TransactionStatusCommitted {
+		return nil
+	}
+	return tx.TxStatus.BlockHash
+}
+
+func (t *TransactionTracker) untrackAll() {
+	t.mutex.Lock()
+	txs := t.txs
+	t.txs = make(map[types.Hash]*trackedTransaction)
+	t.mutex.Unlock()
+	for _, tx := range txs {
+		tx.close()
+	}
+}
+
+// deliver sends event unless the transaction is untracked, and returns false if ctx is done
+func (tx *trackedTransaction) deliver(ctx context.Context, event *TransactionEvent) bool {
+	tx.mutex.Lock()
+	defer tx.mutex.Unlock()
+	if tx.isClosed {
+		return true
+	}
+	select {
+	case tx.events <- event:
+		return true
+	case <-tx.quit:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (tx *trackedTransaction) close() {
+	tx.once.Do(func() {
+		close(tx.quit)
+		tx.mutex.Lock()
+		tx.isClosed = true
+		close(tx.events)
+		tx.mutex.Unlock()
+	})
+}
@@ -0,0 +1,219 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// trackerMockClient serves batch calls from an in-memory chain
+type trackerMockClient struct {
+	Client
+	tip       uint64
+	canonical map[uint64]types.Hash
+	numbers   map[types.Hash]uint64
+	statuses  map[types.Hash]*types.TxStatus
+	batches   int
+}
+
+func newTrackerMockClient() *trackerMockClient {
+	return &trackerMockClient{
+		canonical: make(map[uint64]types.Hash),
+		numbers:   make(map[types.Hash]uint64),
+		statuses:  make(map[types.Hash]*types.TxStatus),
+	}
+}
+
+func (c *trackerMockClient) addBlock(number uint64, hash types.Hash) {
+	c.canonical[number] = hash
+	c.numbers[hash] = number
+	if number > c.tip {
+		c.tip = number
+	}
+}
+
+func (c *trackerMockClient) commit(tx types.Hash, block types.Hash) {
+	c.statuses[tx] = &types.TxStatus{Status: types.TransactionStatusCommitted, BlockHash: &block}
+}
+
+func (c *trackerMockClient) GetTipBlockNumber(ctx context.Context) (uint64, error) {
+	return c.tip, nil
+}
+
+func (c *trackerMockClient) BatchCall(ctx context.Context, batch *BatchRequest) error {
+	if batch.Len() == 0 {
+		return nil
+	}
+	c.batches += 1
+	for i, elem := range batch.elems {
+		var result interface{}
+		switch elem.Method {
+		case "get_transaction":
+			hash := elem.Args[0].(types.Hash)
+			status, ok := c.statuses[hash]
+			if !ok {
+				status = &types.TxStatus{Status: types.TransactionStatusUnknown}
+			}
+			result = map[string]interface{}{"transaction": nil, "tx_status": status}
+		case "get_header":
+			hash := elem.Args[0].(types.Hash)
+			if number, ok := c.numbers[hash]; ok {
+				result = map[string]interface{}{
+					"compact_target":    "0x0",
+					"dao":               types.Hash{},
+					"epoch":             "0x0",
+					"extra_hash":        types.Hash{},
+					"hash":              hash,
+					"nonce":             "0x0",
+					"number":            hexutil.Uint64(number),
+					"parent_hash":       types.Hash{},
+					"proposals_hash":    types.Hash{},
+					"timestamp":         "0x0",
+					"transactions_root": types.Hash{},
+					"version":           "0x0",
+				}
+			}
+		case "get_block_hash":
+			number := uint64(elem.Args[0].(hexutil.Uint64))
+			if hash, ok := c.canonical[number]; ok {
+				result = hash
+			}
+		}
+		raw, _ := json.Marshal(result)
+		if err := json.Unmarshal(raw, elem.Result); err != nil {
+			return err
+		}
+		batch.setters[i](nil)
+	}
+	return nil
+}
+
+func nextEvent(t *testing.T, events <-chan *TransactionEvent) *TransactionEvent {
+	select {
+	case event := <-events:
+		return event
+	default:
+		t.Fatal("no event")
+		return nil
+	}
+}
+
+func assertNoEvent(t *testing.T, events <-chan *TransactionEvent) {
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event %+v", event)
+	default:
+	}
+}
+
+func TestTransactionTracker(t *testing.T) {
+	client := newTrackerMockClient()
+	client.addBlock(10, types.Hash{10})
+	tracker := NewTransactionTracker(client)
+	tracker.ConfirmationDepth = 3
+	ctx := context.Background()
+
+	txHash := types.Hash{1}
+	events, err := tracker.Track(txHash)
+	assert.NoError(t, err)
+	_, err = tracker.Track(txHash)
+	assert.Error(t, err)
+
+	assert.NoError(t, tracker.Poll(ctx))
+	assert.Equal(t, types.TransactionStatusUnknown, nextEvent(t, events).Status)
+	assert.NoError(t, tracker.Poll(ctx))
+	assertNoEvent(t, events)
+
+	client.statuses[txHash] = &types.TxStatus{Status: types.TransactionStatusPending}
+	assert.NoError(t, tracker.Poll(ctx))
+	assert.Equal(t, types.TransactionStatusPending, nextEvent(t, events).Status)
+
+	client.addBlock(11, types.Hash{11})
+	client.commit(txHash, types.Hash{11})
+	assert.NoError(t, tracker.Poll(ctx))
+	event := nextEvent(t, events)
+	assert.Equal(t, types.TransactionStatusCommitted, event.Status)
+	assert.Equal(t, types.Hash{11}, *event.BlockHash)
+	assert.Equal(t, uint64(11), event.BlockNumber)
+	assert.Equal(t, uint64(1), event.Confirmations)
+	assert.False(t, event.Confirmed)
+
+	client.addBlock(12, types.Hash{12})
+	assert.NoError(t, tracker.Poll(ctx))
+	assertNoEvent(t, events)
+
+	client.addBlock(13, types.Hash{13})
+	assert.NoError(t, tracker.Poll(ctx))
+	event = nextEvent(t, events)
+	assert.True(t, event.Confirmed)
+	assert.Equal(t, uint64(3), event.Confirmations)
+	client.addBlock(14, types.Hash{14})
+	assert.NoError(t, tracker.Poll(ctx))
+	assertNoEvent(t, events)
+
+	// block 11 is replaced while node still reports the transaction committed in it
+	client.addBlock(11, types.Hash{0x11})
+	assert.NoError(t, tracker.Poll(ctx))
+	event = nextEvent(t, events)
+	assert.True(t, event.Reorged)
+	assert.Equal(t, types.TransactionStatusUnknown, event.Status)
+	assert.Equal(t, types.Hash{11}, *event.BlockHash)
+
+	// committed again in the new branch
+	client.commit(txHash, types.Hash{0x11})
+	assert.NoError(t, tracker.Poll(ctx))
+	event = nextEvent(t, events)
+	assert.False(t, event.Reorged)
+	assert.Equal(t, types.TransactionStatusCommitted, event.Status)
+	assert.Equal(t, types.Hash{0x11}, *event.BlockHash)
+	assert.True(t, event.Confirmed)
+
+	// moved into another block directly
+	client.addBlock(15, types.Hash{15})
+	client.commit(txHash, types.Hash{15})
+	assert.NoError(t, tracker.Poll(ctx))
+	event = nextEvent(t, events)
+	assert.True(t, event.Reorged)
+	assert.Equal(t, types.Hash{0x11}, *event.BlockHash)
+	event = nextEvent(t, events)
+	assert.Equal(t, types.Hash{15}, *event.BlockHash)
+	assert.Equal(t, uint64(1), event.Confirmations)
+	assert.False(t, event.Confirmed)
+
+	tracker.Untrack(txHash)
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestTransactionTracker_SharedPolling(t *testing.T) {
+	client := newTrackerMockClient()
+	client.addBlock(1, types.Hash{1})
+	tracker := NewTransactionTracker(client)
+	var channels []<-chan *TransactionEvent
+	for i := 0; i < 10; i++ {
+		txHash := types.Hash{0xff, byte(i)}
+		client.commit(txHash, types.Hash{1})
+		events, err := tracker.Track(txHash)
+		assert.NoError(t, err)
+		channels = append(channels, events)
+	}
+	assert.NoError(t, tracker.Poll(context.Background()))
+	// transactions, headers and canonical hashes
+	assert.Equal(t, 3, client.batches)
+	for _, events := range channels {
+		event := nextEvent(t, events)
+		assert.Equal(t, types.TransactionStatusCommitted, event.Status)
+		assert.Equal(t, uint64(1), event.BlockNumber)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, tracker.Run(ctx))
+	for _, events := range channels {
+		for range events {
+		}
+	}
+}
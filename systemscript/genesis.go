@@ -0,0 +1,110 @@
+package systemscript
+
+import (
+	"fmt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+)
+
+// Positions of system cells in the genesis block. The cellbase holds code cells, and the second transaction holds dep
+// groups of secp256k1 lock scripts.
+const (
+	genesisSighashAllIndex     = 1
+	genesisDaoIndex            = 2
+	genesisSecp256k1DataIndex  = 3
+	genesisMultisigAllIndex    = 4
+	genesisSighashAllDepGroup  = 0
+	genesisMultisigAllDepGroup = 1
+	genesisDepGroupTransaction = 1
+)
+
+// NewInfosFromGenesis derives secp256k1 sighash and multisig dep groups and the DAO code cell from the genesis block.
+// Code hashes are the type hashes of code cells, which are verified against those in consensus.
+func NewInfosFromGenesis(genesis *types.Block, consensus *types.Consensus) (map[SystemScript]*Info, error) {
+	if genesis == nil || len(genesis.Transactions) <= genesisDepGroupTransaction {
+		return nil, fmt.Errorf("genesis block should have at least %d transactions", genesisDepGroupTransaction+1)
+	}
+	if genesis.Header != nil && genesis.Header.Hash != consensus.GenesisHash {
+		return nil, fmt.Errorf("genesis hash %s mismatches consensus %s", genesis.Header.Hash, consensus.GenesisHash)
+	}
+	cellbase := genesis.Transactions[0]
+	depGroupTx := genesis.Transactions[genesisDepGroupTransaction]
+	cellbaseHash := cellbase.ComputeHash()
+	depGroupTxHash := depGroupTx.ComputeHash()
+
+	sighashAll, err := genesisTypeHash(cellbase, genesisSighashAllIndex, consensus.Secp256k1Blake160SighashAllTypeHash)
+	if err != nil {
+		return nil, err
+	}
+	multisigAll, err := genesisTypeHash(cellbase, genesisMultisigAllIndex, consensus.Secp256k1Blake160MultisigAllTypeHash)
+	if err != nil {
+		return nil, err
+	}
+	dao, err := genesisTypeHash(cellbase, genesisDaoIndex, consensus.DaoTypeHash)
+	if err != nil {
+		return nil, err
+	}
+	secp256k1Data := &types.OutPoint{TxHash: cellbaseHash, Index: genesisSecp256k1DataIndex}
+	if err := checkGenesisDepGroup(depGroupTx, genesisSighashAllDepGroup,
+		secp256k1Data, &types.OutPoint{TxHash: cellbaseHash, Index: genesisSighashAllIndex}); err != nil {
+		return nil, err
+	}
+	if err := checkGenesisDepGroup(depGroupTx, genesisMultisigAllDepGroup,
+		secp256k1Data, &types.OutPoint{TxHash: cellbaseHash, Index: genesisMultisigAllIndex}); err != nil {
+		return nil, err
+	}
+
+	return map[SystemScript]*Info{
+		Secp256k1Blake160SighashAll: {
+			CodeHash: sighashAll,
+			HashType: types.HashTypeType,
+			OutPoint: &types.OutPoint{TxHash: depGroupTxHash, Index: genesisSighashAllDepGroup},
+			DepType:  types.DepTypeDepGroup,
+		},
+		Secp256k1Blake160MultisigAll: {
+			CodeHash: multisigAll,
+			HashType: types.HashTypeType,
+			OutPoint: &types.OutPoint{TxHash: depGroupTxHash, Index: genesisMultisigAllDepGroup},
+			DepType:  types.DepTypeDepGroup,
+		},
+		Dao: {
+			CodeHash: dao,
+			HashType: types.HashTypeType,
+			OutPoint: &types.OutPoint{TxHash: cellbaseHash, Index: genesisDaoIndex},
+			DepType:  types.DepTypeCode,
+		},
+	}, nil
+}
+
+func genesisTypeHash(cellbase *types.Transaction, index int, expected *types.Hash) (types.Hash, error) {
+	if len(cellbase.Outputs) <= index || cellbase.Outputs[index].Type == nil {
+		return types.Hash{}, fmt.Errorf("genesis cell %d has no type script", index)
+	}
+	hash := cellbase.Outputs[index].Type.Hash()
+	if expected == nil || hash != *expected {
+		return types.Hash{}, fmt.Errorf("type hash %s of genesis cell %d mismatches consensus", hash, index)
+	}
+	return hash, nil
+}
+
+func checkGenesisDepGroup(tx *types.Transaction, index int, outPoints ...*types.OutPoint) error {
+	if len(tx.OutputsData) <= index {
+		return fmt.Errorf("genesis dep group %d not found", index)
+	}
+	group, err := types.DeserializeOutPointVec(tx.OutputsData[index])
+	if err != nil {
+		return err
+	}
+	for _, outPoint := range outPoints {
+		found := false
+		for _, o := range group {
+			if *o == *outPoint {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("genesis dep group %d doesn't contain cell %d", index, outPoint.Index)
+		}
+	}
+	return nil
+}
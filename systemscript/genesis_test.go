@@ -0,0 +1,85 @@
+package systemscript
+
+import (
+	"encoding/binary"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func getGenesisBlock() (*types.Block, *types.Consensus) {
+	cellbase := &types.Transaction{}
+	for i := 0; i < 5; i++ {
+		cellbase.Outputs = append(cellbase.Outputs, &types.CellOutput{
+			Capacity: 100000000000,
+			Lock:     &types.Script{CodeHash: types.Hash{}, HashType: types.HashTypeData, Args: []byte{}},
+			Type:     TypeIdScript(&types.CellInput{PreviousOutput: &types.OutPoint{}}, uint64(i)),
+		})
+		cellbase.OutputsData = append(cellbase.OutputsData, []byte{byte(i)})
+	}
+	cellbaseHash := cellbase.ComputeHash()
+	depGroup := func(indices ...uint32) []byte {
+		data := make([]byte, 4)
+		binary.LittleEndian.PutUint32(data, uint32(len(indices)))
+		for _, i := range indices {
+			data = append(data, (&types.OutPoint{TxHash: cellbaseHash, Index: i}).Serialize()...)
+		}
+		return data
+	}
+	depGroupTx := &types.Transaction{
+		Outputs: []*types.CellOutput{
+			{Capacity: 100000000000, Lock: cellbase.Outputs[0].Lock},
+			{Capacity: 100000000000, Lock: cellbase.Outputs[0].Lock},
+		},
+		OutputsData: [][]byte{depGroup(3, 1), depGroup(3, 4)},
+	}
+	sighashAll := cellbase.Outputs[1].Type.Hash()
+	dao := cellbase.Outputs[2].Type.Hash()
+	multisigAll := cellbase.Outputs[4].Type.Hash()
+	block := &types.Block{
+		Header:       &types.Header{Hash: types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001")},
+		Transactions: []*types.Transaction{cellbase, depGroupTx},
+	}
+	consensus := &types.Consensus{
+		GenesisHash:                          block.Header.Hash,
+		DaoTypeHash:                          &dao,
+		Secp256k1Blake160SighashAllTypeHash:  &sighashAll,
+		Secp256k1Blake160MultisigAllTypeHash: &multisigAll,
+	}
+	return block, consensus
+}
+
+func TestNewInfosFromGenesis(t *testing.T) {
+	block, consensus := getGenesisBlock()
+	contracts, err := NewInfosFromGenesis(block, consensus)
+	assert.NoError(t, err)
+	cellbaseHash := block.Transactions[0].ComputeHash()
+	depGroupTxHash := block.Transactions[1].ComputeHash()
+	assert.Equal(t, &Info{
+		CodeHash: *consensus.Secp256k1Blake160SighashAllTypeHash,
+		HashType: types.HashTypeType,
+		OutPoint: &types.OutPoint{TxHash: depGroupTxHash, Index: 0},
+		DepType:  types.DepTypeDepGroup,
+	}, contracts[Secp256k1Blake160SighashAll])
+	assert.Equal(t, &types.OutPoint{TxHash: depGroupTxHash, Index: 1}, contracts[Secp256k1Blake160MultisigAll].OutPoint)
+	assert.Equal(t, &types.OutPoint{TxHash: cellbaseHash, Index: 2}, contracts[Dao].OutPoint)
+	assert.Equal(t, types.DepTypeCode, contracts[Dao].DepType)
+
+	// type hashes mismatch
+	block, consensus = getGenesisBlock()
+	consensus.DaoTypeHash = consensus.Secp256k1Blake160SighashAllTypeHash
+	_, err = NewInfosFromGenesis(block, consensus)
+	assert.Error(t, err)
+
+	// dep group doesn't reference the code cell
+	block, consensus = getGenesisBlock()
+	block.Transactions[1].OutputsData[0], block.Transactions[1].OutputsData[1] = block.Transactions[1].OutputsData[1], block.Transactions[1].OutputsData[0]
+	_, err = NewInfosFromGenesis(block, consensus)
+	assert.Error(t, err)
+
+	// consensus of another chain
+	block, consensus = getGenesisBlock()
+	consensus.GenesisHash = types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002")
+	_, err = NewInfosFromGenesis(block, consensus)
+	assert.Error(t, err)
+}
@@ -5,10 +5,18 @@ import (
 )
 
 type Info struct {
-	CodeHash types.Hash
-	HashType types.ScriptHashType
-	OutPoint *types.OutPoint
-	DepType  types.DepType
+	CodeHash types.Hash           `json:"code_hash"`
+	HashType types.ScriptHashType `json:"hash_type"`
+	OutPoint *types.OutPoint      `json:"out_point"`
+	DepType  types.DepType        `json:"dep_type"`
+}
+
+// CellDep returns the cell dep to reference the script
+func (r *Info) CellDep() *types.CellDep {
+	return &types.CellDep{
+		OutPoint: r.OutPoint,
+		DepType:  r.DepType,
+	}
 }
 
 type SystemScript uint
@@ -22,6 +30,7 @@ const (
 	Cheque
 	PwLock
 	Omnilock
+	Xudt
 )
 
 var mainnetContracts = make(map[SystemScript]*Info)
@@ -105,6 +114,15 @@ func initMainnetSystemScript() {
 		},
 		DepType: types.DepTypeCode,
 	}
+	mainnetContracts[Xudt] = &Info{
+		CodeHash: types.HexToHash("0x50bd8d6680b8b9cf98b73f3c08faf8b2a21914311954118ad6609be6e78a1b95"),
+		HashType: types.HashTypeData1,
+		OutPoint: &types.OutPoint{
+			TxHash: types.HexToHash("0xc07844ce21b38e4b071dd0e1ee3b0e27afd8d7532491327f39b786343f558ab7"),
+			Index:  0,
+		},
+		DepType: types.DepTypeCode,
+	}
 }
 
 func initTestnetSystemScript() {
@@ -180,9 +198,23 @@ func initTestnetSystemScript() {
 		},
 		DepType: types.DepTypeCode,
 	}
+	testnetContracts[Xudt] = &Info{
+		CodeHash: types.HexToHash("0x25c29dc317811a6f6f3985a7a9ebc4838bd388d19d0feeecf0bcd60f6c0975bb"),
+		HashType: types.HashTypeType,
+		OutPoint: &types.OutPoint{
+			TxHash: types.HexToHash("0xbf6fb538763efec2a70a6a3dcb7242787087e1030c4e7d86585bc63a9d337f5f"),
+			Index:  0,
+		},
+		DepType: types.DepTypeCode,
+	}
 }
 
+// GetInfo returns the system script of network, or nil if it's unavailable. Scripts registered by RegisterNetwork are
+// looked up first.
 func GetInfo(network types.Network, script SystemScript) *Info {
+	if info, ok := getRegisteredInfo(network, script); ok {
+		return info
+	}
 	switch network {
 	case types.NetworkMain:
 		return mainnetContracts[script]
@@ -193,8 +225,13 @@ func GetInfo(network types.Network, script SystemScript) *Info {
 	}
 }
 
+// GetCodeHash returns the code hash of system script, or zero hash if it's unavailable on network
 func GetCodeHash(network types.Network, script SystemScript) types.Hash {
-	return GetInfo(network, script).CodeHash
+	info := GetInfo(network, script)
+	if info == nil {
+		return types.Hash{}
+	}
+	return info.CodeHash
 }
 
 func NewScript(script SystemScript, args []byte, network types.Network) *types.Script {
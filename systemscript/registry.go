@@ -0,0 +1,145 @@
+package systemscript
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"io"
+	"os"
+	"sync"
+)
+
+var systemScriptNames = map[SystemScript]string{
+	Secp256k1Blake160SighashAll:  "secp256k1_blake160_sighash_all",
+	Secp256k1Blake160MultisigAll: "secp256k1_blake160_multisig_all",
+	AnyoneCanPay:                 "anyone_can_pay",
+	Dao:                          "dao",
+	Sudt:                         "sudt",
+	Cheque:                       "cheque",
+	PwLock:                       "pw_lock",
+	Omnilock:                     "omnilock",
+	Xudt:                         "xudt",
+}
+
+func (r SystemScript) String() string {
+	if name, ok := systemScriptNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("SystemScript(%d)", uint(r))
+}
+
+// ParseSystemScript parses the snake case name of system script, e.g. secp256k1_blake160_sighash_all
+func ParseSystemScript(name string) (SystemScript, error) {
+	for script, n := range systemScriptNames {
+		if n == name {
+			return script, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown system script %s", name)
+}
+
+var (
+	registryMutex sync.RWMutex
+	registry      = make(map[types.Network]map[SystemScript]*Info)
+	// registryVersion is increased on every change of registry
+	registryVersion uint64
+)
+
+// RegisterNetwork registers system scripts of network, e.g. a devnet with its own cell deps. It replaces the scripts
+// registered for network before. Registered scripts take precedence over the built-in ones of mainnet and testnet.
+func RegisterNetwork(network types.Network, contracts map[SystemScript]*Info) {
+	m := make(map[SystemScript]*Info, len(contracts))
+	for script, info := range contracts {
+		m[script] = info
+	}
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry[network] = m
+	registryVersion++
+}
+
+// RegisterInfo registers a single system script of network
+func RegisterInfo(network types.Network, script SystemScript, info *Info) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	m, ok := registry[network]
+	if !ok {
+		m = make(map[SystemScript]*Info)
+		registry[network] = m
+	}
+	m[script] = info
+	registryVersion++
+}
+
+// UnregisterNetwork removes the scripts registered for network
+func UnregisterNetwork(network types.Network) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	delete(registry, network)
+	registryVersion++
+}
+
+// RegistryVersion returns a number which changes whenever scripts are registered or unregistered, so that callers
+// caching registered scripts know when to refresh.
+func RegistryVersion() uint64 {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	return registryVersion
+}
+
+// IsKnownNetwork returns true for mainnet, testnet and registered networks
+func IsKnownNetwork(network types.Network) bool {
+	if network == types.NetworkMain || network == types.NetworkTest {
+		return true
+	}
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	_, ok := registry[network]
+	return ok
+}
+
+func getRegisteredInfo(network types.Network, script SystemScript) (*Info, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	m, ok := registry[network]
+	if !ok {
+		return nil, false
+	}
+	info, ok := m[script]
+	return info, ok
+}
+
+// LoadNetwork registers system scripts of network from a JSON deployment file, which maps script names to infos, e.g.
+//
+//	{"secp256k1_blake160_sighash_all": {"code_hash": "0x...", "hash_type": "type", "out_point": {"tx_hash": "0x...", "index": "0x0"}, "dep_type": "dep_group"}}
+//
+// Records of builder.DeployTransactionBuilder can be saved into the file under script names.
+func LoadNetwork(network types.Network, r io.Reader) error {
+	var file map[string]*Info
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return err
+	}
+	contracts := make(map[SystemScript]*Info, len(file))
+	for name, info := range file {
+		script, err := ParseSystemScript(name)
+		if err != nil {
+			return err
+		}
+		if info == nil || info.OutPoint == nil {
+			return fmt.Errorf("out point of system script %s is missing", name)
+		}
+		contracts[script] = info
+	}
+	RegisterNetwork(network, contracts)
+	return nil
+}
+
+// LoadNetworkFromFile is LoadNetwork reading the deployment file at path
+func LoadNetworkFromFile(network types.Network, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return LoadNetwork(network, f)
+}
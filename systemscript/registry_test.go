@@ -0,0 +1,74 @@
+package systemscript
+
+import (
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const devnet types.Network = 100
+
+func TestRegisterNetwork(t *testing.T) {
+	defer UnregisterNetwork(devnet)
+	assert.False(t, IsKnownNetwork(devnet))
+	assert.Nil(t, GetInfo(devnet, Secp256k1Blake160SighashAll))
+	assert.Equal(t, types.Hash{}, GetCodeHash(devnet, Secp256k1Blake160SighashAll))
+
+	info := &Info{
+		CodeHash: types.HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+		HashType: types.HashTypeType,
+		OutPoint: &types.OutPoint{
+			TxHash: types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001"),
+			Index:  0,
+		},
+		DepType: types.DepTypeDepGroup,
+	}
+	RegisterNetwork(devnet, map[SystemScript]*Info{Secp256k1Blake160SighashAll: info})
+	assert.True(t, IsKnownNetwork(devnet))
+	assert.Equal(t, info, GetInfo(devnet, Secp256k1Blake160SighashAll))
+	assert.Nil(t, GetInfo(devnet, Sudt))
+
+	RegisterInfo(devnet, Sudt, info)
+	assert.Equal(t, info, GetInfo(devnet, Sudt))
+	// built-in networks are not affected
+	assert.NotEqual(t, info, GetInfo(types.NetworkTest, Sudt))
+}
+
+func TestLoadNetwork(t *testing.T) {
+	defer UnregisterNetwork(devnet)
+	file := `{
+		"secp256k1_blake160_sighash_all": {
+			"code_hash": "0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8",
+			"hash_type": "type",
+			"out_point": {"tx_hash": "0x0000000000000000000000000000000000000000000000000000000000000001", "index": "0x0"},
+			"dep_type": "dep_group"
+		},
+		"sudt": {
+			"code_hash": "0x0000000000000000000000000000000000000000000000000000000000000002",
+			"hash_type": "data1",
+			"out_point": {"tx_hash": "0x0000000000000000000000000000000000000000000000000000000000000003", "index": "0x2"},
+			"dep_type": "code",
+			"data_hash": "0x0000000000000000000000000000000000000000000000000000000000000002"
+		}
+	}`
+	assert.NoError(t, LoadNetwork(devnet, strings.NewReader(file)))
+	info := GetInfo(devnet, Sudt)
+	assert.Equal(t, types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002"), info.CodeHash)
+	assert.Equal(t, types.HashTypeData1, info.HashType)
+	assert.Equal(t, uint32(2), info.OutPoint.Index)
+	assert.Equal(t, types.DepTypeCode, info.DepType)
+	assert.Equal(t, types.DepTypeDepGroup, GetInfo(devnet, Secp256k1Blake160SighashAll).DepType)
+
+	assert.Error(t, LoadNetwork(devnet, strings.NewReader(`{"unknown": {}}`)))
+	assert.Error(t, LoadNetwork(devnet, strings.NewReader(`{"dao": {"code_hash": "0x0000000000000000000000000000000000000000000000000000000000000002"}}`)))
+}
+
+func TestSystemScriptName(t *testing.T) {
+	assert.Equal(t, "secp256k1_blake160_multisig_all", Secp256k1Blake160MultisigAll.String())
+	script, err := ParseSystemScript("xudt")
+	assert.NoError(t, err)
+	assert.Equal(t, Xudt, script)
+	_, err = ParseSystemScript("unknown")
+	assert.Error(t, err)
+}
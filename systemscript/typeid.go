@@ -0,0 +1,31 @@
+package systemscript
+
+import (
+	"encoding/binary"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/blake2b"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+)
+
+// TypeIdCodeHash is the code hash of the built-in Type ID script, which is referenced by hash type type and needs no
+// cell dep.
+var TypeIdCodeHash = types.HexToHash("0x00000000000000000000000000000000000000000000000000545950455f4944")
+
+// TypeIdArgs computes Type ID args of the output at outputIndex, which is the blake2b hash of the first input of
+// transaction and the output index in 8-byte little endian.
+func TypeIdArgs(firstInput *types.CellInput, outputIndex uint64) []byte {
+	index := make([]byte, 8)
+	binary.LittleEndian.PutUint64(index, outputIndex)
+	return blake2b.Blake256(append(firstInput.Serialize(), index...))
+}
+
+func TypeIdScript(firstInput *types.CellInput, outputIndex uint64) *types.Script {
+	return &types.Script{
+		CodeHash: TypeIdCodeHash,
+		HashType: types.HashTypeType,
+		Args:     TypeIdArgs(firstInput, outputIndex),
+	}
+}
+
+func IsTypeIdScript(script *types.Script) bool {
+	return script != nil && script.CodeHash == TypeIdCodeHash && script.HashType == types.HashTypeType && len(script.Args) == 32
+}
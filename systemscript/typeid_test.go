@@ -0,0 +1,24 @@
+package systemscript
+
+import (
+	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/blake2b"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTypeIdScript(t *testing.T) {
+	input := &types.CellInput{
+		Since: 0,
+		PreviousOutput: &types.OutPoint{
+			TxHash: types.HexToHash("0x8f8c79eb6671709633fe6a46de93c0fedc9c1b8a6527a18d3983879542635c9f"),
+			Index:  1,
+		},
+	}
+	script := TypeIdScript(input, 1)
+	assert.True(t, IsTypeIdScript(script))
+	expected := blake2b.Blake256(append(input.Serialize(), 1, 0, 0, 0, 0, 0, 0, 0))
+	assert.Equal(t, expected, script.Args)
+	assert.NotEqual(t, script.Args, TypeIdArgs(input, 0))
+	assert.False(t, IsTypeIdScript(&types.Script{CodeHash: TypeIdCodeHash, HashType: types.HashTypeData, Args: script.Args}))
+}
@@ -0,0 +1,213 @@
+package systemscript
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/blake2b"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+)
+
+const (
+	// XudtFlagsNoExtension means there is no extension script
+	XudtFlagsNoExtension uint32 = 0
+	// XudtFlagsExtensionScripts means extension data in args is a ScriptVec of extension scripts
+	XudtFlagsExtensionScripts uint32 = 1
+	// XudtFlagsExtensionScriptsHash means extension data in args is the blake160 hash of a ScriptVec, and the
+	// ScriptVec itself is provided in witness as raw extension data
+	XudtFlagsExtensionScriptsHash uint32 = 2
+
+	// XudtOwnerModeInputType also enables owner mode when an input's type script hash matches owner hash
+	XudtOwnerModeInputType uint32 = 0x80000000
+	// XudtOwnerModeOutputType also enables owner mode when an output's type script hash matches owner hash
+	XudtOwnerModeOutputType uint32 = 0x40000000
+	// XudtOwnerModeInputLockDisabled disables owner mode by input lock script hash
+	XudtOwnerModeInputLockDisabled uint32 = 0x20000000
+
+	xudtExtensionFlagsMask uint32 = 0x1FFFFFFF
+)
+
+// XudtArgs is the args of xUDT type script: a 32-byte owner script hash, optional 4-byte flags and extension data.
+type XudtArgs struct {
+	OwnerHash types.Hash
+	Flags     uint32
+	// ExtensionScripts is decoded from args when extension flags is XudtFlagsExtensionScripts, and should be set for
+	// both XudtFlagsExtensionScripts and XudtFlagsExtensionScriptsHash to encode args
+	ExtensionScripts []*types.Script
+	// ExtensionScriptsHash is decoded from args when extension flags is XudtFlagsExtensionScriptsHash
+	ExtensionScriptsHash []byte
+}
+
+func NewXudtArgs(ownerLock *types.Script) *XudtArgs {
+	return &XudtArgs{OwnerHash: ownerLock.Hash()}
+}
+
+// ExtensionFlags returns flags without owner mode bits
+func (r *XudtArgs) ExtensionFlags() uint32 {
+	return r.Flags & xudtExtensionFlagsMask
+}
+
+func (r *XudtArgs) Encode() []byte {
+	out := r.OwnerHash.Bytes()
+	if r.Flags == 0 && len(r.ExtensionScripts) == 0 {
+		return out
+	}
+	flags := make([]byte, 4)
+	binary.LittleEndian.PutUint32(flags, r.Flags)
+	out = append(out, flags...)
+	switch r.ExtensionFlags() {
+	case XudtFlagsExtensionScripts:
+		out = append(out, SerializeScriptVec(r.ExtensionScripts)...)
+	case XudtFlagsExtensionScriptsHash:
+		hash := r.ExtensionScriptsHash
+		if len(r.ExtensionScripts) > 0 {
+			hash = blake2b.Blake160(SerializeScriptVec(r.ExtensionScripts))
+		}
+		out = append(out, hash...)
+	}
+	return out
+}
+
+func DecodeToXudtArgs(in []byte) (*XudtArgs, error) {
+	if len(in) < 32 {
+		return nil, fmt.Errorf("xudt args should be at least 32 bytes but receive %d bytes", len(in))
+	}
+	args := &XudtArgs{OwnerHash: types.BytesToHash(in[:32])}
+	if len(in) == 32 {
+		return args, nil
+	}
+	if len(in) < 36 {
+		return nil, fmt.Errorf("invalid xudt args length %d", len(in))
+	}
+	args.Flags = binary.LittleEndian.Uint32(in[32:36])
+	data := in[36:]
+	switch args.ExtensionFlags() {
+	case XudtFlagsNoExtension:
+		if len(data) != 0 {
+			return nil, fmt.Errorf("unexpected %d bytes extension data in xudt args", len(data))
+		}
+	case XudtFlagsExtensionScripts:
+		scripts, err := DeserializeScriptVec(data)
+		if err != nil {
+			return nil, err
+		}
+		args.ExtensionScripts = scripts
+	case XudtFlagsExtensionScriptsHash:
+		if len(data) != 20 {
+			return nil, fmt.Errorf("extension scripts hash should be 20 bytes but receive %d bytes", len(data))
+		}
+		args.ExtensionScriptsHash = data
+	default:
+		return nil, fmt.Errorf("unknown xudt flags %d", args.Flags)
+	}
+	return args, nil
+}
+
+// XudtWitness is put in input_type of the witness of the first xUDT input, or output_type of the witness of the first
+// xUDT output if there is no xUDT input.
+type XudtWitness struct {
+	OwnerScript    *types.Script
+	OwnerSignature []byte
+	// RawExtensionData is the extension scripts required by XudtFlagsExtensionScriptsHash
+	RawExtensionData []*types.Script
+	// ExtensionData holds the data of extension scripts in their order
+	ExtensionData [][]byte
+}
+
+func (r *XudtWitness) Serialize() []byte {
+	var ownerScript, ownerSignature, rawExtensionData []byte
+	if r.OwnerScript != nil {
+		ownerScript = r.OwnerScript.Serialize()
+	}
+	if r.OwnerSignature != nil {
+		ownerSignature = types.PackBytes(r.OwnerSignature).AsSlice()
+	}
+	if r.RawExtensionData != nil {
+		rawExtensionData = SerializeScriptVec(r.RawExtensionData)
+	}
+	return serializeDynvec([][]byte{
+		ownerScript,
+		ownerSignature,
+		rawExtensionData,
+		types.PackBytesVec(r.ExtensionData).AsSlice(),
+	})
+}
+
+// SerializeScriptVec serializes scripts to molecule ScriptVec
+func SerializeScriptVec(scripts []*types.Script) []byte {
+	items := make([][]byte, 0, len(scripts))
+	for _, script := range scripts {
+		items = append(items, script.Serialize())
+	}
+	return serializeDynvec(items)
+}
+
+// DeserializeScriptVec deserializes molecule ScriptVec
+func DeserializeScriptVec(in []byte) ([]*types.Script, error) {
+	items, err := deserializeDynvec(in)
+	if err != nil {
+		return nil, err
+	}
+	scripts := make([]*types.Script, 0, len(items))
+	for _, item := range items {
+		script, err := types.DeserializeScript(item)
+		if err != nil {
+			return nil, err
+		}
+		scripts = append(scripts, script)
+	}
+	return scripts, nil
+}
+
+// serializeDynvec serializes items as molecule dynvec, which is also the layout of molecule table
+func serializeDynvec(items [][]byte) []byte {
+	headerSize := 4 + 4*len(items)
+	totalSize := headerSize
+	for _, item := range items {
+		totalSize += len(item)
+	}
+	out := make([]byte, headerSize, totalSize)
+	binary.LittleEndian.PutUint32(out[0:4], uint32(totalSize))
+	offset := headerSize
+	for i, item := range items {
+		binary.LittleEndian.PutUint32(out[4+4*i:8+4*i], uint32(offset))
+		offset += len(item)
+	}
+	for _, item := range items {
+		out = append(out, item...)
+	}
+	return out
+}
+
+func deserializeDynvec(in []byte) ([][]byte, error) {
+	if len(in) < 4 {
+		return nil, fmt.Errorf("invalid dynvec length %d", len(in))
+	}
+	totalSize := int(binary.LittleEndian.Uint32(in[0:4]))
+	if totalSize != len(in) {
+		return nil, fmt.Errorf("dynvec total size %d mismatches length %d", totalSize, len(in))
+	}
+	if totalSize == 4 {
+		return [][]byte{}, nil
+	}
+	if totalSize < 8 {
+		return nil, fmt.Errorf("invalid dynvec length %d", len(in))
+	}
+	headerSize := int(binary.LittleEndian.Uint32(in[4:8]))
+	if headerSize%4 != 0 || headerSize < 8 || headerSize > totalSize {
+		return nil, fmt.Errorf("invalid dynvec header size %d", headerSize)
+	}
+	count := headerSize/4 - 1
+	items := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		start := int(binary.LittleEndian.Uint32(in[4+4*i : 8+4*i]))
+		end := totalSize
+		if i+1 < count {
+			end = int(binary.LittleEndian.Uint32(in[8+4*i : 12+4*i]))
+		}
+		if start < headerSize || start > end || end > totalSize {
+			return nil, fmt.Errorf("invalid dynvec offset %d", start)
+		}
+		items = append(items, in[start:end])
+	}
+	return items, nil
+}
@@ -0,0 +1,64 @@
+package systemscript
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/blake2b"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var xudtOwnerLock = &types.Script{
+	CodeHash: types.HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+	HashType: types.HashTypeType,
+	Args:     common.FromHex("0xeac21ac6d373414aaa9ba34c469f805d48b62f86"),
+}
+
+func TestXudtArgs(t *testing.T) {
+	args := NewXudtArgs(xudtOwnerLock)
+	encoded := args.Encode()
+	assert.Equal(t, xudtOwnerLock.Hash().Bytes(), encoded)
+	decoded, err := DecodeToXudtArgs(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, args, decoded)
+
+	extension := &types.Script{CodeHash: types.HexToHash("0x01"), HashType: types.HashTypeData1, Args: []byte{1, 2}}
+	args.Flags = XudtFlagsExtensionScripts | XudtOwnerModeInputType
+	args.ExtensionScripts = []*types.Script{extension}
+	encoded = args.Encode()
+	assert.Equal(t, []byte{1, 0, 0, 0x80}, encoded[32:36])
+	decoded, err = DecodeToXudtArgs(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, args, decoded)
+
+	args.Flags = XudtFlagsExtensionScriptsHash
+	encoded = args.Encode()
+	assert.Equal(t, 56, len(encoded))
+	decoded, err = DecodeToXudtArgs(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, blake2b.Blake160(SerializeScriptVec(args.ExtensionScripts)), decoded.ExtensionScriptsHash)
+
+	_, err = DecodeToXudtArgs(append(xudtOwnerLock.Hash().Bytes(), 3, 0, 0, 0))
+	assert.Error(t, err)
+	_, err = DecodeToXudtArgs(encoded[:40])
+	assert.Error(t, err)
+}
+
+func TestScriptVec(t *testing.T) {
+	assert.Equal(t, []byte{4, 0, 0, 0}, SerializeScriptVec(nil))
+	scripts := []*types.Script{xudtOwnerLock, {CodeHash: types.HexToHash("0x01"), HashType: types.HashTypeData, Args: []byte{}}}
+	decoded, err := DeserializeScriptVec(SerializeScriptVec(scripts))
+	assert.NoError(t, err)
+	assert.Equal(t, scripts, decoded)
+}
+
+func TestXudtWitnessSerialize(t *testing.T) {
+	witness := &XudtWitness{}
+	// table header of 4 fields, 3 empty options and an empty BytesVec
+	assert.Equal(t, common.FromHex("0x180000001400000014000000140000001400000004000000"), witness.Serialize())
+
+	witness.ExtensionData = [][]byte{{1}}
+	serialized := witness.Serialize()
+	assert.Equal(t, uint32(len(serialized)), uint32(serialized[0]))
+	assert.Equal(t, types.PackBytesVec(witness.ExtensionData).AsSlice(), serialized[20:])
+}
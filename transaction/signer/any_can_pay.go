@@ -16,6 +16,10 @@ func (s *AnyCanPaySigner) SignTransaction(tx *types.Transaction, group *transact
 	}
 	if matched {
 		i0 := group.InputIndices[0]
+		// cells paid by others are unlocked without signature
+		if len(tx.Witnesses[i0]) == 0 {
+			return false, nil
+		}
 		signature, err := SignTransaction(tx, uint32ArrayToIntArray(group.InputIndices), tx.Witnesses[i0], ctx.Key)
 		if err != nil {
 			return false, err
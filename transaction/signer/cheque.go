@@ -0,0 +1,54 @@
+package signer
+
+import (
+	"bytes"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/blake2b"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+)
+
+// ChequeSigner signs cheque cells for receiver to claim or for sender to withdraw. The key matches when the lock hash
+// of its secp256k1_blake160_sighash_all lock matches receiver or sender lock hash in cheque args.
+type ChequeSigner struct {
+	network types.Network
+}
+
+func (s *ChequeSigner) SignTransaction(tx *types.Transaction, group *transaction.ScriptGroup, ctx *transaction.Context) (bool, error) {
+	if !IsChequeMatched(s.network, ctx.Key.PubKey(), group.Script.Args) {
+		return false, nil
+	}
+	i0 := group.InputIndices[0]
+	// cheque cells unlocked by lock hash don't have signature placeholder
+	if len(tx.Witnesses[i0]) == 0 {
+		return false, nil
+	}
+	witnessArgs, err := types.DeserializeWitnessArgs(tx.Witnesses[i0])
+	if err != nil {
+		return false, err
+	}
+	if len(witnessArgs.Lock) != 65 {
+		return false, nil
+	}
+	signature, err := SignTransaction(tx, uint32ArrayToIntArray(group.InputIndices), tx.Witnesses[i0], ctx.Key)
+	if err != nil {
+		return false, err
+	}
+	witnessArgs.Lock = signature
+	tx.Witnesses[i0] = witnessArgs.Serialize()
+	return true, nil
+}
+
+// IsChequeMatched checks whether the secp256k1_blake160_sighash_all lock of pubKey is the receiver or sender of cheque.
+func IsChequeMatched(network types.Network, pubKey []byte, chequeArgs []byte) bool {
+	if len(chequeArgs) != 40 {
+		return false
+	}
+	lock := &types.Script{
+		CodeHash: systemscript.GetCodeHash(network, systemscript.Secp256k1Blake160SighashAll),
+		HashType: types.HashTypeType,
+		Args:     blake2b.Blake160(pubKey),
+	}
+	lockHash := lock.Hash()
+	return bytes.Equal(lockHash[0:20], chequeArgs[0:20]) || bytes.Equal(lockHash[0:20], chequeArgs[20:40])
+}
@@ -5,19 +5,31 @@ import (
 	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
 )
 
-func init() {
-	networks := []types.Network{types.NetworkMain, types.NetworkTest}
-	for _, network := range networks {
-		instance := GetTransactionSignerInstance(network)
-		instance.RegisterLockSigner(
-			systemscript.GetCodeHash(network, systemscript.Secp256k1Blake160SighashAll), &Secp256k1Blake160SighashAllSigner{})
-		instance.RegisterLockSigner(
-			systemscript.GetCodeHash(network, systemscript.Secp256k1Blake160MultisigAll), &Secp256k1Blake160MultisigAllSigner{})
-		instance.RegisterLockSigner(
-			systemscript.GetCodeHash(network, systemscript.AnyoneCanPay), &AnyCanPaySigner{})
-		instance.RegisterLockSigner(
-			systemscript.GetCodeHash(network, systemscript.PwLock), &PWLockSigner{})
-		instance.RegisterLockSigner(
-			systemscript.GetCodeHash(network, systemscript.Omnilock), &OmnilockSigner{})
+// registerSystemScriptSigners registers signers of system scripts available on network. Signers registered by an
+// earlier call are replaced, unless they have been overridden by RegisterSigner.
+func registerSystemScriptSigners(instance *TransactionSigner, network types.Network) {
+	signers := map[systemscript.SystemScript]ScriptSigner{
+		systemscript.Secp256k1Blake160SighashAll:  &Secp256k1Blake160SighashAllSigner{},
+		systemscript.Secp256k1Blake160MultisigAll: &Secp256k1Blake160MultisigAllSigner{},
+		systemscript.AnyoneCanPay:                 &AnyCanPaySigner{},
+		systemscript.PwLock:                       &PWLockSigner{},
+		systemscript.Omnilock:                     &OmnilockSigner{},
+		systemscript.Cheque:                       &ChequeSigner{network: network},
+	}
+	for key := range instance.systemKeys {
+		delete(instance.signers, key)
+	}
+	instance.systemKeys = make(map[types.Hash]bool)
+	for script, signer := range signers {
+		info := systemscript.GetInfo(network, script)
+		if info == nil {
+			continue
+		}
+		key := hash(info.CodeHash, types.ScriptTypeLock)
+		if _, ok := instance.signers[key]; ok {
+			continue
+		}
+		instance.signers[key] = signer
+		instance.systemKeys[key] = true
 	}
 }
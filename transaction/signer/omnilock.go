@@ -2,7 +2,10 @@ package signer
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/blake2b"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/secp256k1"
@@ -10,6 +13,7 @@ import (
 	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction/signer/omnilock"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"strconv"
 )
 
 type OmnilockSigner struct {
@@ -23,6 +27,10 @@ func (s *OmnilockSigner) SignTransaction(transaction *types.Transaction, group *
 	if !bytes.Equal(group.Script.Args, config.Args.Encode()) {
 		return false, nil
 	}
+	// cells unlocked by anyone-can-pay rules don't need signature
+	if config.Mode == OmnolockModeAnyoneCanPay {
+		return false, nil
+	}
 	index := group.InputIndices[0]
 	witnesses := transaction.Witnesses
 	witnessArgs, err := types.DeserializeWitnessArgs(witnesses[index])
@@ -75,15 +83,53 @@ func signForAuthMode(tx *types.Transaction, group *transaction.ScriptGroup, key
 		}
 		omnilockWitnessLock.Signature = signature
 	case omnilock.AuthFlagEthereum:
-		return nil, fmt.Errorf("unsupported flag Ethereum")
+		pubKeyHash, err := omnilock.EthereumAuthContent(key.(*secp256k1.Secp256k1Key).PubKeyUncompressed())
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(pubKeyHash, authArgs) {
+			return nil, nil
+		}
+		omnilockWitnessLock.Signature = make([]byte, 65)
+		witnessArgs.Lock = make([]byte, len(omnilockWitnessLock.Serialize()))
+		witnessPlaceholder := witnessArgs.Serialize()
+		message, err := GenerateSighashAllMessage(tx, uint32ArrayToIntArray(group.InputIndices), witnessPlaceholder)
+		if err != nil {
+			return nil, err
+		}
+		signature, err := key.Sign(ethereumPersonalMessageHash(message))
+		if err != nil {
+			return nil, err
+		}
+		omnilockWitnessLock.Signature = signature
 	case omnilock.AuthFlagEOS:
 		return nil, fmt.Errorf("unsupported flag EOS")
 	case omnilock.AuthFlagTRON:
 		return nil, fmt.Errorf("unsupported flag TRON")
-	case omnilock.AuthFlagBitcoin:
-		return nil, fmt.Errorf("unsupported flag Bitcoin")
-	case omnilock.AuthFlagDogcoin:
-		return nil, fmt.Errorf("unsupported flag Dogecoin")
+	case omnilock.AuthFlagBitcoin, omnilock.AuthFlagDogcoin:
+		pubKeyHash, err := omnilock.BitcoinAuthContent(key.(*secp256k1.Secp256k1Key).PubKey())
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(pubKeyHash, authArgs) {
+			return nil, nil
+		}
+		omnilockWitnessLock.Signature = make([]byte, 65)
+		witnessArgs.Lock = make([]byte, len(omnilockWitnessLock.Serialize()))
+		witnessPlaceholder := witnessArgs.Serialize()
+		message, err := GenerateSighashAllMessage(tx, uint32ArrayToIntArray(group.InputIndices), witnessPlaceholder)
+		if err != nil {
+			return nil, err
+		}
+		magic := bitcoinMessageMagic
+		if config.Args.Authentication.Flag == omnilock.AuthFlagDogcoin {
+			magic = dogecoinMessageMagic
+		}
+		signature, err := key.Sign(bitcoinMessageHash(message, magic))
+		if err != nil {
+			return nil, err
+		}
+		omnilockWitnessLock.Signature = toBitcoinCompactSignature(signature)
 	case omnilock.AuthFlagCKBMultiSig:
 		multisigConfig := config.MultisigConfig
 		if multisigConfig == nil || !bytes.Equal(multisigConfig.Hash160(), authArgs) {
@@ -135,21 +181,75 @@ func signForAuthMode(tx *types.Transaction, group *transaction.ScriptGroup, key
 }
 
 func signForAdministratorMode(tx *types.Transaction, group *transaction.ScriptGroup, key crypto.Key, config *OmnilockConfiguration) (*omnilock.OmnilockWitnessLock, error) {
-	var signature []byte = nil
-	switch config.OmnilockIdentity.Identity.Flag {
+	if config.OmnilockIdentity == nil || config.OmnilockIdentity.Identity == nil {
+		return nil, fmt.Errorf("omnilock identity is required in administrator mode")
+	}
+	omnilockWitnessLock := new(omnilock.OmnilockWitnessLock)
+	omnilockWitnessLock.OmnilockIdentity = config.OmnilockIdentity
+	identity := config.OmnilockIdentity.Identity
+	switch identity.Flag {
 	case omnilock.OmnilockFlagCKBSecp256k1Blake160:
-		return nil, fmt.Errorf("unsupported flag CKB_Secp256k1_Blake160")
+		hash := blake2b.Blake160(key.(*secp256k1.Secp256k1Key).PubKey())
+		if !bytes.Equal(hash, identity.AuthContent) {
+			return nil, nil
+		}
+		firstWitness := tx.Witnesses[group.InputIndices[0]]
+		witnessArgs, err := types.DeserializeWitnessArgs(firstWitness)
+		if err != nil {
+			return nil, err
+		}
+		omnilockWitnessLock.Signature = make([]byte, 65)
+		witnessArgs.Lock = make([]byte, len(omnilockWitnessLock.Serialize()))
+		witnessPlaceholder := witnessArgs.Serialize()
+		signature, err := SignTransaction(tx, uint32ArrayToIntArray(group.InputIndices), witnessPlaceholder, key)
+		if err != nil {
+			return nil, err
+		}
+		omnilockWitnessLock.Signature = signature
 	case omnilock.OmnilockFlagLockScriptHash:
 		// Do nothing
 	default:
-		return nil, fmt.Errorf("unknown flag %d", config.OmnilockIdentity.Identity.Flag)
+		return nil, fmt.Errorf("unknown flag %d", identity.Flag)
 	}
-	omnilockWitnessLock := new(omnilock.OmnilockWitnessLock)
-	omnilockWitnessLock.OmnilockIdentity = config.OmnilockIdentity
-	omnilockWitnessLock.Signature = signature
 	return omnilockWitnessLock, nil
 }
 
+// ethereumPersonalMessageHash computes the digest signed by Ethereum personal_sign
+func ethereumPersonalMessageHash(message []byte) []byte {
+	prefix := []byte("\u0019Ethereum Signed Message:\n" + strconv.Itoa(len(message)))
+	return ethcrypto.Keccak256(append(prefix, message...))
+}
+
+const (
+	bitcoinMessageMagic  = "\u0018Bitcoin Signed Message:\n"
+	dogecoinMessageMagic = "\u0019Dogecoin Signed Message:\n"
+)
+
+// bitcoinMessageHash computes the digest signed by Bitcoin signmessage, where the message
+// is the hex string of the Omnilock message
+func bitcoinMessageHash(message []byte, magic string) []byte {
+	return bitcoinTextHash(hex.EncodeToString(message), magic)
+}
+
+// bitcoinTextHash computes the digest of text signed by Bitcoin signmessage, where text is shorter than 253 bytes
+func bitcoinTextHash(text string, magic string) []byte {
+	data := []byte(magic)
+	data = append(data, byte(len(text)))
+	data = append(data, text...)
+	hash := sha256.Sum256(data)
+	hash = sha256.Sum256(hash[:])
+	return hash[:]
+}
+
+// toBitcoinCompactSignature converts a recoverable signature in [R || S || V] format to the
+// Bitcoin compact format [header || R || S], where the header marks a compressed public key
+func toBitcoinCompactSignature(signature []byte) []byte {
+	out := make([]byte, 65)
+	out[0] = 27 + 4 + signature[64]
+	copy(out[1:], signature[:64])
+	return out
+}
+
 type OmnilockConfiguration struct {
 	Args             *omnilock.OmnilockArgs
 	Mode             OmnilockMode
@@ -163,4 +263,5 @@ type OmnilockMode uint
 const (
 	OmnolockModeAuth          OmnilockMode = 0
 	OmnolockModeAdministrator OmnilockMode = 1
+	OmnolockModeAnyoneCanPay  OmnilockMode = 2
 )
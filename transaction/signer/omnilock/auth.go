@@ -0,0 +1,111 @@
+package omnilock
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"github.com/ethereum/go-ethereum/crypto"
+	addr "github.com/nervosnetwork/ckb-sdk-go/v2/address"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// EthereumAuthContent returns the Ethereum address of the uncompressed secp256k1 public key,
+// which is used as auth content of AuthFlagEthereum.
+func EthereumAuthContent(pubKeyUncompressed []byte) ([]byte, error) {
+	if len(pubKeyUncompressed) != 65 || pubKeyUncompressed[0] != 0x04 {
+		return nil, fmt.Errorf("invalid uncompressed public key")
+	}
+	hash := crypto.Keccak256(pubKeyUncompressed[1:])
+	return hash[12:], nil
+}
+
+// NewEthereumAuthentication creates an Authentication of AuthFlagEthereum from the uncompressed public key.
+func NewEthereumAuthentication(pubKeyUncompressed []byte) (*Authentication, error) {
+	content, err := EthereumAuthContent(pubKeyUncompressed)
+	if err != nil {
+		return nil, err
+	}
+	authentication := &Authentication{Flag: AuthFlagEthereum}
+	copy(authentication.AuthContent[:], content)
+	return authentication, nil
+}
+
+// BitcoinAuthContent returns the P2PKH hash160 of the compressed secp256k1 public key,
+// which is used as auth content of AuthFlagBitcoin and AuthFlagDogcoin.
+func BitcoinAuthContent(pubKeyCompressed []byte) ([]byte, error) {
+	if len(pubKeyCompressed) != 33 || (pubKeyCompressed[0] != 0x02 && pubKeyCompressed[0] != 0x03) {
+		return nil, fmt.Errorf("invalid compressed public key")
+	}
+	hash := sha256.Sum256(pubKeyCompressed)
+	hasher := ripemd160.New()
+	hasher.Write(hash[:])
+	return hasher.Sum(nil), nil
+}
+
+// NewBitcoinAuthentication creates an Authentication of AuthFlagBitcoin from the compressed public key.
+func NewBitcoinAuthentication(pubKeyCompressed []byte) (*Authentication, error) {
+	return newBitcoinStyleAuthentication(AuthFlagBitcoin, pubKeyCompressed)
+}
+
+// NewDogecoinAuthentication creates an Authentication of AuthFlagDogcoin from the compressed public key.
+func NewDogecoinAuthentication(pubKeyCompressed []byte) (*Authentication, error) {
+	return newBitcoinStyleAuthentication(AuthFlagDogcoin, pubKeyCompressed)
+}
+
+func newBitcoinStyleAuthentication(flag AuthFlag, pubKeyCompressed []byte) (*Authentication, error) {
+	content, err := BitcoinAuthContent(pubKeyCompressed)
+	if err != nil {
+		return nil, err
+	}
+	authentication := &Authentication{Flag: flag}
+	copy(authentication.AuthContent[:], content)
+	return authentication, nil
+}
+
+// NewOmnilockAddress creates an Omnilock address with the authentication and no omni config enabled.
+func NewOmnilockAddress(network types.Network, authentication *Authentication) (*addr.Address, error) {
+	info := systemscript.GetInfo(network, systemscript.Omnilock)
+	if info == nil {
+		return nil, fmt.Errorf("omnilock is not deployed on network %d", network)
+	}
+	args := OmnilockArgs{
+		Authentication: authentication,
+		OmniConfig:     &OmniConfig{},
+	}
+	return &addr.Address{
+		Script: &types.Script{
+			CodeHash: info.CodeHash,
+			HashType: info.HashType,
+			Args:     args.Encode(),
+		},
+		Network: network,
+	}, nil
+}
+
+// NewEthereumOmnilockAddress creates an Omnilock address unlocked by the Ethereum key.
+func NewEthereumOmnilockAddress(network types.Network, pubKeyUncompressed []byte) (*addr.Address, error) {
+	authentication, err := NewEthereumAuthentication(pubKeyUncompressed)
+	if err != nil {
+		return nil, err
+	}
+	return NewOmnilockAddress(network, authentication)
+}
+
+// NewBitcoinOmnilockAddress creates an Omnilock address unlocked by the Bitcoin key.
+func NewBitcoinOmnilockAddress(network types.Network, pubKeyCompressed []byte) (*addr.Address, error) {
+	authentication, err := NewBitcoinAuthentication(pubKeyCompressed)
+	if err != nil {
+		return nil, err
+	}
+	return NewOmnilockAddress(network, authentication)
+}
+
+// NewDogecoinOmnilockAddress creates an Omnilock address unlocked by the Dogecoin key.
+func NewDogecoinOmnilockAddress(network types.Network, pubKeyCompressed []byte) (*addr.Address, error) {
+	authentication, err := NewDogecoinAuthentication(pubKeyCompressed)
+	if err != nil {
+		return nil, err
+	}
+	return NewOmnilockAddress(network, authentication)
+}
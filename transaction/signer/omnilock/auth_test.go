@@ -0,0 +1,34 @@
+package omnilock
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBitcoinAuthContent(t *testing.T) {
+	// public key of private key 0x01
+	pubKey := common.FromHex("0x0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	content, err := BitcoinAuthContent(pubKey)
+	assert.NoError(t, err)
+	assert.Equal(t, common.FromHex("0x751e76e8199196d454941c45d1b3a323f1433bd6"), content)
+
+	_, err = BitcoinAuthContent(pubKey[1:])
+	assert.Error(t, err)
+}
+
+func TestNewBitcoinOmnilockAddress(t *testing.T) {
+	pubKey := common.FromHex("0x0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	address, err := NewBitcoinOmnilockAddress(types.NetworkTest, pubKey)
+	assert.NoError(t, err)
+	assert.Equal(t, common.FromHex("0x04751e76e8199196d454941c45d1b3a323f1433bd600"), address.Script.Args)
+
+	address, err = NewDogecoinOmnilockAddress(types.NetworkTest, pubKey)
+	assert.NoError(t, err)
+	assert.Equal(t, common.FromHex("0x05751e76e8199196d454941c45d1b3a323f1433bd600"), address.Script.Args)
+
+	args, err := NewOmnilockArgsFromAgrs(address.Script.Args)
+	assert.NoError(t, err)
+	assert.Equal(t, AuthFlagDogcoin, args.Authentication.Flag)
+}
@@ -0,0 +1,82 @@
+package omnilock
+
+import (
+	"fmt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/smt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+)
+
+const (
+	RCRuleFlagEmergencyHalt byte = 0x1
+	RCRuleFlagWhiteList     byte = 0x2
+)
+
+// SmtProofMaskBoth marks that a proof is used for both input and output checks
+const SmtProofMaskBoth byte = 0x3
+
+// SmtValueExisting is the SMT value of an identity on the RC rule list
+var SmtValueExisting = types.Hash{1}
+
+// RCRule is the data of an administrator list cell, in which SmtRoot is the root of a sparse merkle tree
+// containing administrator identities.
+type RCRule struct {
+	SmtRoot types.Hash
+	Flags   byte
+}
+
+func NewWhiteListRCRule(smtRoot types.Hash) *RCRule {
+	return &RCRule{SmtRoot: smtRoot, Flags: RCRuleFlagWhiteList}
+}
+
+func (r *RCRule) IsWhiteList() bool {
+	return r.Flags&RCRuleFlagWhiteList != 0
+}
+
+func (r *RCRule) IsEmergencyHalt() bool {
+	return r.Flags&RCRuleFlagEmergencyHalt != 0
+}
+
+// Encode encodes rule as the molecule union RCData, which is the cell data of administrator list cell
+func (r *RCRule) Encode() []byte {
+	out := []byte{0, 0, 0, 0}
+	out = append(out, r.SmtRoot.Bytes()...)
+	out = append(out, r.Flags)
+	return out
+}
+
+func DecodeToRCRule(in []byte) (*RCRule, error) {
+	if len(in) != 37 {
+		return nil, fmt.Errorf("invalid RCRule data length %d", len(in))
+	}
+	if in[0] != 0 || in[1] != 0 || in[2] != 0 || in[3] != 0 {
+		return nil, fmt.Errorf("RCData is not RCRule")
+	}
+	return &RCRule{
+		SmtRoot: types.BytesToHash(in[4:36]),
+		Flags:   in[36],
+	}, nil
+}
+
+// SmtKey returns the key of identity in SMT, which is the identity padded with zero to 32 bytes
+func (a Auth) SmtKey() types.Hash {
+	var key types.Hash
+	copy(key[:], a.encode())
+	return key
+}
+
+// NewOmnilockIdentity creates an identity for administrator mode, with a proof for each RC rule tree.
+func NewOmnilockIdentity(identity *Auth, trees ...*smt.SparseMerkleTree) (*OmnilockIdentity, error) {
+	omnilockIdentity := &OmnilockIdentity{Identity: identity}
+	key := identity.SmtKey()
+	for _, tree := range trees {
+		proof, err := tree.MerkleProof(key)
+		if err != nil {
+			return nil, err
+		}
+		omnilockIdentity.Proofs = append(omnilockIdentity.Proofs, &SmtProofEntry{
+			Mask:     SmtProofMaskBoth,
+			SmtProof: proof,
+		})
+	}
+	return omnilockIdentity, nil
+}
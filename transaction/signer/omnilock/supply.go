@@ -0,0 +1,45 @@
+package omnilock
+
+import (
+	"fmt"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"math/big"
+)
+
+// SupplyInfo is the data of the info cell in Omnilock supply mode, whose type script hash is
+// OmniConfig.TypeScriptHashForSupply.
+type SupplyInfo struct {
+	Version        byte
+	CurrentSupply  *big.Int
+	MaxSupply      *big.Int
+	SudtScriptHash types.Hash
+}
+
+func DecodeToSupplyInfo(in []byte) (*SupplyInfo, error) {
+	if len(in) < 65 {
+		return nil, fmt.Errorf("supply info data at least should be 65 bytes")
+	}
+	currentSupply, err := systemscript.DecodeSudtAmount(in[1:17])
+	if err != nil {
+		return nil, err
+	}
+	maxSupply, err := systemscript.DecodeSudtAmount(in[17:33])
+	if err != nil {
+		return nil, err
+	}
+	return &SupplyInfo{
+		Version:        in[0],
+		CurrentSupply:  currentSupply,
+		MaxSupply:      maxSupply,
+		SudtScriptHash: types.BytesToHash(in[33:65]),
+	}, nil
+}
+
+func (s SupplyInfo) Encode() []byte {
+	out := []byte{s.Version}
+	out = append(out, systemscript.EncodeSudtAmount(s.CurrentSupply)...)
+	out = append(out, systemscript.EncodeSudtAmount(s.MaxSupply)...)
+	out = append(out, s.SudtScriptHash.Bytes()...)
+	return out
+}
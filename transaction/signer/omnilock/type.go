@@ -1,7 +1,6 @@
 package omnilock
 
 import (
-	"bytes"
 	"encoding/binary"
 	"fmt"
 	addr "github.com/nervosnetwork/ckb-sdk-go/v2/address"
@@ -48,7 +47,7 @@ type OmniConfig struct {
 	AdminListCellTypeId      [32]byte
 	MinimumCKBExponentInAcp  byte
 	MinimumSUDTExponentInAcp byte
-	SinceForTimeLock         uint8
+	SinceForTimeLock         uint64
 	TypeScriptHashForSupply  [32]byte
 }
 
@@ -68,38 +67,47 @@ func (o OmniConfig) IsSupplyModeEnabled() bool {
 	return (o.Flag & 0b1000) != 0
 }
 
+// DecodeToOmniConfig decodes omnilock flags and the optional fields following it. Each optional field
+// is present only when its mode is enabled, so the position of a field depends on the fields before it.
 func DecodeToOmniConfig(in []byte) (*OmniConfig, error) {
-	inLength := len(in)
+	if len(in) < 1 {
+		return nil, fmt.Errorf("byte array at least should be 1 byte")
+	}
 	omniConfig := new(OmniConfig)
 	omniConfig.Flag = in[0]
+	offset := 1
 	if omniConfig.IsAdminModeEnabled() {
-		if inLength < 33 {
-			return nil, fmt.Errorf("byte array at least should be 33 bytes when admin mode is enabled")
+		if len(in) < offset+32 {
+			return nil, fmt.Errorf("byte array is too short for admin list cell type id")
 		}
-		copy(omniConfig.AdminListCellTypeId[:], in[1:33])
+		copy(omniConfig.AdminListCellTypeId[:], in[offset:offset+32])
+		offset += 32
 	}
 	if omniConfig.IsAnyoneCanPayModeEnabled() {
-		if inLength < 34 {
-			return nil, fmt.Errorf("byte array at least should be 34 bytes when ACP mode is enabled")
+		if len(in) < offset+1 {
+			return nil, fmt.Errorf("byte array is too short for ACP minimum exponents")
 		}
-		omniConfig.MinimumCKBExponentInAcp = in[33]
+		omniConfig.MinimumCKBExponentInAcp = in[offset]
+		offset += 1
 		// It allows there is only minimumCKBExponentInAcp but not minimumSUDTExponentInAcp
-		if len(in) >= 35 {
-			omniConfig.MinimumSUDTExponentInAcp = in[34]
+		if len(in) > offset {
+			omniConfig.MinimumSUDTExponentInAcp = in[offset]
+			offset += 1
 		}
 	}
 	if omniConfig.IsTimeLockModeEnabled() {
-		if inLength < 43 {
-			return nil, fmt.Errorf("byte array at least should be 43 bytes when time-lock mode is enabled")
+		if len(in) < offset+8 {
+			return nil, fmt.Errorf("byte array is too short for time-lock since")
 		}
-		buf := bytes.NewBuffer(in[35:43])
-		binary.Read(buf, binary.BigEndian, &omniConfig.SinceForTimeLock)
+		omniConfig.SinceForTimeLock = binary.LittleEndian.Uint64(in[offset : offset+8])
+		offset += 8
 	}
 	if omniConfig.IsSupplyModeEnabled() {
-		if inLength < 75 {
-			return nil, fmt.Errorf("byte array at least should be 75 bytes when supply mode is enabled")
+		if len(in) < offset+32 {
+			return nil, fmt.Errorf("byte array is too short for supply type script hash")
 		}
-		copy(omniConfig.TypeScriptHashForSupply[:], in[43:75])
+		copy(omniConfig.TypeScriptHashForSupply[:], in[offset:offset+32])
+		offset += 32
 	}
 	return omniConfig, nil
 }
@@ -109,21 +117,15 @@ func (o OmniConfig) Encode() []byte {
 	out = append(out, o.Flag)
 	if o.IsAdminModeEnabled() {
 		out = append(out, o.AdminListCellTypeId[:]...)
-	} else {
-		return out
 	}
 	if o.IsAnyoneCanPayModeEnabled() {
 		out = append(out, o.MinimumCKBExponentInAcp)
 		out = append(out, o.MinimumSUDTExponentInAcp)
-	} else {
-		return out
 	}
 	if o.IsTimeLockModeEnabled() {
-		buf := new(bytes.Buffer)
-		binary.Write(buf, binary.BigEndian, o.SinceForTimeLock)
-		out = append(out, buf.Bytes()...)
-	} else {
-		return out
+		since := make([]byte, 8)
+		binary.LittleEndian.PutUint64(since, o.SinceForTimeLock)
+		out = append(out, since...)
 	}
 	if o.IsSupplyModeEnabled() {
 		out = append(out, o.TypeScriptHashForSupply[:]...)
@@ -15,7 +15,7 @@ func DeserializeOmnilockWitnessLock(in []byte) (*OmnilockWitnessLock, error) {
 func UnpackSmtProofEntry(v *molecule.SmtProofEntry) *SmtProofEntry {
 	return &SmtProofEntry{
 		Mask:     v.Mask().AsSlice()[0],
-		SmtProof: v.Mask().AsSlice(),
+		SmtProof: v.Proof().RawData(),
 	}
 }
 
@@ -91,6 +91,7 @@ func (o *OmnilockIdentity) PackOpt() *molecule.IdentityOpt {
 
 func (o *OmnilockIdentity) Pack() *molecule.Identity {
 	builder := molecule.NewIdentityBuilder()
+	builder.Identity(*o.Identity.Pack())
 	proofsBuilder := molecule.NewSmtProofEntryVecBuilder()
 	for _, p := range o.Proofs {
 		proofsBuilder.Push(*p.Pack())
@@ -100,6 +101,12 @@ func (o *OmnilockIdentity) Pack() *molecule.Identity {
 	return &b
 }
 
+func (a *Auth) Pack() *molecule.Auth {
+	b := make([]byte, 21)
+	copy(b, a.encode())
+	return molecule.AuthFromSliceUnchecked(b)
+}
+
 func (o *OmnilockWitnessLock) Pack() *molecule.OmniLockWitnessLock {
 	builder := molecule.NewOmniLockWitnessLockBuilder()
 	builder.Signature(*packBytesToOpt(o.Signature))
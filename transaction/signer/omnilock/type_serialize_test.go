@@ -41,3 +41,33 @@ func TestDeserializeOmnilockWitnessLock(t *testing.T) {
 	fmt.Println(os.Getenv("CI") == "")
 
 }
+
+func TestOmnilockIdentitySerialize(t *testing.T) {
+	witnessLock := &OmnilockWitnessLock{
+		Signature: make([]byte, 65),
+		OmnilockIdentity: &OmnilockIdentity{
+			Identity: &Auth{
+				Flag:        OmnilockFlagCKBSecp256k1Blake160,
+				AuthContent: hexutil.MustDecode("0x4049ed9cec8a0d39c7a1e899f0dacb8a8c28ad14"),
+			},
+			Proofs: []*SmtProofEntry{{Mask: SmtProofMaskBoth, SmtProof: hexutil.MustDecode("0x4c4f00")}},
+		},
+	}
+	decoded, err := DeserializeOmnilockWitnessLock(witnessLock.Serialize())
+	assert.NoError(t, err)
+	assert.Equal(t, witnessLock.OmnilockIdentity.Identity, decoded.OmnilockIdentity.Identity)
+	assert.Equal(t, SmtProofMaskBoth, decoded.OmnilockIdentity.Proofs[0].Mask)
+	assert.Equal(t, hexutil.MustDecode("0x4c4f00"), decoded.OmnilockIdentity.Proofs[0].SmtProof)
+	assert.Equal(t, witnessLock.Signature, decoded.Signature)
+}
+
+func TestRCRule(t *testing.T) {
+	rule := NewWhiteListRCRule(types.HexToHash("0xc5802e2652d3803eebcae40b65767279676a9d6d55e09b1958ab20c3e704a5e5"))
+	data := rule.Encode()
+	assert.Equal(t, "0x00000000c5802e2652d3803eebcae40b65767279676a9d6d55e09b1958ab20c3e704a5e502", hexutil.Encode(data))
+	decoded, err := DecodeToRCRule(data)
+	assert.NoError(t, err)
+	assert.True(t, decoded.IsWhiteList())
+	assert.False(t, decoded.IsEmergencyHalt())
+	assert.Equal(t, rule.SmtRoot, decoded.SmtRoot)
+}
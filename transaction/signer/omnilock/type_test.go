@@ -22,3 +22,31 @@ func TestNewOmnilockArgsFromAddress(t *testing.T) {
 	_, err = NewOmnilockArgsFromAddress("ckt1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq2qf8keemy2p5uu0g0gn8cd4ju23s5269qk8rg4r")
 	assert.Error(t, err)
 }
+
+func TestOmniConfig(t *testing.T) {
+	config := OmniConfig{
+		Flag:                     0b1111,
+		AdminListCellTypeId:      [32]byte{1},
+		MinimumCKBExponentInAcp:  8,
+		MinimumSUDTExponentInAcp: 2,
+		SinceForTimeLock:         0x2000000000000100,
+		TypeScriptHashForSupply:  [32]byte{2},
+	}
+	encoded := config.Encode()
+	assert.Equal(t, 1+32+2+8+32, len(encoded))
+	assert.Equal(t, []byte{0x00, 0x01, 0, 0, 0, 0, 0, 0x20}, encoded[35:43])
+	decoded, err := DecodeToOmniConfig(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, config, *decoded)
+
+	// fields are placed right after the flag when other modes are disabled
+	config = OmniConfig{Flag: 0b1100, SinceForTimeLock: 100, TypeScriptHashForSupply: [32]byte{2}}
+	encoded = config.Encode()
+	assert.Equal(t, 1+8+32, len(encoded))
+	decoded, err = DecodeToOmniConfig(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, config, *decoded)
+
+	_, err = DecodeToOmniConfig(encoded[:20])
+	assert.Error(t, err)
+}
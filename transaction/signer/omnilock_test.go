@@ -0,0 +1,39 @@
+package signer
+
+import (
+	"encoding/base64"
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction/signer/omnilock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// No committed Omnilock transaction of Ethereum auth is available to the tests, so the digest is checked against the
+// personal_sign vector of go-ethereum (accounts.TextHash).
+func TestEthereumPersonalMessageHash(t *testing.T) {
+	assert.Equal(t, common.FromHex("0xa080337ae51c4e064c189e113edd0ba391df9206e2f49db658bb32cf2911730b"),
+		ethereumPersonalMessageHash([]byte("Hello Joe")))
+}
+
+// The signature is the signmessage example of bitcoinjs-message, made by address 1F3sAm6ZtwLAUnj7d38pGFxtP3RVEvtsbV
+// with a compressed key. No such vector of Dogecoin is available, which differs only in magic.
+func TestBitcoinTextHash(t *testing.T) {
+	signature, err := base64.StdEncoding.DecodeString("H9L5yLFjti0QTHhPyFrZCT1V/MMnBtXKmoiKDZ78NDBjERki6ZTQZdSMCtkgoNmp17By9ItJr8o7ChX0XxY91nk=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := bitcoinTextHash("This is an example of a signed message.", bitcoinMessageMagic)
+	recoverable := append(append([]byte{}, signature[1:]...), signature[0]-31)
+	assert.Equal(t, signature, toBitcoinCompactSignature(recoverable))
+	pubKey, err := ethcrypto.SigToPub(digest, recoverable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authContent, err := omnilock.BitcoinAuthContent(ethcrypto.CompressPubkey(pubKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// hash160 of the address
+	assert.Equal(t, common.FromHex("0x9a1c78a507689f6f54b847ad1cef1e614ee23f1e"), authContent)
+}
@@ -56,6 +56,19 @@ func IsSingleSigMatched(key *secp256k1.Secp256k1Key, scriptArgs []byte) (bool, e
 
 // SignTransaction signs transaction with index group and witness placeholder in secp256k1_blake160_sighash_all way
 func SignTransaction(tx *types.Transaction, group []int, witnessPlaceholder []byte, key crypto.Key) ([]byte, error) {
+	msgHash, err := GenerateSighashAllMessage(tx, group, witnessPlaceholder)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := key.Sign(msgHash)
+	if err != nil {
+		return nil, err
+	}
+	return signature, nil
+}
+
+// GenerateSighashAllMessage generates the message to be signed in secp256k1_blake160_sighash_all way
+func GenerateSighashAllMessage(tx *types.Transaction, group []int, witnessPlaceholder []byte) ([]byte, error) {
 	inputsLen := len(tx.Inputs)
 	for i := 0; i < len(group); i++ {
 		if i > 0 && group[i] <= group[i-1] {
@@ -86,11 +99,5 @@ func SignTransaction(tx *types.Transaction, group []int, witnessPlaceholder []by
 		msg = append(msg, bytesLen...)
 		msg = append(msg, bytes...)
 	}
-
-	msgHash := blake2b.Blake256(msg)
-	signature, err := key.Sign(msgHash)
-	if err != nil {
-		return nil, err
-	}
-	return signature, nil
+	return blake2b.Blake256(msg), nil
 }
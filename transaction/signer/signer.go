@@ -3,8 +3,10 @@ package signer
 import (
 	"fmt"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/blake2b"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"sync"
 )
 
 type ScriptSigner interface {
@@ -13,28 +15,48 @@ type ScriptSigner interface {
 
 type TransactionSigner struct {
 	signers map[types.Hash]ScriptSigner
+	// systemKeys are the keys of signers registered for system scripts, which are refreshed on registry changes. A key
+	// is removed once its signer is overridden by RegisterSigner.
+	systemKeys      map[types.Hash]bool
+	registryVersion uint64
 }
 
 func NewTransactionSigner() *TransactionSigner {
 	return &TransactionSigner{signers: make(map[types.Hash]ScriptSigner)}
 }
 
-var testInstance = NewTransactionSigner()
-var mainInstance = NewTransactionSigner()
+var (
+	instancesMutex sync.Mutex
+	instances      = make(map[types.Network]*TransactionSigner)
+)
 
+// GetTransactionSignerInstance returns the shared signer of network, which signs system scripts available on network.
+// It returns nil for networks other than mainnet, testnet and those registered by systemscript.RegisterNetwork. Signers
+// of system scripts are refreshed after the registry changes, while signers registered by callers are kept.
 func GetTransactionSignerInstance(network types.Network) *TransactionSigner {
-	if network == types.NetworkTest {
-		return testInstance
-	} else if network == types.NetworkMain {
-		return mainInstance
-	} else {
+	instancesMutex.Lock()
+	defer instancesMutex.Unlock()
+	if !systemscript.IsKnownNetwork(network) {
+		delete(instances, network)
 		return nil
 	}
+	version := systemscript.RegistryVersion()
+	instance, ok := instances[network]
+	if !ok {
+		instance = NewTransactionSigner()
+		instances[network] = instance
+	} else if instance.systemKeys != nil && instance.registryVersion == version {
+		return instance
+	}
+	registerSystemScriptSigners(instance, network)
+	instance.registryVersion = version
+	return instance
 }
 
 func (r *TransactionSigner) RegisterSigner(codeHash types.Hash, scriptType types.ScriptType, signer ScriptSigner) {
 	hash := hash(codeHash, scriptType)
 	r.signers[hash] = signer
+	delete(r.systemKeys, hash)
 }
 
 func (r *TransactionSigner) RegisterTypeSigner(codeHash types.Hash, signer ScriptSigner) {
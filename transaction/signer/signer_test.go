@@ -0,0 +1,62 @@
+package signer
+
+import (
+	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// customSigner isn't zero-size, so that its pointers are distinct from those of system signers
+type customSigner struct {
+	name string
+}
+
+func (s *customSigner) SignTransaction(tx *types.Transaction, group *transaction.ScriptGroup, ctx *transaction.Context) (bool, error) {
+	return false, nil
+}
+
+func TestGetTransactionSignerInstanceRefresh(t *testing.T) {
+	devnet := types.Network(101)
+	secp := *systemscript.GetInfo(types.NetworkTest, systemscript.Secp256k1Blake160SighashAll)
+	systemscript.RegisterNetwork(devnet, map[systemscript.SystemScript]*systemscript.Info{
+		systemscript.Secp256k1Blake160SighashAll: &secp,
+	})
+	defer systemscript.UnregisterNetwork(devnet)
+
+	instance := GetTransactionSignerInstance(devnet)
+	assert.NotNil(t, instance)
+	assert.Equal(t, 1, len(instance.signers))
+	custom := &customSigner{name: "secp256k1"}
+	instance.RegisterLockSigner(secp.CodeHash, custom)
+
+	// scripts registered after the first call are signed, while the custom signer is kept
+	omnilock := *systemscript.GetInfo(types.NetworkTest, systemscript.Omnilock)
+	systemscript.RegisterInfo(devnet, systemscript.Omnilock, &omnilock)
+	assert.Same(t, instance, GetTransactionSignerInstance(devnet))
+	assert.Equal(t, 2, len(instance.signers))
+	assert.IsType(t, &OmnilockSigner{}, instance.signers[hash(omnilock.CodeHash, types.ScriptTypeLock)])
+	assert.Same(t, custom, instance.signers[hash(secp.CodeHash, types.ScriptTypeLock)])
+
+	// signers of replaced scripts are removed
+	systemscript.RegisterNetwork(devnet, map[systemscript.SystemScript]*systemscript.Info{
+		systemscript.Secp256k1Blake160SighashAll: &secp,
+	})
+	assert.Equal(t, 1, len(GetTransactionSignerInstance(devnet).signers))
+
+	// an override of the same type as the system signer is kept as well
+	omnilockKey := hash(omnilock.CodeHash, types.ScriptTypeLock)
+	instance.RegisterLockSigner(omnilock.CodeHash, &OmnilockSigner{})
+	systemscript.RegisterInfo(devnet, systemscript.Omnilock, &omnilock)
+	assert.Same(t, instance, GetTransactionSignerInstance(devnet))
+	assert.False(t, instance.systemKeys[omnilockKey])
+	systemscript.RegisterNetwork(devnet, map[systemscript.SystemScript]*systemscript.Info{
+		systemscript.Secp256k1Blake160SighashAll: &secp,
+	})
+	assert.Equal(t, 2, len(GetTransactionSignerInstance(devnet).signers))
+	assert.IsType(t, &OmnilockSigner{}, instance.signers[omnilockKey])
+
+	systemscript.UnregisterNetwork(devnet)
+	assert.Nil(t, GetTransactionSignerInstance(devnet))
+}
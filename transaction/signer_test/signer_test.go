@@ -1,12 +1,17 @@
 package signer_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/blake2b"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/secp256k1"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/crypto/smt"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/systemscript"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction/signer"
@@ -78,6 +83,10 @@ func TestOmnilockSigner(t *testing.T) {
 	testSignAndCheck(t, "omnilock_secp256k1_blake160_sighash_all.json")
 	testSignAndCheck(t, "omnilock_secp256k1_blake160_multisig_all_first.json")
 	testSignAndCheck(t, "omnilock_secp256k1_blake160_multisig_all_second.json")
+	testSignAndCheck(t, "omnilock_ethereum.json")
+	testSignAndCheck(t, "omnilock_bitcoin.json")
+	testSignAndCheck(t, "omnilock_dogecoin.json")
+	testSignAndCheck(t, "omnilock_administrator.json")
 }
 
 func testSignAndCheck(t *testing.T, fileName string) {
@@ -168,6 +177,9 @@ func (r *signerChecker) UnmarshalJSON(input []byte) error {
 			if val, ok := v["multisig_script"]; ok {
 				config.MultisigConfig = unmarshalMultisigConfig(val.(map[string]interface{}))
 			}
+			if val, ok := v["omnilock_identity"]; ok {
+				config.OmnilockIdentity = unmarshalOmnilockIdentity(val.(map[string]interface{}))
+			}
 			ctx.Payload = config
 		}
 		r.Contexts = append(r.Contexts, ctx)
@@ -175,6 +187,24 @@ func (r *signerChecker) UnmarshalJSON(input []byte) error {
 	return nil
 }
 
+func unmarshalOmnilockIdentity(input map[string]interface{}) *omnilock.OmnilockIdentity {
+	identity := hexutil.MustDecode(input["identity"].(string))
+	omnilockIdentity := &omnilock.OmnilockIdentity{
+		Identity: &omnilock.Auth{
+			Flag:        omnilock.OmnilockFlag(identity[0]),
+			AuthContent: identity[1:],
+		},
+	}
+	for _, p := range input["proofs"].([]interface{}) {
+		proof := p.(map[string]interface{})
+		omnilockIdentity.Proofs = append(omnilockIdentity.Proofs, &omnilock.SmtProofEntry{
+			Mask:     byte(proof["mask"].(float64)),
+			SmtProof: hexutil.MustDecode(proof["proof"].(string)),
+		})
+	}
+	return omnilockIdentity
+}
+
 func unmarshalMultisigConfig(input map[string]interface{}) *systemscript.MultisigConfig {
 	config := systemscript.NewMultisigConfig(byte(input["first_n"].(float64)),
 		byte(input["threshold"].(float64)))
@@ -183,3 +213,107 @@ func unmarshalMultisigConfig(input map[string]interface{}) *systemscript.Multisi
 	}
 	return config
 }
+
+// The expected witnesses of omnilock_ethereum.json are a snapshot of this signer, not the witness of a committed
+// transaction, and the digest below is rebuilt with the same formula. They pin the current output only, and should be
+// replaced by the witness of a committed Ethereum-auth Omnilock transaction or a ckb-auth vector.
+func TestOmnilockEthereumSignatureRecovery(t *testing.T) {
+	message, signature, authContent := signOmnilockFixture(t, "omnilock_ethereum.json")
+	digest := ethcrypto.Keccak256(append([]byte("\x19Ethereum Signed Message:\n32"), message...))
+	pubKey, err := ethcrypto.Ecrecover(digest, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, authContent, ethcrypto.Keccak256(pubKey[1:])[12:])
+}
+
+// The expected witnesses of omnilock_bitcoin.json and omnilock_dogecoin.json are snapshots of this signer, and the
+// digest below is rebuilt with the same magic and hex encoding of the message. Whether the contract verifies that
+// digest isn't proved until they are replaced by witnesses of committed BTC-auth and DOGE-auth Omnilock transactions
+// or ckb-auth vectors.
+func TestOmnilockBitcoinSignatureRecovery(t *testing.T) {
+	testBitcoinStyleSignatureRecovery(t, "omnilock_bitcoin.json", "\x18Bitcoin Signed Message:\n")
+	testBitcoinStyleSignatureRecovery(t, "omnilock_dogecoin.json", "\x19Dogecoin Signed Message:\n")
+}
+
+func testBitcoinStyleSignatureRecovery(t *testing.T, fileName string, magic string) {
+	message, signature, authContent := signOmnilockFixture(t, fileName)
+	data := append([]byte(magic), 64)
+	data = append(data, hex.EncodeToString(message)...)
+	digest := sha256.Sum256(data)
+	digest = sha256.Sum256(digest[:])
+
+	// compact signature header is 27 + 4 (compressed) + recovery id
+	assert.True(t, signature[0] >= 31 && signature[0] <= 34)
+	recoverable := append(append([]byte{}, signature[1:]...), signature[0]-31)
+	pubKey, err := ethcrypto.SigToPub(digest[:], recoverable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := omnilock.BitcoinAuthContent(ethcrypto.CompressPubkey(pubKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, authContent, expected)
+}
+
+func TestOmnilockAdministratorSignatureRecovery(t *testing.T) {
+	message, witnessLock, _ := signOmnilockFixtureWitness(t, "omnilock_administrator.json")
+	pubKey, err := ethcrypto.SigToPub(message, witnessLock.Signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity := witnessLock.OmnilockIdentity.Identity
+	assert.Equal(t, omnilock.OmnilockFlagCKBSecp256k1Blake160, identity.Flag)
+	assert.Equal(t, identity.AuthContent, blake2b.Blake160(ethcrypto.CompressPubkey(pubKey)))
+
+	// the identity is proved to be in the SMT of RC rule
+	root := types.HexToHash("0xc5802e2652d3803eebcae40b65767279676a9d6d55e09b1958ab20c3e704a5e5")
+	assert.Equal(t, 1, len(witnessLock.OmnilockIdentity.Proofs))
+	proof := smt.CompiledMerkleProof(witnessLock.OmnilockIdentity.Proofs[0].SmtProof)
+	ok, err := proof.Verify(root, []smt.Leaf{{Key: identity.SmtKey(), Value: omnilock.SmtValueExisting}})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+// signOmnilockFixture signs the fixture, and returns the Omnilock message recomputed from the
+// unsigned transaction, the signature in witness and the auth content in script args
+func signOmnilockFixture(t *testing.T, fileName string) ([]byte, []byte, []byte) {
+	message, witnessLock, args := signOmnilockFixtureWitness(t, fileName)
+	return message, witnessLock.Signature, args[1:21]
+}
+
+func signOmnilockFixtureWitness(t *testing.T, fileName string) ([]byte, *omnilock.OmnilockWitnessLock, []byte) {
+	checker, err := fromFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := checker.Transaction
+	group := tx.ScriptGroups[0]
+	unsigned := append([][]byte{}, tx.TxView.Witnesses...)
+	_, err = signer.GetTransactionSignerInstance(types.NetworkTest).SignTransaction(tx, checker.Contexts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := tx.TxView.Witnesses
+
+	var indices []int
+	for _, i := range group.InputIndices {
+		indices = append(indices, int(i))
+	}
+	tx.TxView.Witnesses = unsigned
+	message, err := signer.GenerateSighashAllMessage(tx.TxView, indices, unsigned[group.InputIndices[0]])
+	tx.TxView.Witnesses = signed
+	if err != nil {
+		t.Fatal(err)
+	}
+	witnessArgs, err := types.DeserializeWitnessArgs(signed[group.InputIndices[0]])
+	if err != nil {
+		t.Fatal(err)
+	}
+	witnessLock, err := omnilock.DeserializeOmnilockWitnessLock(witnessArgs.Lock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return message, witnessLock, group.Script.Args
+}
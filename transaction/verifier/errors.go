@@ -0,0 +1,162 @@
+package verifier
+
+import (
+	"fmt"
+)
+
+// UnresolvedInputError is returned when the cell spent by an input can't be resolved.
+type UnresolvedInputError struct {
+	Index int
+	Err   error
+}
+
+func (e *UnresolvedInputError) Error() string {
+	return fmt.Sprintf("input %d: unresolved cell: %v", e.Index, e.Err)
+}
+
+func (e *UnresolvedInputError) Unwrap() error {
+	return e.Err
+}
+
+// DuplicateInputError is returned when two inputs spend the same cell.
+type DuplicateInputError struct {
+	Index    int
+	Previous int
+}
+
+func (e *DuplicateInputError) Error() string {
+	return fmt.Sprintf("input %d: spends the same cell as input %d", e.Index, e.Previous)
+}
+
+// UnresolvedCellDepError is returned when a cell dep, or a member of a dep group, can't be resolved.
+// GroupIndex is -1 unless the failing out point is a member of a dep group.
+type UnresolvedCellDepError struct {
+	Index      int
+	GroupIndex int
+	Err        error
+}
+
+func (e *UnresolvedCellDepError) Error() string {
+	if e.GroupIndex >= 0 {
+		return fmt.Sprintf("cell dep %d: unresolved dep group member %d: %v", e.Index, e.GroupIndex, e.Err)
+	}
+	return fmt.Sprintf("cell dep %d: unresolved cell: %v", e.Index, e.Err)
+}
+
+func (e *UnresolvedCellDepError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidDepGroupError is returned when the data of a dep group cell is not a valid OutPointVec.
+type InvalidDepGroupError struct {
+	Index int
+}
+
+func (e *InvalidDepGroupError) Error() string {
+	return fmt.Sprintf("cell dep %d: invalid dep group data", e.Index)
+}
+
+// UnresolvedHeaderDepError is returned when a header dep can't be resolved.
+type UnresolvedHeaderDepError struct {
+	Index int
+	Err   error
+}
+
+func (e *UnresolvedHeaderDepError) Error() string {
+	return fmt.Sprintf("header dep %d: unresolved header: %v", e.Index, e.Err)
+}
+
+func (e *UnresolvedHeaderDepError) Unwrap() error {
+	return e.Err
+}
+
+// OutputsDataMismatchError is returned when outputs and outputs_data have different lengths.
+type OutputsDataMismatchError struct {
+	Outputs     int
+	OutputsData int
+}
+
+func (e *OutputsDataMismatchError) Error() string {
+	return fmt.Sprintf("outputs length %d doesn't match outputs data length %d", e.Outputs, e.OutputsData)
+}
+
+// WitnessCountError is returned when there are fewer witnesses than inputs.
+type WitnessCountError struct {
+	Inputs    int
+	Witnesses int
+}
+
+func (e *WitnessCountError) Error() string {
+	return fmt.Sprintf("witnesses length %d is less than inputs length %d", e.Witnesses, e.Inputs)
+}
+
+// InsufficientOutputCapacityError is returned when an output holds less capacity than it occupies.
+type InsufficientOutputCapacityError struct {
+	Index    int
+	Capacity uint64
+	Occupied uint64
+}
+
+func (e *InsufficientOutputCapacityError) Error() string {
+	return fmt.Sprintf("output %d: capacity %d is less than occupied capacity %d", e.Index, e.Capacity, e.Occupied)
+}
+
+// CapacityOverflowError is returned when the sum of input or output capacities overflows uint64.
+type CapacityOverflowError struct {
+	Outputs bool
+}
+
+func (e *CapacityOverflowError) Error() string {
+	if e.Outputs {
+		return "outputs capacity overflow"
+	}
+	return "inputs capacity overflow"
+}
+
+// UnbalancedCapacityError is returned when outputs hold more capacity than inputs provide.
+type UnbalancedCapacityError struct {
+	InputsCapacity  uint64
+	OutputsCapacity uint64
+}
+
+func (e *UnbalancedCapacityError) Error() string {
+	return fmt.Sprintf("outputs capacity %d exceeds inputs capacity %d", e.OutputsCapacity, e.InputsCapacity)
+}
+
+// InvalidSinceError is returned when the since field of an input is malformed.
+type InvalidSinceError struct {
+	Index int
+	Since uint64
+}
+
+func (e *InvalidSinceError) Error() string {
+	return fmt.Sprintf("input %d: invalid since %#x", e.Index, e.Since)
+}
+
+// ImmatureSinceError is returned when the since condition of an input is not satisfied yet,
+// or can't be checked because the header of the input cell is unknown.
+type ImmatureSinceError struct {
+	Index int
+	Since uint64
+}
+
+func (e *ImmatureSinceError) Error() string {
+	return fmt.Sprintf("input %d: immature since %#x", e.Index, e.Since)
+}
+
+// ScriptGroupError is returned when a script group references an out-of-range index,
+// or an input/output whose script doesn't belong to the group.
+type ScriptGroupError struct {
+	GroupIndex int
+	Output     bool
+	Index      int
+	Reason     string
+}
+
+func (e *ScriptGroupError) Error() string {
+	target := "input"
+	if e.Output {
+		target = "output"
+	}
+	return fmt.Sprintf("script group %d: %s %d: %s", e.GroupIndex, target, e.Index, e.Reason)
+}
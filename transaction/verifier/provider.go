@@ -0,0 +1,57 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+)
+
+// ResolvedCell is a live cell referenced by a transaction input or cell dep.
+type ResolvedCell struct {
+	Output *types.CellOutput
+	Data   []byte
+	// Header of the block that committed the cell. It is only required for
+	// inputs with a relative since.
+	Header *types.Header
+}
+
+// CellProvider resolves out points and header hashes referenced by a transaction.
+type CellProvider interface {
+	GetCell(outPoint *types.OutPoint) (*ResolvedCell, error)
+	GetHeader(hash types.Hash) (*types.Header, error)
+}
+
+// MemoryCellProvider is a CellProvider backed by in-memory maps, mostly useful in tests.
+type MemoryCellProvider struct {
+	cells   map[types.OutPoint]*ResolvedCell
+	headers map[types.Hash]*types.Header
+}
+
+func NewMemoryCellProvider() *MemoryCellProvider {
+	return &MemoryCellProvider{
+		cells:   make(map[types.OutPoint]*ResolvedCell),
+		headers: make(map[types.Hash]*types.Header),
+	}
+}
+
+func (r *MemoryCellProvider) AddCell(outPoint *types.OutPoint, cell *ResolvedCell) {
+	r.cells[*outPoint] = cell
+}
+
+func (r *MemoryCellProvider) AddHeader(header *types.Header) {
+	r.headers[header.Hash] = header
+}
+
+func (r *MemoryCellProvider) GetCell(outPoint *types.OutPoint) (*ResolvedCell, error) {
+	if cell, ok := r.cells[*outPoint]; ok {
+		return cell, nil
+	}
+	return nil, fmt.Errorf("cell %s:%d not found", outPoint.TxHash, outPoint.Index)
+}
+
+func (r *MemoryCellProvider) GetHeader(hash types.Hash) (*types.Header, error) {
+	if header, ok := r.headers[hash]; ok {
+		return header, nil
+	}
+	return nil, fmt.Errorf("header %s not found", hash)
+}
@@ -0,0 +1,266 @@
+// Package verifier checks the structural rules of a transaction locally, without a CKB node.
+//
+// Scripts are not executed: the verifier only catches the kind of mistakes a transaction
+// builder can make, such as unbalanced capacity, missing witnesses, unresolvable deps or
+// immature since values.
+package verifier
+
+import (
+	"math/big"
+
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+)
+
+const (
+	sinceRelativeFlag = uint64(0x8000000000000000)
+	sinceMetricMask   = uint64(0x6000000000000000)
+	sinceReservedMask = uint64(0x1f00000000000000)
+	sinceValueMask    = uint64(0x00ffffffffffffff)
+
+	sinceMetricBlockNumber = uint64(0x0000000000000000)
+	sinceMetricEpochNumber = uint64(0x2000000000000000)
+	sinceMetricTimestamp   = uint64(0x4000000000000000)
+)
+
+// ChainState describes the chain the transaction is expected to be committed on.
+// Since values are checked against it.
+type ChainState struct {
+	TipNumber uint64
+	// TipEpoch is the epoch of the tip block, encoded as epoch number with fraction
+	TipEpoch uint64
+	// MedianTimestamp is the median time of the tip block in milliseconds
+	MedianTimestamp uint64
+}
+
+type Verifier struct {
+	Provider CellProvider
+	// Chain is optional, since values are not checked if it is nil
+	Chain *ChainState
+}
+
+func NewVerifier(provider CellProvider, chain *ChainState) *Verifier {
+	return &Verifier{
+		Provider: provider,
+		Chain:    chain,
+	}
+}
+
+// Verify checks tx and its script groups, and returns the first violated rule.
+func (r *Verifier) Verify(tx *transaction.TransactionWithScriptGroups) error {
+	inputs, err := r.verifyTransaction(tx.TxView)
+	if err != nil {
+		return err
+	}
+	return verifyScriptGroups(tx.TxView, inputs, tx.ScriptGroups)
+}
+
+// VerifyTransaction checks tx and returns the first violated rule.
+//
+// The capacity balance check doesn't take DAO withdrawal compensation into account.
+func (r *Verifier) VerifyTransaction(tx *types.Transaction) error {
+	_, err := r.verifyTransaction(tx)
+	return err
+}
+
+func (r *Verifier) verifyTransaction(tx *types.Transaction) ([]*ResolvedCell, error) {
+	if len(tx.Outputs) != len(tx.OutputsData) {
+		return nil, &OutputsDataMismatchError{Outputs: len(tx.Outputs), OutputsData: len(tx.OutputsData)}
+	}
+	if len(tx.Witnesses) < len(tx.Inputs) {
+		return nil, &WitnessCountError{Inputs: len(tx.Inputs), Witnesses: len(tx.Witnesses)}
+	}
+	inputs, err := r.resolveInputs(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.resolveCellDeps(tx); err != nil {
+		return nil, err
+	}
+	for i, hash := range tx.HeaderDeps {
+		if _, err := r.Provider.GetHeader(hash); err != nil {
+			return nil, &UnresolvedHeaderDepError{Index: i, Err: err}
+		}
+	}
+	if err = verifyCapacity(tx, inputs); err != nil {
+		return nil, err
+	}
+	if r.Chain != nil {
+		for i, input := range tx.Inputs {
+			if err = r.verifySince(i, input.Since, inputs[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return inputs, nil
+}
+
+func (r *Verifier) resolveInputs(tx *types.Transaction) ([]*ResolvedCell, error) {
+	inputs := make([]*ResolvedCell, len(tx.Inputs))
+	spent := make(map[types.OutPoint]int)
+	for i, input := range tx.Inputs {
+		if previous, ok := spent[*input.PreviousOutput]; ok {
+			return nil, &DuplicateInputError{Index: i, Previous: previous}
+		}
+		spent[*input.PreviousOutput] = i
+		cell, err := r.Provider.GetCell(input.PreviousOutput)
+		if err != nil {
+			return nil, &UnresolvedInputError{Index: i, Err: err}
+		}
+		inputs[i] = cell
+	}
+	return inputs, nil
+}
+
+func (r *Verifier) resolveCellDeps(tx *types.Transaction) error {
+	for i, cellDep := range tx.CellDeps {
+		cell, err := r.Provider.GetCell(cellDep.OutPoint)
+		if err != nil {
+			return &UnresolvedCellDepError{Index: i, GroupIndex: -1, Err: err}
+		}
+		if cellDep.DepType != types.DepTypeDepGroup {
+			continue
+		}
+		outPoints, err := types.DeserializeOutPointVec(cell.Data)
+		if err != nil {
+			return &InvalidDepGroupError{Index: i}
+		}
+		for j, outPoint := range outPoints {
+			if _, err := r.Provider.GetCell(outPoint); err != nil {
+				return &UnresolvedCellDepError{Index: i, GroupIndex: j, Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+func verifyCapacity(tx *types.Transaction, inputs []*ResolvedCell) error {
+	var inputsCapacity, outputsCapacity uint64
+	for _, cell := range inputs {
+		inputsCapacity += cell.Output.Capacity
+		if inputsCapacity < cell.Output.Capacity {
+			return &CapacityOverflowError{Outputs: false}
+		}
+	}
+	for i, output := range tx.Outputs {
+		occupied := output.OccupiedCapacity(tx.OutputsData[i])
+		if output.Capacity < occupied {
+			return &InsufficientOutputCapacityError{Index: i, Capacity: output.Capacity, Occupied: occupied}
+		}
+		outputsCapacity += output.Capacity
+		if outputsCapacity < output.Capacity {
+			return &CapacityOverflowError{Outputs: true}
+		}
+	}
+	if outputsCapacity > inputsCapacity {
+		return &UnbalancedCapacityError{InputsCapacity: inputsCapacity, OutputsCapacity: outputsCapacity}
+	}
+	return nil
+}
+
+func (r *Verifier) verifySince(index int, since uint64, cell *ResolvedCell) error {
+	if since == 0 {
+		return nil
+	}
+	if since&sinceReservedMask != 0 || since&sinceMetricMask == sinceMetricMask {
+		return &InvalidSinceError{Index: index, Since: since}
+	}
+	relative := since&sinceRelativeFlag != 0
+	metric := since & sinceMetricMask
+	value := since & sinceValueMask
+	if relative && cell.Header == nil {
+		return &ImmatureSinceError{Index: index, Since: since}
+	}
+
+	var mature bool
+	switch metric {
+	case sinceMetricBlockNumber:
+		if relative {
+			value += cell.Header.Number
+		}
+		mature = r.Chain.TipNumber >= value
+	case sinceMetricEpochNumber:
+		target, ok := epochToRat(value)
+		if !ok {
+			return &InvalidSinceError{Index: index, Since: since}
+		}
+		if relative {
+			start, ok := epochToRat(cell.Header.Epoch)
+			if !ok {
+				return &ImmatureSinceError{Index: index, Since: since}
+			}
+			target.Add(target, start)
+		}
+		tip, ok := epochToRat(r.Chain.TipEpoch)
+		if !ok {
+			return &ImmatureSinceError{Index: index, Since: since}
+		}
+		mature = tip.Cmp(target) >= 0
+	case sinceMetricTimestamp:
+		// since timestamps are in seconds while header timestamps are in milliseconds.
+		// The header timestamp is used in place of the median time of the committing block,
+		// which makes the relative check slightly stricter than the node's.
+		target := value * 1000
+		if relative {
+			target += cell.Header.Timestamp
+		}
+		mature = r.Chain.MedianTimestamp >= target
+	}
+	if !mature {
+		return &ImmatureSinceError{Index: index, Since: since}
+	}
+	return nil
+}
+
+// epochToRat converts an epoch number with fraction to a rational number.
+func epochToRat(epoch uint64) (*big.Rat, bool) {
+	params := types.ParseEpoch(epoch)
+	if params.Length == 0 {
+		if params.Index != 0 {
+			return nil, false
+		}
+		return new(big.Rat).SetInt64(int64(params.Number)), true
+	}
+	if params.Index >= params.Length {
+		return nil, false
+	}
+	fraction := big.NewRat(int64(params.Index), int64(params.Length))
+	return fraction.Add(fraction, new(big.Rat).SetInt64(int64(params.Number))), true
+}
+
+func verifyScriptGroups(tx *types.Transaction, inputs []*ResolvedCell, groups []*transaction.ScriptGroup) error {
+	for i, group := range groups {
+		scriptHash := group.Script.Hash()
+		for _, index := range group.InputIndices {
+			if int(index) >= len(inputs) {
+				return &ScriptGroupError{GroupIndex: i, Index: int(index), Reason: "index out of range"}
+			}
+			output := inputs[index].Output
+			if !belongsToGroup(output, group.GroupType, scriptHash) {
+				return &ScriptGroupError{GroupIndex: i, Index: int(index), Reason: "script doesn't match"}
+			}
+		}
+		for _, index := range group.OutputIndices {
+			if int(index) >= len(tx.Outputs) {
+				return &ScriptGroupError{GroupIndex: i, Output: true, Index: int(index), Reason: "index out of range"}
+			}
+			if group.GroupType == types.ScriptTypeLock {
+				return &ScriptGroupError{GroupIndex: i, Output: true, Index: int(index), Reason: "lock script group can't contain outputs"}
+			}
+			if !belongsToGroup(tx.Outputs[index], group.GroupType, scriptHash) {
+				return &ScriptGroupError{GroupIndex: i, Output: true, Index: int(index), Reason: "script doesn't match"}
+			}
+		}
+	}
+	return nil
+}
+
+func belongsToGroup(output *types.CellOutput, scriptType types.ScriptType, scriptHash types.Hash) bool {
+	var script *types.Script
+	if scriptType == types.ScriptTypeLock {
+		script = output.Lock
+	} else {
+		script = output.Type
+	}
+	return script != nil && script.Hash() == scriptHash
+}
@@ -0,0 +1,189 @@
+package verifier
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/nervosnetwork/ckb-sdk-go/v2/transaction"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/nervosnetwork/ckb-sdk-go/v2/types/numeric"
+	"github.com/stretchr/testify/assert"
+)
+
+var lock = &types.Script{
+	CodeHash: types.HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+	HashType: types.HashTypeType,
+	Args:     make([]byte, 20),
+}
+
+var cellHeader = &types.Header{
+	Hash:      types.HexToHash("0x01"),
+	Number:    100,
+	Epoch:     (&types.EpochParams{Length: 1000, Index: 500, Number: 10}).Uint64(),
+	Timestamp: 1600000000000,
+}
+
+func outPoint(b byte, index uint32) *types.OutPoint {
+	return &types.OutPoint{TxHash: types.BytesToHash([]byte{b}), Index: index}
+}
+
+func newTestCase() (*MemoryCellProvider, *types.Transaction) {
+	provider := NewMemoryCellProvider()
+	provider.AddCell(outPoint(1, 0), &ResolvedCell{
+		Output: &types.CellOutput{Capacity: 20000000000, Lock: lock},
+		Data:   []byte{},
+		Header: cellHeader,
+	})
+	provider.AddCell(outPoint(2, 0), &ResolvedCell{
+		Output: &types.CellOutput{Capacity: 20000000000, Lock: lock},
+		Data:   []byte{},
+	})
+	provider.AddHeader(cellHeader)
+	tx := &types.Transaction{
+		CellDeps:   []*types.CellDep{{OutPoint: outPoint(2, 0), DepType: types.DepTypeCode}},
+		HeaderDeps: []types.Hash{cellHeader.Hash},
+		Inputs:     []*types.CellInput{{PreviousOutput: outPoint(1, 0)}},
+		Outputs: []*types.CellOutput{
+			{Capacity: 6100000000, Lock: lock},
+			{Capacity: 13899999000, Lock: lock},
+		},
+		OutputsData: [][]byte{{}, {}},
+		Witnesses:   [][]byte{{}},
+	}
+	return provider, tx
+}
+
+func TestVerifyTransaction(t *testing.T) {
+	provider, tx := newTestCase()
+	verifier := NewVerifier(provider, nil)
+	assert.NoError(t, verifier.VerifyTransaction(tx))
+}
+
+func TestVerifyWithScriptGroups(t *testing.T) {
+	provider, tx := newTestCase()
+	verifier := NewVerifier(provider, nil)
+	group := &transaction.ScriptGroup{Script: lock, GroupType: types.ScriptTypeLock, InputIndices: []uint32{0}}
+	txWithGroups := &transaction.TransactionWithScriptGroups{TxView: tx, ScriptGroups: []*transaction.ScriptGroup{group}}
+	assert.NoError(t, verifier.Verify(txWithGroups))
+
+	group.InputIndices = []uint32{1}
+	var groupErr *ScriptGroupError
+	assert.True(t, errors.As(verifier.Verify(txWithGroups), &groupErr))
+	assert.Equal(t, 1, groupErr.Index)
+}
+
+func TestVerifyStructure(t *testing.T) {
+	provider, tx := newTestCase()
+	verifier := NewVerifier(provider, nil)
+
+	tx.Witnesses = [][]byte{}
+	var witnessErr *WitnessCountError
+	assert.True(t, errors.As(verifier.VerifyTransaction(tx), &witnessErr))
+
+	_, tx = newTestCase()
+	tx.OutputsData = [][]byte{{}}
+	var dataErr *OutputsDataMismatchError
+	assert.True(t, errors.As(verifier.VerifyTransaction(tx), &dataErr))
+
+	_, tx = newTestCase()
+	tx.Inputs = append(tx.Inputs, &types.CellInput{PreviousOutput: outPoint(3, 1)})
+	tx.Witnesses = append(tx.Witnesses, []byte{})
+	var inputErr *UnresolvedInputError
+	assert.True(t, errors.As(verifier.VerifyTransaction(tx), &inputErr))
+	assert.Equal(t, 1, inputErr.Index)
+
+	_, tx = newTestCase()
+	tx.Inputs = append(tx.Inputs, &types.CellInput{PreviousOutput: outPoint(1, 0)})
+	tx.Witnesses = append(tx.Witnesses, []byte{})
+	var duplicateErr *DuplicateInputError
+	assert.True(t, errors.As(verifier.VerifyTransaction(tx), &duplicateErr))
+	assert.Equal(t, 0, duplicateErr.Previous)
+}
+
+func TestVerifyCapacity(t *testing.T) {
+	provider, tx := newTestCase()
+	verifier := NewVerifier(provider, nil)
+
+	tx.Outputs[0].Capacity = 6099999999
+	var occupiedErr *InsufficientOutputCapacityError
+	assert.True(t, errors.As(verifier.VerifyTransaction(tx), &occupiedErr))
+	assert.Equal(t, 0, occupiedErr.Index)
+	assert.Equal(t, uint64(6100000000), occupiedErr.Occupied)
+
+	_, tx = newTestCase()
+	tx.Outputs[1].Capacity = 13900000001
+	var balanceErr *UnbalancedCapacityError
+	assert.True(t, errors.As(verifier.VerifyTransaction(tx), &balanceErr))
+	assert.Equal(t, uint64(20000000000), balanceErr.InputsCapacity)
+}
+
+func TestVerifyDeps(t *testing.T) {
+	provider, tx := newTestCase()
+	verifier := NewVerifier(provider, nil)
+
+	tx.HeaderDeps = append(tx.HeaderDeps, types.HexToHash("0x02"))
+	var headerErr *UnresolvedHeaderDepError
+	assert.True(t, errors.As(verifier.VerifyTransaction(tx), &headerErr))
+	assert.Equal(t, 1, headerErr.Index)
+
+	// dep group pointing to one known and one unknown cell
+	data := make([]byte, 4, 4+2*36)
+	binary.LittleEndian.PutUint32(data, 2)
+	for _, op := range []*types.OutPoint{outPoint(2, 0), outPoint(4, 0)} {
+		data = append(data, op.TxHash.Bytes()...)
+		index := make([]byte, 4)
+		binary.LittleEndian.PutUint32(index, op.Index)
+		data = append(data, index...)
+	}
+	provider.AddCell(outPoint(3, 0), &ResolvedCell{Output: &types.CellOutput{Capacity: 0, Lock: lock}, Data: data})
+	_, tx = newTestCase()
+	tx.CellDeps = append(tx.CellDeps, &types.CellDep{OutPoint: outPoint(3, 0), DepType: types.DepTypeDepGroup})
+	var depErr *UnresolvedCellDepError
+	assert.True(t, errors.As(verifier.VerifyTransaction(tx), &depErr))
+	assert.Equal(t, 1, depErr.Index)
+	assert.Equal(t, 1, depErr.GroupIndex)
+
+	provider.AddCell(outPoint(3, 0), &ResolvedCell{Output: &types.CellOutput{Capacity: 0, Lock: lock}, Data: data[:40]})
+	var groupErr *InvalidDepGroupError
+	assert.True(t, errors.As(verifier.VerifyTransaction(tx), &groupErr))
+}
+
+func TestVerifySince(t *testing.T) {
+	provider, tx := newTestCase()
+	chain := &ChainState{
+		TipNumber:       200,
+		TipEpoch:        (&types.EpochParams{Length: 1000, Index: 400, Number: 16}).Uint64(),
+		MedianTimestamp: 1600000100000,
+	}
+	verifier := NewVerifier(provider, chain)
+	tests := []struct {
+		since  uint64
+		mature bool
+	}{
+		{uint64(numeric.NewSinceFromAbsoluteBlockNumber(200)), true},
+		{uint64(numeric.NewSinceFromAbsoluteBlockNumber(201)), false},
+		{uint64(numeric.NewSinceFromRelativeBlockNumber(100)), true},
+		{uint64(numeric.NewSinceFromRelativeBlockNumber(101)), false},
+		{uint64(numeric.NewSinceFromRelativeEpochNumber(5)), true},
+		{uint64(numeric.NewSinceFromRelativeEpochNumber(6)), false},
+		{uint64(numeric.NewSinceFromAbsoluteEpochNumber(16)), true},
+		{uint64(numeric.NewSinceFromAbsoluteEpochNumber(17)), false},
+		{uint64(numeric.NewSinceFromRelativeTimestamp(100)), true},
+		{uint64(numeric.NewSinceFromRelativeTimestamp(101)), false},
+	}
+	for _, tt := range tests {
+		tx.Inputs[0].Since = tt.since
+		err := verifier.VerifyTransaction(tx)
+		if tt.mature {
+			assert.NoError(t, err, "since %#x", tt.since)
+		} else {
+			var sinceErr *ImmatureSinceError
+			assert.True(t, errors.As(err, &sinceErr), "since %#x", tt.since)
+		}
+	}
+
+	tx.Inputs[0].Since = 0x6000000000000000
+	var invalidErr *InvalidSinceError
+	assert.True(t, errors.As(verifier.VerifyTransaction(tx), &invalidErr))
+}
@@ -13,3 +13,41 @@ type BatchLiveCellItem struct {
 	Result   *CellWithStatus
 	Error    error
 }
+
+type BatchHeaderItem struct {
+	Result *Header
+	Error  error
+}
+
+type BatchBlockItem struct {
+	Result *Block
+	Error  error
+}
+
+type BatchHashItem struct {
+	Result *Hash
+	Error  error
+}
+
+type BatchEpochItem struct {
+	Result *Epoch
+	Error  error
+}
+
+type BatchBlockEconomicStateItem struct {
+	Result *BlockEconomicState
+	Error  error
+}
+
+type BatchTransactionProofItem struct {
+	Result *TransactionProof
+	Error  error
+}
+
+// BatchCallItem is a call of any method, Result should be a pointer to be unmarshalled into
+type BatchCallItem struct {
+	Method string
+	Args   []interface{}
+	Result interface{}
+	Error  error
+}
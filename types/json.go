@@ -808,3 +808,44 @@ func (r *PackedBlock) UnmarshalJSON(input []byte) error {
 	}
 	return nil
 }
+
+func (r *PoolTransactionEntry) UnmarshalJSON(input []byte) error {
+	var jsonObj struct {
+		Transaction *Transaction   `json:"transaction"`
+		Cycles      hexutil.Uint64 `json:"cycles"`
+		Size        hexutil.Uint64 `json:"size"`
+		Fee         hexutil.Uint64 `json:"fee"`
+		Timestamp   hexutil.Uint64 `json:"timestamp"`
+	}
+	if err := json.Unmarshal(input, &jsonObj); err != nil {
+		return err
+	}
+	*r = PoolTransactionEntry{
+		Transaction: jsonObj.Transaction,
+		Cycles:      uint64(jsonObj.Cycles),
+		Size:        uint64(jsonObj.Size),
+		Fee:         uint64(jsonObj.Fee),
+		Timestamp:   uint64(jsonObj.Timestamp),
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes the tuple of entry and reject published to topic rejected_transaction
+func (r *PoolTransactionRejection) UnmarshalJSON(input []byte) error {
+	var jsonObj []json.RawMessage
+	if err := json.Unmarshal(input, &jsonObj); err != nil {
+		return err
+	}
+	if len(jsonObj) != 2 {
+		return fmt.Errorf("invalid rejected transaction length %d", len(jsonObj))
+	}
+	var result PoolTransactionRejection
+	if err := json.Unmarshal(jsonObj[0], &result.Entry); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(jsonObj[1], &result.Reject); err != nil {
+		return err
+	}
+	*r = result
+	return nil
+}
@@ -16,3 +16,23 @@ type RawTxPool struct {
 	Pending  []Hash `json:"pending"`
 	Proposed []Hash `json:"proposed"`
 }
+
+// PoolTransactionEntry is a transaction entering or proposed in the pool, which is published to subscribers
+type PoolTransactionEntry struct {
+	Transaction *Transaction `json:"transaction"`
+	Cycles      uint64       `json:"cycles"`
+	Size        uint64       `json:"size"`
+	Fee         uint64       `json:"fee"`
+	Timestamp   uint64       `json:"timestamp"`
+}
+
+type PoolTransactionReject struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+// PoolTransactionRejection is a transaction rejected by the pool and the reason
+type PoolTransactionRejection struct {
+	Entry  *PoolTransactionEntry
+	Reject *PoolTransactionReject
+}
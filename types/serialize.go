@@ -1,11 +1,14 @@
 package types
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"github.com/nervosnetwork/ckb-sdk-go/v2/types/molecule"
 )
 
+const outPointSize = 36
+
 func SerializeUint32(n uint32) []byte {
 	return PackUint32(n).AsSlice()
 }
@@ -95,3 +98,41 @@ func DeserializeWitnessArgs(in []byte) (*WitnessArgs, error) {
 	}
 	return UnpackWitnessArgs(m), nil
 }
+
+func DeserializeScript(in []byte) (*Script, error) {
+	m, err := molecule.ScriptFromSlice(in, false)
+	if err != nil {
+		return nil, err
+	}
+	return UnpackScript(m), nil
+}
+
+// SerializeOutPointVec serializes out points into a molecule fixvec of OutPoint, which is the data of dep group cell
+func SerializeOutPointVec(outPoints []*OutPoint) []byte {
+	out := make([]byte, 0, 4+outPointSize*len(outPoints))
+	out = append(out, SerializeUint32(uint32(len(outPoints)))...)
+	for _, outPoint := range outPoints {
+		out = append(out, outPoint.Serialize()...)
+	}
+	return out
+}
+
+// DeserializeOutPointVec parses a molecule fixvec of OutPoint, which is the data of dep group cell
+func DeserializeOutPointVec(in []byte) ([]*OutPoint, error) {
+	if len(in) < 4 {
+		return nil, fmt.Errorf("invalid out point vec length %d", len(in))
+	}
+	n := binary.LittleEndian.Uint32(in)
+	if uint64(len(in)-4) != uint64(n)*outPointSize {
+		return nil, fmt.Errorf("invalid out point vec length %d", len(in))
+	}
+	outPoints := make([]*OutPoint, n)
+	for i := range outPoints {
+		m, err := molecule.OutPointFromSlice(in[4+outPointSize*i:4+outPointSize*(i+1)], false)
+		if err != nil {
+			return nil, err
+		}
+		outPoints[i] = UnpackOutPoint(m)
+	}
+	return outPoints, nil
+}
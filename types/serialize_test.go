@@ -27,3 +27,24 @@ func TestDeserializeWitnessArgs(t *testing.T) {
 	assert.Nil(t, witnessArgs.InputType)
 	assert.Nil(t, witnessArgs.OutputType)
 }
+
+func TestOutPointVec(t *testing.T) {
+	outPoints := []*OutPoint{
+		{TxHash: HexToHash("0x71a7ba8fc96349fea0ed3a5c47992e3b4084b031a42264a018e0072e8172e46c"), Index: 0},
+		{TxHash: HexToHash("0xe2fb199810d49a4d8beec56718ba2593b665db9d52299a0f9e6e75416d73ff5c"), Index: 2},
+	}
+	data := SerializeOutPointVec(outPoints)
+	assert.Equal(t, 4+36*2, len(data))
+	decoded, err := DeserializeOutPointVec(data)
+	assert.NoError(t, err)
+	assert.Equal(t, outPoints, decoded)
+
+	decoded, err = DeserializeOutPointVec(SerializeOutPointVec(nil))
+	assert.NoError(t, err)
+	assert.Empty(t, decoded)
+
+	_, err = DeserializeOutPointVec(data[:len(data)-1])
+	assert.Error(t, err)
+	_, err = DeserializeOutPointVec([]byte{1, 0})
+	assert.Error(t, err)
+}